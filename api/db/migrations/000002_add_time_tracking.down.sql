@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS time_entries_running_user_id_idx;
+DROP TABLE IF EXISTS time_entries;
+ALTER TABLE todos DROP COLUMN IF EXISTS estimated_duration;
@@ -0,0 +1,17 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS estimated_duration INTEGER;
+
+CREATE TABLE IF NOT EXISTS time_entries(
+    id TEXT PRIMARY KEY,
+    todo_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    stopped_at TIMESTAMP,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- A user can have only one running timer at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_user_id_idx
+ON time_entries(user_id) WHERE stopped_at IS NULL;
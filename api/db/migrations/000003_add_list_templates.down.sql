@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS template_todos;
+DROP TABLE IF EXISTS list_templates;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS list_templates(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- due_offset is the number of minutes from the start date given when a list
+-- is created from the template.
+CREATE TABLE IF NOT EXISTS template_todos(
+    id TEXT PRIMARY KEY,
+    template_id TEXT NOT NULL,
+    parent_id TEXT,
+    title TEXT NOT NULL,
+    description TEXT,
+    due_offset INTEGER,
+    estimated_duration INTEGER,
+    FOREIGN KEY (template_id) REFERENCES list_templates(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES template_todos(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+ALTER TABLE lists DROP COLUMN IF EXISTS folder_id;
+DROP TABLE IF EXISTS folder_shares;
+DROP TABLE IF EXISTS folders;
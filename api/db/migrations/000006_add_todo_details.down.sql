@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS todos_tags_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS recurrence;
+ALTER TABLE todos DROP COLUMN IF EXISTS tags;
+ALTER TABLE todos DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,7 @@
+-- Priority goes from 0 (none) to 3 (high).
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+-- RFC 5545 recurrence rule without the "RRULE:" prefix.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS recurrence TEXT;
+
+CREATE INDEX IF NOT EXISTS todos_tags_idx ON todos USING GIN (tags);
@@ -0,0 +1,25 @@
+-- All-day due dates become due at the start of the day in UTC.
+UPDATE todos SET complete_before = due_date::timestamp AT TIME ZONE 'UTC'
+WHERE due_date IS NOT NULL;
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_due_check;
+ALTER TABLE todos DROP COLUMN IF EXISTS due_date;
+
+ALTER TABLE folders ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE folders ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE list_templates ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE list_templates ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE time_entries ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE time_entries ALTER COLUMN stopped_at TYPE TIMESTAMP USING stopped_at AT TIME ZONE 'UTC';
+ALTER TABLE time_entries ALTER COLUMN started_at TYPE TIMESTAMP USING started_at AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN completed_at TYPE TIMESTAMP USING completed_at AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN complete_before TYPE TIMESTAMP USING complete_before AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE lists ALTER COLUMN archived_at TYPE TIMESTAMP USING archived_at AT TIME ZONE 'UTC';
+ALTER TABLE lists ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE lists ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE jwt_tokens ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE 'UTC';
+ALTER TABLE jwt_tokens ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE users ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS user_preferences(
+    user_id TEXT PRIMARY KEY,
+    time_zone TEXT NOT NULL DEFAULT 'UTC',
+    -- 0 is Sunday, 1 is Monday and so on.
+    week_start SMALLINT NOT NULL DEFAULT 1,
+    locale TEXT NOT NULL DEFAULT 'en-US',
+    date_format TEXT NOT NULL DEFAULT 'YYYY-MM-DD',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Every timestamp so far was written in UTC.
+ALTER TABLE users ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE jwt_tokens ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE jwt_tokens ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC';
+ALTER TABLE lists ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE lists ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE lists ALTER COLUMN archived_at TYPE TIMESTAMPTZ USING archived_at AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN complete_before TYPE TIMESTAMPTZ USING complete_before AT TIME ZONE 'UTC';
+ALTER TABLE todos ALTER COLUMN completed_at TYPE TIMESTAMPTZ USING completed_at AT TIME ZONE 'UTC';
+ALTER TABLE time_entries ALTER COLUMN started_at TYPE TIMESTAMPTZ USING started_at AT TIME ZONE 'UTC';
+ALTER TABLE time_entries ALTER COLUMN stopped_at TYPE TIMESTAMPTZ USING stopped_at AT TIME ZONE 'UTC';
+ALTER TABLE time_entries ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE list_templates ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE list_templates ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE folders ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE folders ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+
+-- An all-day due date. A todo is due either on a date or at a date-time.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_date DATE;
+ALTER TABLE todos ADD CONSTRAINT todos_due_check CHECK (due_date IS NULL OR complete_before IS NULL);
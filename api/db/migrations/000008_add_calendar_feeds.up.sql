@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS calendar_feeds(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    -- A feed of a single list, or of every list of the user if NULL.
+    list_id TEXT,
+    token_hash TEXT NOT NULL UNIQUE,
+    include_events BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (list_id) REFERENCES lists(id) ON DELETE CASCADE
+);
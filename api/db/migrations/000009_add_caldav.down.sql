@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS todos_list_id_ical_name_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS ical_name;
+ALTER TABLE todos DROP COLUMN IF EXISTS ical_uid;
+
+DROP TABLE IF EXISTS app_passwords;
@@ -0,0 +1,18 @@
+-- Passwords for clients that cannot use jwts, like CalDAV clients.
+CREATE TABLE IF NOT EXISTS app_passwords(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    password_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- The UID and resource name a CalDAV client gave a todo. Todos without them
+-- use their id as UID and "<id>.ics" as name.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS ical_uid TEXT;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS ical_name TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS todos_list_id_ical_name_idx
+ON todos(list_id, ical_name) WHERE ical_name IS NOT NULL;
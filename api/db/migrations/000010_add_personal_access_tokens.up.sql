@@ -0,0 +1,12 @@
+-- Long-lived bearer tokens for scripts, used instead of access jwts.
+CREATE TABLE IF NOT EXISTS personal_access_tokens(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Tokens without expiry are valid until deleted.
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
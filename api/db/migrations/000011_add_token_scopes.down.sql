@@ -0,0 +1,5 @@
+ALTER TABLE personal_access_tokens
+DROP COLUMN IF EXISTS list_ids;
+
+ALTER TABLE personal_access_tokens
+DROP COLUMN IF EXISTS scopes;
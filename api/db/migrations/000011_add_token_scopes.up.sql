@@ -0,0 +1,7 @@
+-- Existing tokens keep the full access they had.
+ALTER TABLE personal_access_tokens
+ADD COLUMN scopes TEXT[] NOT NULL DEFAULT '{lists:read,lists:write,todos:write,users:admin}';
+
+-- Tokens without list ids can access every list of the user.
+ALTER TABLE personal_access_tokens
+ADD COLUMN list_ids TEXT[];
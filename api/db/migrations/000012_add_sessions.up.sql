@@ -0,0 +1,18 @@
+-- One session per refresh token family, shown to the user so that they can
+-- log out devices.
+CREATE TABLE IF NOT EXISTS sessions(
+    family TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Families from before sessions were recorded, without user agent or ip.
+INSERT INTO sessions (family, user_id, created_at, last_used_at)
+SELECT family, user_id, MIN(created_at), MAX(created_at)
+FROM jwt_tokens
+GROUP BY family, user_id
+ON CONFLICT (family) DO NOTHING;
@@ -0,0 +1,13 @@
+-- Revokes access jwts before they expire. Kept without a foreign key, so that
+-- the jwts of deleted users stay revoked.
+CREATE TABLE IF NOT EXISTS token_revocations(
+    user_id TEXT NOT NULL,
+    -- Revokes the jwts of this session, or every jwt of the user issued before
+    -- revoked_at if null.
+    family TEXT,
+    revoked_at TIMESTAMPTZ NOT NULL,
+    -- When every revoked jwt has expired and the row can be removed.
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS token_revocations_expires_at_idx ON token_revocations(expires_at);
@@ -0,0 +1,16 @@
+-- Keys for signing jwts with EdDSA or RS256, shared by every instance of the
+-- api. The newest active key signs, the others only verify.
+CREATE TABLE IF NOT EXISTS jwt_signing_keys(
+    kid TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    -- PKCS #8 and PKIX DER.
+    private_key BYTEA NOT NULL,
+    public_key BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Signing starts later than creation, so that every instance has loaded
+    -- the key before jwts signed with it are seen.
+    active_from TIMESTAMPTZ NOT NULL,
+    -- Set when a newer key takes over, after which every jwt signed with this
+    -- key has expired.
+    verify_until TIMESTAMPTZ
+);
@@ -0,0 +1,32 @@
+-- TOTP secrets of users who enrolled in two-factor authentication. Login only
+-- asks for a code once the secret is enabled.
+CREATE TABLE IF NOT EXISTS user_totp(
+    user_id TEXT PRIMARY KEY,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The step of the last accepted code, so that a code cannot be used twice.
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    enabled_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Single use codes for logging in without the authenticator.
+CREATE TABLE IF NOT EXISTS recovery_codes(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    code_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_id_idx ON recovery_codes(user_id);
+
+-- Logins waiting for the second factor after the password was correct.
+CREATE TABLE IF NOT EXISTS login_challenges(
+    token_hash TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+-- Optional, reset links for forgotten passwords are sent to it.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_idx ON users(LOWER(email));
+
+-- Tokens of emailed reset links. Used once, and only the newest one of a user
+-- is kept.
+CREATE TABLE IF NOT EXISTS password_reset_tokens(
+    token_hash TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS password_reset_tokens_user_id_idx ON password_reset_tokens(user_id);
@@ -0,0 +1,9 @@
+-- Failed logins counted per username and per ip, shared by every instance of
+-- the api. key is "username:<username>" or "ip:<ip>".
+CREATE TABLE IF NOT EXISTS login_failures(
+    key TEXT PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Logins are refused until then.
+    locked_until TIMESTAMPTZ
+);
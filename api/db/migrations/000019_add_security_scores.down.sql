@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ip_blocks;
+DROP TABLE IF EXISTS security_actions;
+DROP TABLE IF EXISTS security_scores;
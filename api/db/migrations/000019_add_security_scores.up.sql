@@ -0,0 +1,35 @@
+-- Scores of security events by violator, summed over a rolling window to
+-- decide when rules fire. violator_type is user, with the user id as
+-- violator, or ip.
+CREATE TABLE IF NOT EXISTS security_scores(
+    id BIGSERIAL PRIMARY KEY,
+    violator_type TEXT NOT NULL,
+    violator TEXT NOT NULL,
+    event TEXT NOT NULL,
+    score INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS security_scores_violator_idx ON security_scores(violator_type, violator, created_at);
+CREATE INDEX IF NOT EXISTS security_scores_created_at_idx ON security_scores(created_at);
+
+-- Actions fired by rules, kept for admins to review.
+CREATE TABLE IF NOT EXISTS security_actions(
+    id BIGSERIAL PRIMARY KEY,
+    violator_type TEXT NOT NULL,
+    violator TEXT NOT NULL,
+    rule TEXT NOT NULL,
+    action TEXT NOT NULL,
+    score INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS security_actions_created_at_idx ON security_actions(created_at);
+
+-- Ips that get no response but 403 until blocked_until.
+CREATE TABLE IF NOT EXISTS ip_blocks(
+    ip TEXT PRIMARY KEY,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    blocked_until TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,36 @@
+-- Audit, session, token, object and security events, kept apart from the log
+-- files so that rotation does not delete them. Each entry stores the hash of
+-- the one before, so that changing or removing an entry breaks the chain from
+-- there on.
+CREATE TABLE IF NOT EXISTS audit_log(
+    seq BIGINT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    sourcetype TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    src_ip TEXT NOT NULL,
+    target_path TEXT NOT NULL,
+    -- JSON rather than JSONB, so that the text the hash was made from is kept
+    -- as it is.
+    data JSON NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log(actor, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_event_type_idx ON audit_log(event_type, created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
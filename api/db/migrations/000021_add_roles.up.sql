@@ -0,0 +1,61 @@
+-- What roles allow beyond a user's own data. is_admin still grants every
+-- permission, roles give some of them to users that are not admins.
+CREATE TABLE IF NOT EXISTS permissions(
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS roles(
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions(
+    role TEXT NOT NULL,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission),
+    FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE,
+    FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles(
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'Read any user, their preferences and time report'),
+    ('users:manage', 'Update and delete any user and their preferences'),
+    ('sessions:read', 'Read the sessions of any user and locked logins'),
+    ('sessions:manage', 'Log out sessions of any user and unlock logins'),
+    ('lists:read', 'Read all lists and their time reports'),
+    ('lists:manage', 'Update, archive and delete any list, folder, template, time entry and calendar feed'),
+    ('audit:read', 'Read the audit log and security scores'),
+    ('security:manage', 'Lift ip blocks'),
+    ('roles:manage', 'Assign roles to users and make them admin')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (name, description) VALUES
+    ('user-manager', 'Manages users and their sessions'),
+    ('auditor', 'Reads all lists and logs without changing anything'),
+    ('support', 'Helps users with their lists and logins')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('user-manager', 'users:read'),
+    ('user-manager', 'users:manage'),
+    ('user-manager', 'sessions:read'),
+    ('user-manager', 'sessions:manage'),
+    ('auditor', 'users:read'),
+    ('auditor', 'sessions:read'),
+    ('auditor', 'lists:read'),
+    ('auditor', 'audit:read'),
+    ('support', 'users:read'),
+    ('support', 'sessions:read'),
+    ('support', 'sessions:manage'),
+    ('support', 'lists:read')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,31 @@
+-- Set when the user followed the link of the verification email. Users with
+-- an email address from before verification existed count as verified.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+UPDATE users SET email_verified_at = CURRENT_TIMESTAMP
+WHERE email IS NOT NULL AND email_verified_at IS NULL;
+
+-- Tokens of emailed verification links. Used once, and only the newest one of
+-- a user is kept.
+CREATE TABLE IF NOT EXISTS email_verification_tokens(
+    token_hash TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS email_verification_tokens_user_id_idx ON email_verification_tokens(user_id);
+
+-- Codes that let someone create an account while registration is invite-only.
+-- Only the hash of the code is stored.
+CREATE TABLE IF NOT EXISTS invite_codes(
+    id TEXT PRIMARY KEY,
+    code_hash TEXT NOT NULL UNIQUE,
+    created_by TEXT,
+    max_uses INT NOT NULL CHECK (max_uses > 0),
+    uses INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
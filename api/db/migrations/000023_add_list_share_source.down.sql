@@ -0,0 +1 @@
+ALTER TABLE list_shares DROP COLUMN IF EXISTS via_folder;
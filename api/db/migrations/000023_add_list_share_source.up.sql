@@ -0,0 +1,16 @@
+-- Whether the list is shared because a folder it is in is shared. Only these
+-- shares are removed when the folder is unshared or the list moves out of it,
+-- shares made on the list itself are kept.
+ALTER TABLE list_shares ADD COLUMN IF NOT EXISTS via_folder BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Shares that a shared folder accounts for are taken to come from it.
+WITH RECURSIVE shared AS (
+    SELECT fs.folder_id AS id, fs.user_id FROM folder_shares fs
+    UNION
+    SELECT f.id, s.user_id FROM folders f
+    JOIN shared s ON f.parent_id = s.id
+)
+UPDATE list_shares ls
+SET via_folder = TRUE
+FROM lists l, shared s
+WHERE l.id = ls.list_id AND s.id = l.folder_id AND s.user_id = ls.user_id;
@@ -0,0 +1,2 @@
+ALTER TABLE token_revocations DROP COLUMN IF EXISTS epoch;
+ALTER TABLE users DROP COLUMN IF EXISTS token_epoch;
@@ -0,0 +1,17 @@
+-- Counts the revocations of every jwt of the user. Access jwts carry the epoch
+-- they were issued in and are revoked once it is behind.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_epoch INTEGER NOT NULL DEFAULT 0;
+
+-- The epoch the user was revoked up to, jwts of earlier epochs are revoked.
+-- Unused by revocations of a session.
+ALTER TABLE token_revocations ADD COLUMN IF NOT EXISTS epoch INTEGER NOT NULL DEFAULT 0;
+
+-- Jwts issued before this carry no epoch, so the users revoked until now have
+-- all of theirs revoked.
+UPDATE users
+SET token_epoch = 1
+WHERE id IN (SELECT user_id FROM token_revocations WHERE family IS NULL);
+
+UPDATE token_revocations
+SET epoch = 1
+WHERE family IS NULL;
@@ -0,0 +1,26 @@
+-- name: CreateAppPassword :one
+INSERT INTO app_passwords (id, user_id, name, password_hash)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetAppPasswordByHash :one
+SELECT * FROM app_passwords
+WHERE password_hash = $1;
+
+-- name: GetAppPasswordsByUserId :many
+SELECT * FROM app_passwords
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: TouchAppPassword :exec
+UPDATE app_passwords
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteAppPassword :execrows
+DELETE FROM app_passwords
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteAppPasswordsByUserId :exec
+DELETE FROM app_passwords
+WHERE user_id = $1;
@@ -0,0 +1,32 @@
+-- Serializes appending to the chain across instances until the end of the
+-- transaction.
+-- name: LockAuditLog :exec
+SELECT pg_advisory_xact_lock(hashtext('audit_log'));
+
+-- name: GetLastAuditLogEntry :one
+SELECT * FROM audit_log
+ORDER BY seq DESC
+LIMIT 1;
+
+-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (seq, created_at, sourcetype, event_type, actor, src_ip, target_path, data, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- Filters that are null are not applied. target_path matches by prefix.
+-- Entries come newest first, before the before_seq cursor if it is set.
+-- name: SearchAuditLog :many
+SELECT * FROM audit_log
+WHERE (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor))
+    AND (sqlc.narg(event_type)::text IS NULL OR event_type = sqlc.narg(event_type))
+    AND (sqlc.narg(sourcetype)::text IS NULL OR sourcetype = sqlc.narg(sourcetype))
+    AND (sqlc.narg(target_path)::text IS NULL OR starts_with(target_path, sqlc.narg(target_path)))
+    AND created_at >= sqlc.arg(range_start) AND created_at < sqlc.arg(range_end)
+    AND (sqlc.narg(before_seq)::bigint IS NULL OR seq < sqlc.narg(before_seq))
+ORDER BY seq DESC
+LIMIT sqlc.arg(max_entries);
+
+-- name: GetAuditLogEntriesAfter :many
+SELECT * FROM audit_log
+WHERE seq > $1
+ORDER BY seq
+LIMIT $2;
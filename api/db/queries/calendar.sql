@@ -0,0 +1,43 @@
+-- name: CreateCalendarFeed :one
+INSERT INTO calendar_feeds (id, user_id, list_id, token_hash, include_events)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetCalendarFeed :one
+SELECT * FROM calendar_feeds
+WHERE id = $1;
+
+-- name: GetCalendarFeedByTokenHash :one
+SELECT * FROM calendar_feeds
+WHERE token_hash = $1;
+
+-- name: GetCalendarFeedsByUserId :many
+SELECT * FROM calendar_feeds
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: UpdateCalendarFeedToken :one
+UPDATE calendar_feeds
+SET token_hash = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: TouchCalendarFeed :exec
+UPDATE calendar_feeds
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteCalendarFeed :execrows
+DELETE FROM calendar_feeds
+WHERE id = $1;
+
+-- name: GetDueTodosForCalendar :many
+SELECT sqlc.embed(t), l.title AS list_title FROM todos t
+JOIN lists l ON t.list_id = l.id
+WHERE (l.user_id = sqlc.arg(user_id) OR l.id IN (
+    SELECT ls.list_id FROM list_shares ls WHERE ls.user_id = sqlc.arg(user_id)
+))
+    AND l.archived_at IS NULL
+    AND (t.complete_before IS NOT NULL OR t.due_date IS NOT NULL)
+    AND (sqlc.narg(list_id)::text IS NULL OR l.id = sqlc.narg(list_id)::text)
+ORDER BY t.created_at;
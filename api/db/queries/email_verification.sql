@@ -0,0 +1,22 @@
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- A token can only be used once, so it is deleted as it is read.
+-- name: UseEmailVerificationToken :one
+DELETE FROM email_verification_tokens
+WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id;
+
+-- name: DeleteEmailVerificationTokensByUserId :exec
+DELETE FROM email_verification_tokens
+WHERE user_id = $1;
+
+-- name: DeleteExpiredEmailVerificationTokens :exec
+DELETE FROM email_verification_tokens
+WHERE expires_at <= CURRENT_TIMESTAMP;
+
+-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND email_verified_at IS NULL;
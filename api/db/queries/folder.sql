@@ -0,0 +1,73 @@
+-- name: CreateFolder :one
+INSERT INTO folders (id, user_id, parent_id, title)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetFolder :one
+SELECT * FROM folders
+WHERE id = $1;
+
+-- name: GetFoldersByOwnerId :many
+SELECT * FROM folders
+WHERE user_id = $1
+ORDER BY title;
+
+-- name: GetFoldersSharedWithUserId :many
+WITH RECURSIVE shared AS (
+    SELECT f.id FROM folders f
+    JOIN folder_shares fs ON f.id = fs.folder_id
+    WHERE fs.user_id = $1
+    UNION
+    SELECT f.id FROM folders f
+    JOIN shared s ON f.parent_id = s.id
+)
+SELECT f.* FROM folders f
+WHERE f.id IN (SELECT id FROM shared)
+ORDER BY f.title;
+
+-- name: GetFolderSubtreeIds :many
+WITH RECURSIVE subtree AS (
+    SELECT f.id FROM folders f WHERE f.id = $1
+    UNION
+    SELECT f.id FROM folders f
+    JOIN subtree s ON f.parent_id = s.id
+)
+SELECT id FROM subtree;
+
+-- name: GetFolderAncestorIds :many
+WITH RECURSIVE ancestors AS (
+    SELECT f.id, f.parent_id FROM folders f WHERE f.id = $1
+    UNION
+    SELECT f.id, f.parent_id FROM folders f
+    JOIN ancestors a ON f.id = a.parent_id
+)
+SELECT id FROM ancestors;
+
+-- name: UpdateFolder :one
+UPDATE folders
+SET title = $1, parent_id = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $3
+RETURNING *;
+
+-- name: DeleteFolder :execrows
+DELETE FROM folders
+WHERE id = $1;
+
+-- name: CreateFolderShare :exec
+INSERT INTO folder_shares (folder_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteFolderShare :execrows
+DELETE FROM folder_shares
+WHERE folder_id = $1 AND user_id = $2;
+
+-- name: GetFolderShares :many
+SELECT fs.user_id, u.username FROM folder_shares fs
+JOIN users u ON fs.user_id = u.id
+WHERE fs.folder_id = $1
+ORDER BY u.username;
+
+-- name: GetFolderShareUserIds :many
+SELECT DISTINCT user_id FROM folder_shares
+WHERE folder_id = ANY(sqlc.arg(folder_ids)::text[]);
@@ -0,0 +1,21 @@
+-- name: CreateInviteCode :one
+INSERT INTO invite_codes (id, code_hash, created_by, max_uses, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetInviteCodes :many
+SELECT *
+FROM invite_codes
+ORDER BY created_at DESC;
+
+-- Counts a use of the code, if it has not expired or been used up. Returns no
+-- rows otherwise.
+-- name: UseInviteCode :one
+UPDATE invite_codes
+SET uses = uses + 1
+WHERE code_hash = $1 AND uses < max_uses AND expires_at > CURRENT_TIMESTAMP
+RETURNING id;
+
+-- name: DeleteInviteCode :execrows
+DELETE FROM invite_codes
+WHERE id = $1;
@@ -0,0 +1,23 @@
+-- name: CreateJwtSigningKey :one
+INSERT INTO jwt_signing_keys (kid, algorithm, private_key, public_key, active_from)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- Serializes rotations of concurrently starting instances until the end of
+-- the transaction.
+-- name: LockJwtSigningKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('jwt_signing_keys'));
+
+-- name: GetJwtSigningKeys :many
+SELECT * FROM jwt_signing_keys
+WHERE verify_until IS NULL OR verify_until > CURRENT_TIMESTAMP
+ORDER BY active_from DESC;
+
+-- name: RetireJwtSigningKeys :exec
+UPDATE jwt_signing_keys
+SET verify_until = sqlc.arg(verify_until)
+WHERE verify_until IS NULL AND kid != sqlc.arg(kid);
+
+-- name: DeleteExpiredJwtSigningKeys :exec
+DELETE FROM jwt_signing_keys
+WHERE verify_until <= CURRENT_TIMESTAMP;
@@ -13,23 +13,23 @@ WHERE l.user_id = $1 OR id IN (
 
 -- name: GetListsByOwnerId :many
 SELECT * FROM lists
-WHERE user_id = $1;
+WHERE user_id = $1 AND (sqlc.arg(include_archived)::boolean OR archived_at IS NULL);
 
 -- name: GetListsBySharedUserId :many
 SELECT l.* FROM lists l
 JOIN list_shares ls ON l.id = ls.list_id
-WHERE ls.user_id = $1;
+WHERE ls.user_id = $1 AND (sqlc.arg(include_archived)::boolean OR l.archived_at IS NULL);
 
 -- name: GetListsAccessibleByUserId :many
 SELECT l.* FROM lists l
-WHERE l.user_id = $1 OR l.id IN (
+WHERE (l.user_id = $1 OR l.id IN (
     SELECT ls.list_id FROM list_shares ls WHERE ls.user_id = $1
-);
+)) AND (sqlc.arg(include_archived)::boolean OR l.archived_at IS NULL);
 
 -- name: CreateList :one
 INSERT INTO lists (id, user_id, title, description)
 VALUES ($1, $2, $3, $4)
-RETURNING id, user_id, title, description, created_at, updated_at;
+RETURNING *;
 
 -- name: UpdateList :one
 UPDATE lists
@@ -37,10 +37,58 @@ SET title = $1, description = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $3
 RETURNING *;
 
+-- name: GetListsByFolderIds :many
+SELECT * FROM lists
+WHERE folder_id = ANY(sqlc.arg(folder_ids)::text[])
+    AND (sqlc.arg(include_archived)::boolean OR archived_at IS NULL);
+
+-- name: SetListFolder :one
+UPDATE lists
+SET folder_id = $1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+RETURNING *;
+
+-- name: ArchiveList :one
+UPDATE lists
+SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: UnarchiveList :one
+UPDATE lists
+SET archived_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteList :execrows
 DELETE FROM lists
 WHERE id = $1;
 
 -- name: DeleteListByIdWithUserId :exec
 DELETE FROM lists
-WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+WHERE id = $1 AND user_id = $2;
+
+-- Shares the list itself, which unsharing a folder does not undo.
+-- name: CreateListShare :exec
+INSERT INTO list_shares (list_id, user_id)
+SELECT l.id, sqlc.arg(user_id)::text FROM lists l
+WHERE l.id = sqlc.arg(list_id) AND l.user_id != sqlc.arg(user_id)::text
+ON CONFLICT (list_id, user_id) DO UPDATE SET via_folder = FALSE;
+
+-- Shares the list because a folder it is in is shared. Keeps a share made on
+-- the list itself as it is.
+-- name: CreateFolderListShare :exec
+INSERT INTO list_shares (list_id, user_id, via_folder)
+SELECT l.id, sqlc.arg(user_id)::text, TRUE FROM lists l
+WHERE l.id = sqlc.arg(list_id) AND l.user_id != sqlc.arg(user_id)::text
+ON CONFLICT DO NOTHING;
+
+-- Removes the shares of the list that came from folders.
+-- name: DeleteFolderListShares :exec
+DELETE FROM list_shares
+WHERE list_id = sqlc.arg(list_id) AND user_id = ANY(sqlc.arg(user_ids)::text[]) AND via_folder;
+-- name: GetListShares :many
+SELECT ls.user_id, u.username FROM list_shares ls
+JOIN users u ON ls.user_id = u.id
+WHERE ls.list_id = $1
+ORDER BY u.username;
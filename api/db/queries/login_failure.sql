@@ -0,0 +1,42 @@
+-- Counts a failure, starting over if the last one is older than
+-- reset_before.
+-- name: RecordLoginFailure :one
+INSERT INTO login_failures (key, failures, last_failure_at)
+VALUES (@key, 1, CURRENT_TIMESTAMP)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN login_failures.last_failure_at < @reset_before THEN 1
+        ELSE login_failures.failures + 1
+    END,
+    last_failure_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: LockLoginKey :exec
+UPDATE login_failures
+SET locked_until = $2
+WHERE key = $1;
+
+-- name: GetLoginLocks :many
+SELECT * FROM login_failures
+WHERE key = ANY(@keys::TEXT[]) AND locked_until > CURRENT_TIMESTAMP;
+
+-- name: GetActiveLoginLocks :many
+SELECT * FROM login_failures
+WHERE locked_until > CURRENT_TIMESTAMP
+ORDER BY locked_until DESC;
+
+-- name: DeleteLoginFailures :execrows
+DELETE FROM login_failures
+WHERE key = ANY(@keys::TEXT[]);
+
+-- name: DeleteStaleLoginFailures :exec
+DELETE FROM login_failures
+WHERE last_failure_at < $1
+    AND (locked_until IS NULL OR locked_until <= CURRENT_TIMESTAMP);
+
+-- Locks the key whether or not it has failures.
+-- name: UpsertLoginLock :exec
+INSERT INTO login_failures (key, locked_until)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE
+SET locked_until = GREATEST(login_failures.locked_until, EXCLUDED.locked_until);
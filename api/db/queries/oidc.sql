@@ -0,0 +1,33 @@
+-- name: GetUserByIdentity :one
+SELECT users.*
+FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_id)
+VALUES ($1, $2, $3);
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE issuer = $1 AND subject = $2;
+
+-- name: GetUserIdentitiesByUserId :many
+SELECT * FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: CreateOidcLoginState :exec
+INSERT INTO oidc_login_states (state_hash, code_verifier, nonce, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- A state can only be used once, so it is deleted as it is read.
+-- name: UseOidcLoginState :one
+DELETE FROM oidc_login_states
+WHERE state_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteExpiredOidcLoginStates :exec
+DELETE FROM oidc_login_states
+WHERE expires_at <= CURRENT_TIMESTAMP;
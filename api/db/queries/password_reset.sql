@@ -0,0 +1,17 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- A token can only be used once, so it is deleted as it is read.
+-- name: UsePasswordResetToken :one
+DELETE FROM password_reset_tokens
+WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id;
+
+-- name: DeletePasswordResetTokensByUserId :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1;
+
+-- name: DeleteExpiredPasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE expires_at <= CURRENT_TIMESTAMP;
@@ -0,0 +1,27 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (id, user_id, name, token_hash, expires_at, scopes, list_ids)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT sqlc.embed(t), u.username, u.is_admin FROM personal_access_tokens t
+JOIN users u ON t.user_id = u.id
+WHERE t.token_hash = $1;
+
+-- name: GetPersonalAccessTokensByUserId :many
+SELECT * FROM personal_access_tokens
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeletePersonalAccessToken :execrows
+DELETE FROM personal_access_tokens
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeletePersonalAccessTokensByUserId :exec
+DELETE FROM personal_access_tokens
+WHERE user_id = $1;
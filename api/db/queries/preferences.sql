@@ -0,0 +1,14 @@
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences
+WHERE user_id = $1;
+
+-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (user_id, time_zone, week_start, locale, date_format)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET time_zone = EXCLUDED.time_zone,
+    week_start = EXCLUDED.week_start,
+    locale = EXCLUDED.locale,
+    date_format = EXCLUDED.date_format,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
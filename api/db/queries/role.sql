@@ -0,0 +1,49 @@
+-- name: UserHasPermission :one
+SELECT EXISTS (
+    SELECT 1 FROM user_roles ur
+    JOIN role_permissions rp ON rp.role = ur.role
+    WHERE ur.user_id = $1 AND rp.permission = $2
+);
+
+-- name: GetRoles :many
+SELECT * FROM roles
+ORDER BY name;
+
+-- name: GetRole :one
+SELECT * FROM roles
+WHERE name = $1;
+
+-- name: GetRolePermissions :many
+SELECT * FROM role_permissions
+ORDER BY role, permission;
+
+-- name: GetPermissions :many
+SELECT * FROM permissions
+ORDER BY name;
+
+-- name: GetUserRoles :many
+SELECT * FROM user_roles
+WHERE user_id = $1
+ORDER BY role;
+
+-- Does nothing if the user already has the role.
+-- name: AddUserRole :execrows
+INSERT INTO user_roles (user_id, role)
+VALUES ($1, $2)
+ON CONFLICT (user_id, role) DO NOTHING;
+
+-- name: DeleteUserRole :execrows
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2;
+
+-- Returns the permissions the target has through roles that the user does not.
+-- name: GetMissingPermissions :many
+SELECT rp.permission
+FROM user_roles ur
+JOIN role_permissions rp ON rp.role = ur.role
+WHERE ur.user_id = sqlc.arg(target_id)
+EXCEPT
+SELECT rp.permission
+FROM user_roles ur
+JOIN role_permissions rp ON rp.role = ur.role
+WHERE ur.user_id = sqlc.arg(user_id);
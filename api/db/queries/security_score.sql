@@ -0,0 +1,53 @@
+-- name: CreateSecurityScore :exec
+INSERT INTO security_scores (violator_type, violator, event, score)
+VALUES ($1, $2, $3, $4);
+
+-- name: SumSecurityScore :one
+SELECT COALESCE(SUM(score), 0)::INT
+FROM security_scores
+WHERE violator_type = $1 AND violator = $2 AND created_at > $3;
+
+-- name: GetSecurityScores :many
+SELECT violator_type,
+    violator,
+    SUM(score)::INT AS score,
+    COUNT(*) AS events,
+    MAX(created_at)::TIMESTAMPTZ AS last_event_at
+FROM security_scores
+WHERE created_at > $1
+GROUP BY violator_type, violator
+ORDER BY score DESC;
+
+-- name: DeleteSecurityScoresBefore :exec
+DELETE FROM security_scores
+WHERE created_at <= $1;
+
+-- name: CreateSecurityAction :exec
+INSERT INTO security_actions (violator_type, violator, rule, action, score)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetSecurityActionsSince :many
+SELECT * FROM security_actions
+WHERE created_at > $1
+ORDER BY created_at DESC;
+
+-- Extends a block that is already in place rather than shortening it.
+-- name: BlockIp :exec
+INSERT INTO ip_blocks (ip, reason, blocked_until)
+VALUES ($1, $2, $3)
+ON CONFLICT (ip) DO UPDATE
+SET reason = EXCLUDED.reason,
+    blocked_until = GREATEST(ip_blocks.blocked_until, EXCLUDED.blocked_until);
+
+-- name: GetIpBlocks :many
+SELECT * FROM ip_blocks
+WHERE blocked_until > CURRENT_TIMESTAMP
+ORDER BY blocked_until DESC;
+
+-- name: DeleteIpBlock :execrows
+DELETE FROM ip_blocks
+WHERE ip = $1;
+
+-- name: DeleteExpiredIpBlocks :exec
+DELETE FROM ip_blocks
+WHERE blocked_until <= CURRENT_TIMESTAMP;
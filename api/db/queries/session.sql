@@ -0,0 +1,20 @@
+-- name: SaveSession :exec
+INSERT INTO sessions (family, user_id, user_agent, ip)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (family) DO UPDATE
+SET user_agent = EXCLUDED.user_agent,
+    ip = EXCLUDED.ip,
+    last_used_at = CURRENT_TIMESTAMP;
+
+-- name: GetSessionByFamily :one
+SELECT * FROM sessions
+WHERE family = $1;
+
+-- Sessions whose refresh tokens have all expired are left out.
+-- name: GetSessionsByUserId :many
+SELECT * FROM sessions s
+WHERE s.user_id = $1 AND EXISTS (
+    SELECT 1 FROM jwt_tokens t
+    WHERE t.family = s.family AND t.expires_at > CURRENT_TIMESTAMP
+)
+ORDER BY s.last_used_at DESC;
@@ -0,0 +1,32 @@
+-- name: CreateListTemplate :one
+INSERT INTO list_templates (id, user_id, title, description, is_shared)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetListTemplate :one
+SELECT * FROM list_templates
+WHERE id = $1;
+
+-- name: GetListTemplatesVisibleToUser :many
+SELECT * FROM list_templates
+WHERE user_id = $1 OR is_shared = TRUE
+ORDER BY title;
+
+-- name: UpdateListTemplate :one
+UPDATE list_templates
+SET title = $1, description = $2, is_shared = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $4
+RETURNING *;
+
+-- name: DeleteListTemplate :execrows
+DELETE FROM list_templates
+WHERE id = $1;
+
+-- name: CreateTemplateTodo :one
+INSERT INTO template_todos (id, template_id, parent_id, title, description, due_offset, estimated_duration)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetTemplateTodosByTemplateId :many
+SELECT * FROM template_todos
+WHERE template_id = $1;
@@ -0,0 +1,52 @@
+-- name: CreateTimeEntry :one
+INSERT INTO time_entries (id, todo_id, user_id, started_at, stopped_at, note)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetTimeEntryById :one
+SELECT * FROM time_entries
+WHERE id = $1;
+
+-- name: GetRunningTimeEntryByUserId :one
+SELECT * FROM time_entries
+WHERE user_id = $1 AND stopped_at IS NULL;
+
+-- name: GetTimeEntriesByTodoId :many
+SELECT * FROM time_entries
+WHERE todo_id = $1
+ORDER BY started_at;
+
+-- name: GetTimeEntriesInRange :many
+SELECT te.*, t.title AS todo_title, t.estimated_duration, l.id AS list_id, l.title AS list_title, u.username
+FROM time_entries te
+JOIN todos t ON te.todo_id = t.id
+JOIN lists l ON t.list_id = l.id
+JOIN users u ON te.user_id = u.id
+WHERE te.stopped_at IS NOT NULL
+    AND te.started_at >= sqlc.arg(range_start)
+    AND te.started_at < sqlc.arg(range_end)
+    AND (sqlc.narg(list_id)::text IS NULL OR l.id = sqlc.narg(list_id)::text)
+    AND (sqlc.narg(user_id)::text IS NULL OR te.user_id = sqlc.narg(user_id)::text)
+ORDER BY te.started_at;
+
+-- name: StopTimeEntry :one
+UPDATE time_entries
+SET stopped_at = $2
+WHERE id = $1 AND stopped_at IS NULL
+RETURNING *;
+
+-- name: StopRunningTimeEntriesByTodoId :exec
+UPDATE time_entries
+SET stopped_at = $2
+WHERE todo_id = $1 AND stopped_at IS NULL;
+
+-- name: StopRunningTimeEntriesByListId :exec
+UPDATE time_entries
+SET stopped_at = $2
+WHERE stopped_at IS NULL AND todo_id IN (
+    SELECT id FROM todos WHERE list_id = $1
+);
+
+-- name: DeleteTimeEntry :execrows
+DELETE FROM time_entries
+WHERE id = $1;
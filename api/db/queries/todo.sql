@@ -1,6 +1,6 @@
 -- name: CreateTodo :one
-INSERT INTO todos (id, list_id, user_id, parent_id, title, description, complete_before)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO todos (id, list_id, user_id, parent_id, title, description, complete_before, estimated_duration, priority, tags, recurrence, due_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING *;
 
 -- name: GetTodoByIdWithListId :one
@@ -11,6 +11,11 @@ WHERE id = $1 AND list_id = $2;
 SELECT * FROM todos
 WHERE list_id = $1;
 
+-- name: GetTodosByListOrdered :many
+SELECT * FROM todos
+WHERE list_id = $1
+ORDER BY created_at, id;
+
 -- name: GetTodosAccessibleByUserId :many
 SELECT t.* FROM todos t
 JOIN lists l ON t.list_id = l.id
@@ -24,8 +29,8 @@ WHERE list_id = ANY($1::text[]);
 
 -- name: UpdateTodo :one
 UPDATE todos
-SET title = $1, description = $2, completed = $3, complete_before = $4, updated_at = CURRENT_TIMESTAMP, completed_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE NULL END
-WHERE id = $5
+SET title = $1, description = $2, completed = $3, complete_before = $4, estimated_duration = $5, priority = $6, tags = $7, recurrence = $8, due_date = $9, updated_at = CURRENT_TIMESTAMP, completed_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE NULL END
+WHERE id = $10
 RETURNING *;
 
 -- name: DeleteTodo :exec
@@ -34,4 +39,22 @@ WHERE id = $1;
 
 -- name: DeleteTodoByIdWithListId :exec
 DELETE FROM todos
-WHERE id = $1 AND list_id = $2;
\ No newline at end of file
+WHERE id = $1 AND list_id = $2;
+-- name: GetTodoByIcalName :one
+SELECT * FROM todos
+WHERE list_id = $1 AND COALESCE(ical_name, id || '.ics') = sqlc.arg(name)::text;
+
+-- name: GetTodoByIcalUid :one
+SELECT * FROM todos
+WHERE list_id = $1 AND COALESCE(ical_uid, id) = sqlc.arg(uid)::text;
+
+-- name: SetTodoIcalNames :one
+UPDATE todos
+SET ical_uid = $1, ical_name = $2
+WHERE id = $3
+RETURNING *;
+
+-- name: ImportTodo :one
+INSERT INTO todos (id, list_id, user_id, parent_id, title, description, completed, completed_at, complete_before, due_date, estimated_duration, priority, tags, recurrence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING *;
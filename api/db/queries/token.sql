@@ -13,14 +13,26 @@ UPDATE jwt_tokens
 SET is_used = TRUE
 WHERE jti = $1;
 
+-- Deletes the session of the family with it.
 -- name: DeleteJwtTokenByFamily :execrows
-DELETE FROM jwt_tokens
-WHERE family = $1;
+WITH deleted_sessions AS (
+    DELETE FROM sessions s WHERE s.family = $1
+)
+DELETE FROM jwt_tokens t
+WHERE t.family = $1;
 
+-- Deletes the sessions of the families with them.
 -- name: DeleteJwtTokenByUserIdExcludeFamily :exec
-DELETE FROM jwt_tokens
-WHERE user_id = $1 AND family != $2;
+WITH deleted_sessions AS (
+    DELETE FROM sessions s WHERE s.user_id = $1 AND s.family != $2
+)
+DELETE FROM jwt_tokens t
+WHERE t.user_id = $1 AND t.family != $2;
 
+-- Deletes the sessions of the user with them.
 -- name: DeleteJwtTokensByUserId :exec
-DELETE FROM jwt_tokens
-WHERE user_id = $1;
\ No newline at end of file
+WITH deleted_sessions AS (
+    DELETE FROM sessions s WHERE s.user_id = $1
+)
+DELETE FROM jwt_tokens t
+WHERE t.user_id = $1;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- name: CreateTokenRevocation :exec
+INSERT INTO token_revocations (user_id, family, revoked_at, expires_at, epoch)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetTokenRevocations :many
+SELECT * FROM token_revocations
+WHERE expires_at > CURRENT_TIMESTAMP;
+
+-- name: DeleteExpiredTokenRevocations :exec
+DELETE FROM token_revocations
+WHERE expires_at <= CURRENT_TIMESTAMP;
@@ -0,0 +1,64 @@
+-- Starts over an enrolment that has not been enabled.
+-- name: SaveUserTotp :one
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = CURRENT_TIMESTAMP
+WHERE user_totp.enabled = FALSE
+RETURNING *;
+
+-- name: GetUserTotp :one
+SELECT * FROM user_totp
+WHERE user_id = $1;
+
+-- name: EnableUserTotp :exec
+UPDATE user_totp
+SET enabled = TRUE, enabled_at = CURRENT_TIMESTAMP, last_used_step = $2
+WHERE user_id = $1;
+
+-- Fails to update if the step, or a later one, has been used already.
+-- name: UseUserTotpStep :execrows
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteUserTotp :execrows
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (id, user_id, code_hash)
+VALUES ($1, $2, $3);
+
+-- name: CountRecoveryCodes :one
+SELECT COUNT(*) FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+DELETE FROM recovery_codes
+WHERE user_id = $1 AND code_hash = $2;
+
+-- name: DeleteRecoveryCodesByUserId :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateLoginChallenge :exec
+INSERT INTO login_challenges (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- Counts the attempt and returns the challenge, unless it has expired.
+-- name: AttemptLoginChallenge :one
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteLoginChallenge :exec
+DELETE FROM login_challenges
+WHERE token_hash = $1;
+
+-- name: DeleteExpiredLoginChallenges :exec
+DELETE FROM login_challenges
+WHERE expires_at <= CURRENT_TIMESTAMP;
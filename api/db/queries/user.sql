@@ -13,13 +13,15 @@ SELECT id, username, is_admin, created_at
 FROM users;
 
 -- name: CreateUser :one
-INSERT INTO users (id, username, password_hash, is_admin)
-VALUES ($1, $2, $3, $4)
+INSERT INTO users (id, username, password_hash, is_admin, email, email_verified_at)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, username, is_admin, created_at;
 
+-- A changed email address has to be verified again.
 -- name: UpdateUser :one
 UPDATE users
-SET username = $2, is_admin = $3
+SET username = $2, is_admin = $3, email = $4,
+    email_verified_at = CASE WHEN email IS DISTINCT FROM $4 THEN NULL ELSE email_verified_at END
 WHERE id = $1
 RETURNING id, username, is_admin, created_at;
 
@@ -28,6 +30,17 @@ UPDATE users
 SET password_hash = $2
 WHERE id = $1;
 
+-- name: IncrementUserTokenEpoch :one
+UPDATE users
+SET token_epoch = token_epoch + 1
+WHERE id = $1
+RETURNING token_epoch;
+
 -- name: DeleteUser :execrows
 DELETE FROM users
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetAdminEmails :many
+SELECT email::TEXT
+FROM users
+WHERE is_admin AND email IS NOT NULL;
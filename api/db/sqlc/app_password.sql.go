@@ -0,0 +1,133 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: app_password.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createAppPassword = `-- name: CreateAppPassword :one
+INSERT INTO app_passwords (id, user_id, name, password_hash)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, password_hash, created_at, last_used_at
+`
+
+type CreateAppPasswordParams struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	Name         string `json:"name"`
+	PasswordHash string `json:"password_hash"`
+}
+
+func (q *Queries) CreateAppPassword(ctx context.Context, arg CreateAppPasswordParams) (AppPassword, error) {
+	row := q.db.QueryRow(ctx, createAppPassword,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.PasswordHash,
+	)
+	var i AppPassword
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const deleteAppPassword = `-- name: DeleteAppPassword :execrows
+DELETE FROM app_passwords
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteAppPasswordParams struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+func (q *Queries) DeleteAppPassword(ctx context.Context, arg DeleteAppPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAppPassword, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAppPasswordsByUserId = `-- name: DeleteAppPasswordsByUserId :exec
+DELETE FROM app_passwords
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAppPasswordsByUserId(ctx context.Context, userID string) error {
+	_, err := q.db.Exec(ctx, deleteAppPasswordsByUserId, userID)
+	return err
+}
+
+const getAppPasswordByHash = `-- name: GetAppPasswordByHash :one
+SELECT id, user_id, name, password_hash, created_at, last_used_at FROM app_passwords
+WHERE password_hash = $1
+`
+
+func (q *Queries) GetAppPasswordByHash(ctx context.Context, passwordHash string) (AppPassword, error) {
+	row := q.db.QueryRow(ctx, getAppPasswordByHash, passwordHash)
+	var i AppPassword
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getAppPasswordsByUserId = `-- name: GetAppPasswordsByUserId :many
+SELECT id, user_id, name, password_hash, created_at, last_used_at FROM app_passwords
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetAppPasswordsByUserId(ctx context.Context, userID string) ([]AppPassword, error) {
+	rows, err := q.db.Query(ctx, getAppPasswordsByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AppPassword{}
+	for rows.Next() {
+		var i AppPassword
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.PasswordHash,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchAppPassword = `-- name: TouchAppPassword :exec
+UPDATE app_passwords
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) TouchAppPassword(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, touchAppPassword, id)
+	return err
+}
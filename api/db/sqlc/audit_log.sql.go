@@ -0,0 +1,190 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: audit_log.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuditLogEntry = `-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (seq, created_at, sourcetype, event_type, actor, src_ip, target_path, data, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type CreateAuditLogEntryParams struct {
+	Seq        int64              `json:"seq"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Sourcetype string             `json:"sourcetype"`
+	EventType  string             `json:"event_type"`
+	Actor      string             `json:"actor"`
+	SrcIp      string             `json:"src_ip"`
+	TargetPath string             `json:"target_path"`
+	Data       json.RawMessage    `json:"data"`
+	PrevHash   string             `json:"prev_hash"`
+	Hash       string             `json:"hash"`
+}
+
+func (q *Queries) CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) error {
+	_, err := q.db.Exec(ctx, createAuditLogEntry,
+		arg.Seq,
+		arg.CreatedAt,
+		arg.Sourcetype,
+		arg.EventType,
+		arg.Actor,
+		arg.SrcIp,
+		arg.TargetPath,
+		arg.Data,
+		arg.PrevHash,
+		arg.Hash,
+	)
+	return err
+}
+
+const getAuditLogEntriesAfter = `-- name: GetAuditLogEntriesAfter :many
+SELECT seq, created_at, sourcetype, event_type, actor, src_ip, target_path, data, prev_hash, hash FROM audit_log
+WHERE seq > $1
+ORDER BY seq
+LIMIT $2
+`
+
+type GetAuditLogEntriesAfterParams struct {
+	Seq   int64 `json:"seq"`
+	Limit int32 `json:"limit"`
+}
+
+func (q *Queries) GetAuditLogEntriesAfter(ctx context.Context, arg GetAuditLogEntriesAfterParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, getAuditLogEntriesAfter, arg.Seq, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditLog{}
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.Seq,
+			&i.CreatedAt,
+			&i.Sourcetype,
+			&i.EventType,
+			&i.Actor,
+			&i.SrcIp,
+			&i.TargetPath,
+			&i.Data,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastAuditLogEntry = `-- name: GetLastAuditLogEntry :one
+SELECT seq, created_at, sourcetype, event_type, actor, src_ip, target_path, data, prev_hash, hash FROM audit_log
+ORDER BY seq DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastAuditLogEntry(ctx context.Context) (AuditLog, error) {
+	row := q.db.QueryRow(ctx, getLastAuditLogEntry)
+	var i AuditLog
+	err := row.Scan(
+		&i.Seq,
+		&i.CreatedAt,
+		&i.Sourcetype,
+		&i.EventType,
+		&i.Actor,
+		&i.SrcIp,
+		&i.TargetPath,
+		&i.Data,
+		&i.PrevHash,
+		&i.Hash,
+	)
+	return i, err
+}
+
+const lockAuditLog = `-- name: LockAuditLog :exec
+SELECT pg_advisory_xact_lock(hashtext('audit_log'))
+`
+
+// Serializes appending to the chain across instances until the end of the
+// transaction.
+func (q *Queries) LockAuditLog(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockAuditLog)
+	return err
+}
+
+const searchAuditLog = `-- name: SearchAuditLog :many
+SELECT seq, created_at, sourcetype, event_type, actor, src_ip, target_path, data, prev_hash, hash FROM audit_log
+WHERE ($1::text IS NULL OR actor = $1)
+    AND ($2::text IS NULL OR event_type = $2)
+    AND ($3::text IS NULL OR sourcetype = $3)
+    AND ($4::text IS NULL OR starts_with(target_path, $4))
+    AND created_at >= $5 AND created_at < $6
+    AND ($7::bigint IS NULL OR seq < $7)
+ORDER BY seq DESC
+LIMIT $8
+`
+
+type SearchAuditLogParams struct {
+	Actor      pgtype.Text        `json:"actor"`
+	EventType  pgtype.Text        `json:"event_type"`
+	Sourcetype pgtype.Text        `json:"sourcetype"`
+	TargetPath pgtype.Text        `json:"target_path"`
+	RangeStart pgtype.Timestamptz `json:"range_start"`
+	RangeEnd   pgtype.Timestamptz `json:"range_end"`
+	BeforeSeq  pgtype.Int8        `json:"before_seq"`
+	MaxEntries int32              `json:"max_entries"`
+}
+
+// Filters that are null are not applied. target_path matches by prefix.
+// Entries come newest first, before the before_seq cursor if it is set.
+func (q *Queries) SearchAuditLog(ctx context.Context, arg SearchAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, searchAuditLog,
+		arg.Actor,
+		arg.EventType,
+		arg.Sourcetype,
+		arg.TargetPath,
+		arg.RangeStart,
+		arg.RangeEnd,
+		arg.BeforeSeq,
+		arg.MaxEntries,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditLog{}
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.Seq,
+			&i.CreatedAt,
+			&i.Sourcetype,
+			&i.EventType,
+			&i.Actor,
+			&i.SrcIp,
+			&i.TargetPath,
+			&i.Data,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,234 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: calendar.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCalendarFeed = `-- name: CreateCalendarFeed :one
+INSERT INTO calendar_feeds (id, user_id, list_id, token_hash, include_events)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, list_id, token_hash, include_events, created_at, last_used_at
+`
+
+type CreateCalendarFeedParams struct {
+	ID            string      `json:"id"`
+	UserID        string      `json:"user_id"`
+	ListID        pgtype.Text `json:"list_id"`
+	TokenHash     string      `json:"token_hash"`
+	IncludeEvents bool        `json:"include_events"`
+}
+
+func (q *Queries) CreateCalendarFeed(ctx context.Context, arg CreateCalendarFeedParams) (CalendarFeed, error) {
+	row := q.db.QueryRow(ctx, createCalendarFeed,
+		arg.ID,
+		arg.UserID,
+		arg.ListID,
+		arg.TokenHash,
+		arg.IncludeEvents,
+	)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ListID,
+		&i.TokenHash,
+		&i.IncludeEvents,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const deleteCalendarFeed = `-- name: DeleteCalendarFeed :execrows
+DELETE FROM calendar_feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCalendarFeed(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCalendarFeed, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCalendarFeed = `-- name: GetCalendarFeed :one
+SELECT id, user_id, list_id, token_hash, include_events, created_at, last_used_at FROM calendar_feeds
+WHERE id = $1
+`
+
+func (q *Queries) GetCalendarFeed(ctx context.Context, id string) (CalendarFeed, error) {
+	row := q.db.QueryRow(ctx, getCalendarFeed, id)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ListID,
+		&i.TokenHash,
+		&i.IncludeEvents,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getCalendarFeedByTokenHash = `-- name: GetCalendarFeedByTokenHash :one
+SELECT id, user_id, list_id, token_hash, include_events, created_at, last_used_at FROM calendar_feeds
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (CalendarFeed, error) {
+	row := q.db.QueryRow(ctx, getCalendarFeedByTokenHash, tokenHash)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ListID,
+		&i.TokenHash,
+		&i.IncludeEvents,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getCalendarFeedsByUserId = `-- name: GetCalendarFeedsByUserId :many
+SELECT id, user_id, list_id, token_hash, include_events, created_at, last_used_at FROM calendar_feeds
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetCalendarFeedsByUserId(ctx context.Context, userID string) ([]CalendarFeed, error) {
+	rows, err := q.db.Query(ctx, getCalendarFeedsByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CalendarFeed{}
+	for rows.Next() {
+		var i CalendarFeed
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ListID,
+			&i.TokenHash,
+			&i.IncludeEvents,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDueTodosForCalendar = `-- name: GetDueTodosForCalendar :many
+SELECT t.id, t.parent_id, t.list_id, t.user_id, t.title, t.description, t.completed, t.created_at, t.updated_at, t.complete_before, t.completed_at, t.estimated_duration, t.priority, t.tags, t.recurrence, t.due_date, t.ical_uid, t.ical_name, l.title AS list_title FROM todos t
+JOIN lists l ON t.list_id = l.id
+WHERE (l.user_id = $1 OR l.id IN (
+    SELECT ls.list_id FROM list_shares ls WHERE ls.user_id = $1
+))
+    AND l.archived_at IS NULL
+    AND (t.complete_before IS NOT NULL OR t.due_date IS NOT NULL)
+    AND ($2::text IS NULL OR l.id = $2::text)
+ORDER BY t.created_at
+`
+
+type GetDueTodosForCalendarParams struct {
+	UserID string      `json:"user_id"`
+	ListID pgtype.Text `json:"list_id"`
+}
+
+type GetDueTodosForCalendarRow struct {
+	Todo      Todo   `json:"todo"`
+	ListTitle string `json:"list_title"`
+}
+
+func (q *Queries) GetDueTodosForCalendar(ctx context.Context, arg GetDueTodosForCalendarParams) ([]GetDueTodosForCalendarRow, error) {
+	rows, err := q.db.Query(ctx, getDueTodosForCalendar, arg.UserID, arg.ListID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDueTodosForCalendarRow{}
+	for rows.Next() {
+		var i GetDueTodosForCalendarRow
+		if err := rows.Scan(
+			&i.Todo.ID,
+			&i.Todo.ParentID,
+			&i.Todo.ListID,
+			&i.Todo.UserID,
+			&i.Todo.Title,
+			&i.Todo.Description,
+			&i.Todo.Completed,
+			&i.Todo.CreatedAt,
+			&i.Todo.UpdatedAt,
+			&i.Todo.CompleteBefore,
+			&i.Todo.CompletedAt,
+			&i.Todo.EstimatedDuration,
+			&i.Todo.Priority,
+			&i.Todo.Tags,
+			&i.Todo.Recurrence,
+			&i.Todo.DueDate,
+			&i.Todo.IcalUid,
+			&i.Todo.IcalName,
+			&i.ListTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchCalendarFeed = `-- name: TouchCalendarFeed :exec
+UPDATE calendar_feeds
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) TouchCalendarFeed(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, touchCalendarFeed, id)
+	return err
+}
+
+const updateCalendarFeedToken = `-- name: UpdateCalendarFeedToken :one
+UPDATE calendar_feeds
+SET token_hash = $1
+WHERE id = $2
+RETURNING id, user_id, list_id, token_hash, include_events, created_at, last_used_at
+`
+
+type UpdateCalendarFeedTokenParams struct {
+	TokenHash string `json:"token_hash"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) UpdateCalendarFeedToken(ctx context.Context, arg UpdateCalendarFeedTokenParams) (CalendarFeed, error) {
+	row := q.db.QueryRow(ctx, updateCalendarFeedToken, arg.TokenHash, arg.ID)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ListID,
+		&i.TokenHash,
+		&i.IncludeEvents,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
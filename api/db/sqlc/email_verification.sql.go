@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: email_verification.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createEmailVerificationToken = `-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateEmailVerificationTokenParams struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    string             `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error {
+	_, err := q.db.Exec(ctx, createEmailVerificationToken, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const deleteEmailVerificationTokensByUserId = `-- name: DeleteEmailVerificationTokensByUserId :exec
+DELETE FROM email_verification_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteEmailVerificationTokensByUserId(ctx context.Context, userID string) error {
+	_, err := q.db.Exec(ctx, deleteEmailVerificationTokensByUserId, userID)
+	return err
+}
+
+const deleteExpiredEmailVerificationTokens = `-- name: DeleteExpiredEmailVerificationTokens :exec
+DELETE FROM email_verification_tokens
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredEmailVerificationTokens(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredEmailVerificationTokens)
+	return err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND email_verified_at IS NULL
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, markUserEmailVerified, id)
+	return err
+}
+
+const useEmailVerificationToken = `-- name: UseEmailVerificationToken :one
+DELETE FROM email_verification_tokens
+WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id
+`
+
+// A token can only be used once, so it is deleted as it is read.
+func (q *Queries) UseEmailVerificationToken(ctx context.Context, tokenHash string) (string, error) {
+	row := q.db.QueryRow(ctx, useEmailVerificationToken, tokenHash)
+	var user_id string
+	err := row.Scan(&user_id)
+	return user_id, err
+}
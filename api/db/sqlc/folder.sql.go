@@ -0,0 +1,328 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: folder.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createFolder = `-- name: CreateFolder :one
+INSERT INTO folders (id, user_id, parent_id, title)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, parent_id, title, created_at, updated_at
+`
+
+type CreateFolderParams struct {
+	ID       string      `json:"id"`
+	UserID   string      `json:"user_id"`
+	ParentID pgtype.Text `json:"parent_id"`
+	Title    string      `json:"title"`
+}
+
+func (q *Queries) CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error) {
+	row := q.db.QueryRow(ctx, createFolder,
+		arg.ID,
+		arg.UserID,
+		arg.ParentID,
+		arg.Title,
+	)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createFolderShare = `-- name: CreateFolderShare :exec
+INSERT INTO folder_shares (folder_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateFolderShareParams struct {
+	FolderID string `json:"folder_id"`
+	UserID   string `json:"user_id"`
+}
+
+func (q *Queries) CreateFolderShare(ctx context.Context, arg CreateFolderShareParams) error {
+	_, err := q.db.Exec(ctx, createFolderShare, arg.FolderID, arg.UserID)
+	return err
+}
+
+const deleteFolder = `-- name: DeleteFolder :execrows
+DELETE FROM folders
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFolder(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFolder, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteFolderShare = `-- name: DeleteFolderShare :execrows
+DELETE FROM folder_shares
+WHERE folder_id = $1 AND user_id = $2
+`
+
+type DeleteFolderShareParams struct {
+	FolderID string `json:"folder_id"`
+	UserID   string `json:"user_id"`
+}
+
+func (q *Queries) DeleteFolderShare(ctx context.Context, arg DeleteFolderShareParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFolderShare, arg.FolderID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getFolder = `-- name: GetFolder :one
+SELECT id, user_id, parent_id, title, created_at, updated_at FROM folders
+WHERE id = $1
+`
+
+func (q *Queries) GetFolder(ctx context.Context, id string) (Folder, error) {
+	row := q.db.QueryRow(ctx, getFolder, id)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFolderAncestorIds = `-- name: GetFolderAncestorIds :many
+WITH RECURSIVE ancestors AS (
+    SELECT f.id, f.parent_id FROM folders f WHERE f.id = $1
+    UNION
+    SELECT f.id, f.parent_id FROM folders f
+    JOIN ancestors a ON f.id = a.parent_id
+)
+SELECT id FROM ancestors
+`
+
+func (q *Queries) GetFolderAncestorIds(ctx context.Context, id string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getFolderAncestorIds, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFolderShareUserIds = `-- name: GetFolderShareUserIds :many
+SELECT DISTINCT user_id FROM folder_shares
+WHERE folder_id = ANY($1::text[])
+`
+
+func (q *Queries) GetFolderShareUserIds(ctx context.Context, folderIds []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getFolderShareUserIds, folderIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var user_id string
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFolderShares = `-- name: GetFolderShares :many
+SELECT fs.user_id, u.username FROM folder_shares fs
+JOIN users u ON fs.user_id = u.id
+WHERE fs.folder_id = $1
+ORDER BY u.username
+`
+
+type GetFolderSharesRow struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) GetFolderShares(ctx context.Context, folderID string) ([]GetFolderSharesRow, error) {
+	rows, err := q.db.Query(ctx, getFolderShares, folderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetFolderSharesRow{}
+	for rows.Next() {
+		var i GetFolderSharesRow
+		if err := rows.Scan(&i.UserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFolderSubtreeIds = `-- name: GetFolderSubtreeIds :many
+WITH RECURSIVE subtree AS (
+    SELECT f.id FROM folders f WHERE f.id = $1
+    UNION
+    SELECT f.id FROM folders f
+    JOIN subtree s ON f.parent_id = s.id
+)
+SELECT id FROM subtree
+`
+
+func (q *Queries) GetFolderSubtreeIds(ctx context.Context, id string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getFolderSubtreeIds, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFoldersByOwnerId = `-- name: GetFoldersByOwnerId :many
+SELECT id, user_id, parent_id, title, created_at, updated_at FROM folders
+WHERE user_id = $1
+ORDER BY title
+`
+
+func (q *Queries) GetFoldersByOwnerId(ctx context.Context, userID string) ([]Folder, error) {
+	rows, err := q.db.Query(ctx, getFoldersByOwnerId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Folder{}
+	for rows.Next() {
+		var i Folder
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ParentID,
+			&i.Title,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFoldersSharedWithUserId = `-- name: GetFoldersSharedWithUserId :many
+WITH RECURSIVE shared AS (
+    SELECT f.id FROM folders f
+    JOIN folder_shares fs ON f.id = fs.folder_id
+    WHERE fs.user_id = $1
+    UNION
+    SELECT f.id FROM folders f
+    JOIN shared s ON f.parent_id = s.id
+)
+SELECT f.id, f.user_id, f.parent_id, f.title, f.created_at, f.updated_at FROM folders f
+WHERE f.id IN (SELECT id FROM shared)
+ORDER BY f.title
+`
+
+func (q *Queries) GetFoldersSharedWithUserId(ctx context.Context, userID string) ([]Folder, error) {
+	rows, err := q.db.Query(ctx, getFoldersSharedWithUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Folder{}
+	for rows.Next() {
+		var i Folder
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ParentID,
+			&i.Title,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFolder = `-- name: UpdateFolder :one
+UPDATE folders
+SET title = $1, parent_id = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $3
+RETURNING id, user_id, parent_id, title, created_at, updated_at
+`
+
+type UpdateFolderParams struct {
+	Title    string      `json:"title"`
+	ParentID pgtype.Text `json:"parent_id"`
+	ID       string      `json:"id"`
+}
+
+func (q *Queries) UpdateFolder(ctx context.Context, arg UpdateFolderParams) (Folder, error) {
+	row := q.db.QueryRow(ctx, updateFolder, arg.Title, arg.ParentID, arg.ID)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
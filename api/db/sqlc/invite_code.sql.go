@@ -0,0 +1,110 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: invite_code.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createInviteCode = `-- name: CreateInviteCode :one
+INSERT INTO invite_codes (id, code_hash, created_by, max_uses, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, code_hash, created_by, max_uses, uses, created_at, expires_at
+`
+
+type CreateInviteCodeParams struct {
+	ID        string             `json:"id"`
+	CodeHash  string             `json:"code_hash"`
+	CreatedBy pgtype.Text        `json:"created_by"`
+	MaxUses   int32              `json:"max_uses"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateInviteCode(ctx context.Context, arg CreateInviteCodeParams) (InviteCode, error) {
+	row := q.db.QueryRow(ctx, createInviteCode,
+		arg.ID,
+		arg.CodeHash,
+		arg.CreatedBy,
+		arg.MaxUses,
+		arg.ExpiresAt,
+	)
+	var i InviteCode
+	err := row.Scan(
+		&i.ID,
+		&i.CodeHash,
+		&i.CreatedBy,
+		&i.MaxUses,
+		&i.Uses,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const deleteInviteCode = `-- name: DeleteInviteCode :execrows
+DELETE FROM invite_codes
+WHERE id = $1
+`
+
+func (q *Queries) DeleteInviteCode(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteInviteCode, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getInviteCodes = `-- name: GetInviteCodes :many
+SELECT id, code_hash, created_by, max_uses, uses, created_at, expires_at
+FROM invite_codes
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetInviteCodes(ctx context.Context) ([]InviteCode, error) {
+	rows, err := q.db.Query(ctx, getInviteCodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InviteCode{}
+	for rows.Next() {
+		var i InviteCode
+		if err := rows.Scan(
+			&i.ID,
+			&i.CodeHash,
+			&i.CreatedBy,
+			&i.MaxUses,
+			&i.Uses,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const useInviteCode = `-- name: UseInviteCode :one
+UPDATE invite_codes
+SET uses = uses + 1
+WHERE code_hash = $1 AND uses < max_uses AND expires_at > CURRENT_TIMESTAMP
+RETURNING id
+`
+
+// Counts a use of the code, if it has not expired or been used up. Returns no
+// rows otherwise.
+func (q *Queries) UseInviteCode(ctx context.Context, codeHash string) (string, error) {
+	row := q.db.QueryRow(ctx, useInviteCode, codeHash)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
@@ -0,0 +1,118 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: jwt_signing_key.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createJwtSigningKey = `-- name: CreateJwtSigningKey :one
+INSERT INTO jwt_signing_keys (kid, algorithm, private_key, public_key, active_from)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING kid, algorithm, private_key, public_key, created_at, active_from, verify_until
+`
+
+type CreateJwtSigningKeyParams struct {
+	Kid        string             `json:"kid"`
+	Algorithm  string             `json:"algorithm"`
+	PrivateKey []byte             `json:"private_key"`
+	PublicKey  []byte             `json:"public_key"`
+	ActiveFrom pgtype.Timestamptz `json:"active_from"`
+}
+
+func (q *Queries) CreateJwtSigningKey(ctx context.Context, arg CreateJwtSigningKeyParams) (JwtSigningKey, error) {
+	row := q.db.QueryRow(ctx, createJwtSigningKey,
+		arg.Kid,
+		arg.Algorithm,
+		arg.PrivateKey,
+		arg.PublicKey,
+		arg.ActiveFrom,
+	)
+	var i JwtSigningKey
+	err := row.Scan(
+		&i.Kid,
+		&i.Algorithm,
+		&i.PrivateKey,
+		&i.PublicKey,
+		&i.CreatedAt,
+		&i.ActiveFrom,
+		&i.VerifyUntil,
+	)
+	return i, err
+}
+
+const deleteExpiredJwtSigningKeys = `-- name: DeleteExpiredJwtSigningKeys :exec
+DELETE FROM jwt_signing_keys
+WHERE verify_until <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredJwtSigningKeys(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredJwtSigningKeys)
+	return err
+}
+
+const getJwtSigningKeys = `-- name: GetJwtSigningKeys :many
+SELECT kid, algorithm, private_key, public_key, created_at, active_from, verify_until FROM jwt_signing_keys
+WHERE verify_until IS NULL OR verify_until > CURRENT_TIMESTAMP
+ORDER BY active_from DESC
+`
+
+func (q *Queries) GetJwtSigningKeys(ctx context.Context) ([]JwtSigningKey, error) {
+	rows, err := q.db.Query(ctx, getJwtSigningKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []JwtSigningKey{}
+	for rows.Next() {
+		var i JwtSigningKey
+		if err := rows.Scan(
+			&i.Kid,
+			&i.Algorithm,
+			&i.PrivateKey,
+			&i.PublicKey,
+			&i.CreatedAt,
+			&i.ActiveFrom,
+			&i.VerifyUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockJwtSigningKeys = `-- name: LockJwtSigningKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('jwt_signing_keys'))
+`
+
+// Serializes rotations of concurrently starting instances until the end of
+// the transaction.
+func (q *Queries) LockJwtSigningKeys(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockJwtSigningKeys)
+	return err
+}
+
+const retireJwtSigningKeys = `-- name: RetireJwtSigningKeys :exec
+UPDATE jwt_signing_keys
+SET verify_until = $1
+WHERE verify_until IS NULL AND kid != $2
+`
+
+type RetireJwtSigningKeysParams struct {
+	VerifyUntil pgtype.Timestamptz `json:"verify_until"`
+	Kid         string             `json:"kid"`
+}
+
+func (q *Queries) RetireJwtSigningKeys(ctx context.Context, arg RetireJwtSigningKeysParams) error {
+	_, err := q.db.Exec(ctx, retireJwtSigningKeys, arg.VerifyUntil, arg.Kid)
+	return err
+}
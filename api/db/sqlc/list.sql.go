@@ -11,10 +11,52 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archiveList = `-- name: ArchiveList :one
+UPDATE lists
+SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, user_id, title, description, created_at, updated_at, archived_at, folder_id
+`
+
+func (q *Queries) ArchiveList(ctx context.Context, id string) (List, error) {
+	row := q.db.QueryRow(ctx, archiveList, id)
+	var i List
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.FolderID,
+	)
+	return i, err
+}
+
+const createFolderListShare = `-- name: CreateFolderListShare :exec
+INSERT INTO list_shares (list_id, user_id, via_folder)
+SELECT l.id, $1::text, TRUE FROM lists l
+WHERE l.id = $2 AND l.user_id != $1::text
+ON CONFLICT DO NOTHING
+`
+
+type CreateFolderListShareParams struct {
+	UserID string `json:"user_id"`
+	ListID string `json:"list_id"`
+}
+
+// Shares the list because a folder it is in is shared. Keeps a share made on
+// the list itself as it is.
+func (q *Queries) CreateFolderListShare(ctx context.Context, arg CreateFolderListShareParams) error {
+	_, err := q.db.Exec(ctx, createFolderListShare, arg.UserID, arg.ListID)
+	return err
+}
+
 const createList = `-- name: CreateList :one
 INSERT INTO lists (id, user_id, title, description)
 VALUES ($1, $2, $3, $4)
-RETURNING id, user_id, title, description, created_at, updated_at
+RETURNING id, user_id, title, description, created_at, updated_at, archived_at, folder_id
 `
 
 type CreateListParams struct {
@@ -39,10 +81,46 @@ func (q *Queries) CreateList(ctx context.Context, arg CreateListParams) (List, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.FolderID,
 	)
 	return i, err
 }
 
+const createListShare = `-- name: CreateListShare :exec
+INSERT INTO list_shares (list_id, user_id)
+SELECT l.id, $1::text FROM lists l
+WHERE l.id = $2 AND l.user_id != $1::text
+ON CONFLICT (list_id, user_id) DO UPDATE SET via_folder = FALSE
+`
+
+type CreateListShareParams struct {
+	UserID string `json:"user_id"`
+	ListID string `json:"list_id"`
+}
+
+// Shares the list itself, which unsharing a folder does not undo.
+func (q *Queries) CreateListShare(ctx context.Context, arg CreateListShareParams) error {
+	_, err := q.db.Exec(ctx, createListShare, arg.UserID, arg.ListID)
+	return err
+}
+
+const deleteFolderListShares = `-- name: DeleteFolderListShares :exec
+DELETE FROM list_shares
+WHERE list_id = $1 AND user_id = ANY($2::text[]) AND via_folder
+`
+
+type DeleteFolderListSharesParams struct {
+	ListID  string   `json:"list_id"`
+	UserIds []string `json:"user_ids"`
+}
+
+// Removes the shares of the list that came from folders.
+func (q *Queries) DeleteFolderListShares(ctx context.Context, arg DeleteFolderListSharesParams) error {
+	_, err := q.db.Exec(ctx, deleteFolderListShares, arg.ListID, arg.UserIds)
+	return err
+}
+
 const deleteList = `-- name: DeleteList :execrows
 DELETE FROM lists
 WHERE id = $1
@@ -72,7 +150,7 @@ func (q *Queries) DeleteListByIdWithUserId(ctx context.Context, arg DeleteListBy
 }
 
 const getList = `-- name: GetList :one
-SELECT id, user_id, title, description, created_at, updated_at FROM lists
+SELECT id, user_id, title, description, created_at, updated_at, archived_at, folder_id FROM lists
 WHERE id = $1
 `
 
@@ -86,6 +164,8 @@ func (q *Queries) GetList(ctx context.Context, id string) (List, error) {
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.FolderID,
 	)
 	return i, err
 }
@@ -117,8 +197,40 @@ func (q *Queries) GetListIdsAccessible(ctx context.Context, userID string) ([]st
 	return items, nil
 }
 
+const getListShares = `-- name: GetListShares :many
+SELECT ls.user_id, u.username FROM list_shares ls
+JOIN users u ON ls.user_id = u.id
+WHERE ls.list_id = $1
+ORDER BY u.username
+`
+
+type GetListSharesRow struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) GetListShares(ctx context.Context, listID string) ([]GetListSharesRow, error) {
+	rows, err := q.db.Query(ctx, getListShares, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetListSharesRow{}
+	for rows.Next() {
+		var i GetListSharesRow
+		if err := rows.Scan(&i.UserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLists = `-- name: GetLists :many
-SELECT id, user_id, title, description, created_at, updated_at FROM lists
+SELECT id, user_id, title, description, created_at, updated_at, archived_at, folder_id FROM lists
 `
 
 func (q *Queries) GetLists(ctx context.Context) ([]List, error) {
@@ -137,6 +249,8 @@ func (q *Queries) GetLists(ctx context.Context) ([]List, error) {
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.FolderID,
 		); err != nil {
 			return nil, err
 		}
@@ -149,14 +263,19 @@ func (q *Queries) GetLists(ctx context.Context) ([]List, error) {
 }
 
 const getListsAccessibleByUserId = `-- name: GetListsAccessibleByUserId :many
-SELECT l.id, l.user_id, l.title, l.description, l.created_at, l.updated_at FROM lists l
-WHERE l.user_id = $1 OR l.id IN (
+SELECT l.id, l.user_id, l.title, l.description, l.created_at, l.updated_at, l.archived_at, l.folder_id FROM lists l
+WHERE (l.user_id = $1 OR l.id IN (
     SELECT ls.list_id FROM list_shares ls WHERE ls.user_id = $1
-)
+)) AND ($2::boolean OR l.archived_at IS NULL)
 `
 
-func (q *Queries) GetListsAccessibleByUserId(ctx context.Context, userID string) ([]List, error) {
-	rows, err := q.db.Query(ctx, getListsAccessibleByUserId, userID)
+type GetListsAccessibleByUserIdParams struct {
+	UserID          string `json:"user_id"`
+	IncludeArchived bool   `json:"include_archived"`
+}
+
+func (q *Queries) GetListsAccessibleByUserId(ctx context.Context, arg GetListsAccessibleByUserIdParams) ([]List, error) {
+	rows, err := q.db.Query(ctx, getListsAccessibleByUserId, arg.UserID, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +290,48 @@ func (q *Queries) GetListsAccessibleByUserId(ctx context.Context, userID string)
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.FolderID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getListsByFolderIds = `-- name: GetListsByFolderIds :many
+SELECT id, user_id, title, description, created_at, updated_at, archived_at, folder_id FROM lists
+WHERE folder_id = ANY($1::text[])
+    AND ($2::boolean OR archived_at IS NULL)
+`
+
+type GetListsByFolderIdsParams struct {
+	FolderIds       []string `json:"folder_ids"`
+	IncludeArchived bool     `json:"include_archived"`
+}
+
+func (q *Queries) GetListsByFolderIds(ctx context.Context, arg GetListsByFolderIdsParams) ([]List, error) {
+	rows, err := q.db.Query(ctx, getListsByFolderIds, arg.FolderIds, arg.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []List{}
+	for rows.Next() {
+		var i List
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.FolderID,
 		); err != nil {
 			return nil, err
 		}
@@ -183,12 +344,17 @@ func (q *Queries) GetListsAccessibleByUserId(ctx context.Context, userID string)
 }
 
 const getListsByOwnerId = `-- name: GetListsByOwnerId :many
-SELECT id, user_id, title, description, created_at, updated_at FROM lists
-WHERE user_id = $1
+SELECT id, user_id, title, description, created_at, updated_at, archived_at, folder_id FROM lists
+WHERE user_id = $1 AND ($2::boolean OR archived_at IS NULL)
 `
 
-func (q *Queries) GetListsByOwnerId(ctx context.Context, userID string) ([]List, error) {
-	rows, err := q.db.Query(ctx, getListsByOwnerId, userID)
+type GetListsByOwnerIdParams struct {
+	UserID          string `json:"user_id"`
+	IncludeArchived bool   `json:"include_archived"`
+}
+
+func (q *Queries) GetListsByOwnerId(ctx context.Context, arg GetListsByOwnerIdParams) ([]List, error) {
+	rows, err := q.db.Query(ctx, getListsByOwnerId, arg.UserID, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -203,6 +369,8 @@ func (q *Queries) GetListsByOwnerId(ctx context.Context, userID string) ([]List,
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.FolderID,
 		); err != nil {
 			return nil, err
 		}
@@ -215,13 +383,18 @@ func (q *Queries) GetListsByOwnerId(ctx context.Context, userID string) ([]List,
 }
 
 const getListsBySharedUserId = `-- name: GetListsBySharedUserId :many
-SELECT l.id, l.user_id, l.title, l.description, l.created_at, l.updated_at FROM lists l
+SELECT l.id, l.user_id, l.title, l.description, l.created_at, l.updated_at, l.archived_at, l.folder_id FROM lists l
 JOIN list_shares ls ON l.id = ls.list_id
-WHERE ls.user_id = $1
+WHERE ls.user_id = $1 AND ($2::boolean OR l.archived_at IS NULL)
 `
 
-func (q *Queries) GetListsBySharedUserId(ctx context.Context, userID string) ([]List, error) {
-	rows, err := q.db.Query(ctx, getListsBySharedUserId, userID)
+type GetListsBySharedUserIdParams struct {
+	UserID          string `json:"user_id"`
+	IncludeArchived bool   `json:"include_archived"`
+}
+
+func (q *Queries) GetListsBySharedUserId(ctx context.Context, arg GetListsBySharedUserIdParams) ([]List, error) {
+	rows, err := q.db.Query(ctx, getListsBySharedUserId, arg.UserID, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +409,8 @@ func (q *Queries) GetListsBySharedUserId(ctx context.Context, userID string) ([]
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.FolderID,
 		); err != nil {
 			return nil, err
 		}
@@ -247,11 +422,62 @@ func (q *Queries) GetListsBySharedUserId(ctx context.Context, userID string) ([]
 	return items, nil
 }
 
+const setListFolder = `-- name: SetListFolder :one
+UPDATE lists
+SET folder_id = $1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+RETURNING id, user_id, title, description, created_at, updated_at, archived_at, folder_id
+`
+
+type SetListFolderParams struct {
+	FolderID pgtype.Text `json:"folder_id"`
+	ID       string      `json:"id"`
+}
+
+func (q *Queries) SetListFolder(ctx context.Context, arg SetListFolderParams) (List, error) {
+	row := q.db.QueryRow(ctx, setListFolder, arg.FolderID, arg.ID)
+	var i List
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.FolderID,
+	)
+	return i, err
+}
+
+const unarchiveList = `-- name: UnarchiveList :one
+UPDATE lists
+SET archived_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, user_id, title, description, created_at, updated_at, archived_at, folder_id
+`
+
+func (q *Queries) UnarchiveList(ctx context.Context, id string) (List, error) {
+	row := q.db.QueryRow(ctx, unarchiveList, id)
+	var i List
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.FolderID,
+	)
+	return i, err
+}
+
 const updateList = `-- name: UpdateList :one
 UPDATE lists
 SET title = $1, description = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $3
-RETURNING id, user_id, title, description, created_at, updated_at
+RETURNING id, user_id, title, description, created_at, updated_at, archived_at, folder_id
 `
 
 type UpdateListParams struct {
@@ -270,6 +496,8 @@ func (q *Queries) UpdateList(ctx context.Context, arg UpdateListParams) (List, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.FolderID,
 	)
 	return i, err
 }
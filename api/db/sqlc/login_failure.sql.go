@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: login_failure.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteLoginFailures = `-- name: DeleteLoginFailures :execrows
+DELETE FROM login_failures
+WHERE key = ANY($1::TEXT[])
+`
+
+func (q *Queries) DeleteLoginFailures(ctx context.Context, keys []string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLoginFailures, keys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteStaleLoginFailures = `-- name: DeleteStaleLoginFailures :exec
+DELETE FROM login_failures
+WHERE last_failure_at < $1
+    AND (locked_until IS NULL OR locked_until <= CURRENT_TIMESTAMP)
+`
+
+func (q *Queries) DeleteStaleLoginFailures(ctx context.Context, lastFailureAt pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteStaleLoginFailures, lastFailureAt)
+	return err
+}
+
+const getActiveLoginLocks = `-- name: GetActiveLoginLocks :many
+SELECT key, failures, last_failure_at, locked_until FROM login_failures
+WHERE locked_until > CURRENT_TIMESTAMP
+ORDER BY locked_until DESC
+`
+
+func (q *Queries) GetActiveLoginLocks(ctx context.Context) ([]LoginFailure, error) {
+	rows, err := q.db.Query(ctx, getActiveLoginLocks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LoginFailure{}
+	for rows.Next() {
+		var i LoginFailure
+		if err := rows.Scan(
+			&i.Key,
+			&i.Failures,
+			&i.LastFailureAt,
+			&i.LockedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLoginLocks = `-- name: GetLoginLocks :many
+SELECT key, failures, last_failure_at, locked_until FROM login_failures
+WHERE key = ANY($1::TEXT[]) AND locked_until > CURRENT_TIMESTAMP
+`
+
+func (q *Queries) GetLoginLocks(ctx context.Context, keys []string) ([]LoginFailure, error) {
+	rows, err := q.db.Query(ctx, getLoginLocks, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LoginFailure{}
+	for rows.Next() {
+		var i LoginFailure
+		if err := rows.Scan(
+			&i.Key,
+			&i.Failures,
+			&i.LastFailureAt,
+			&i.LockedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLoginKey = `-- name: LockLoginKey :exec
+UPDATE login_failures
+SET locked_until = $2
+WHERE key = $1
+`
+
+type LockLoginKeyParams struct {
+	Key         string             `json:"key"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+func (q *Queries) LockLoginKey(ctx context.Context, arg LockLoginKeyParams) error {
+	_, err := q.db.Exec(ctx, lockLoginKey, arg.Key, arg.LockedUntil)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+INSERT INTO login_failures (key, failures, last_failure_at)
+VALUES ($1, 1, CURRENT_TIMESTAMP)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN login_failures.last_failure_at < $2 THEN 1
+        ELSE login_failures.failures + 1
+    END,
+    last_failure_at = CURRENT_TIMESTAMP
+RETURNING key, failures, last_failure_at, locked_until
+`
+
+type RecordLoginFailureParams struct {
+	Key         string             `json:"key"`
+	ResetBefore pgtype.Timestamptz `json:"reset_before"`
+}
+
+// Counts a failure, starting over if the last one is older than
+// reset_before.
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginFailure, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.Key, arg.ResetBefore)
+	var i LoginFailure
+	err := row.Scan(
+		&i.Key,
+		&i.Failures,
+		&i.LastFailureAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const upsertLoginLock = `-- name: UpsertLoginLock :exec
+INSERT INTO login_failures (key, locked_until)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE
+SET locked_until = GREATEST(login_failures.locked_until, EXCLUDED.locked_until)
+`
+
+type UpsertLoginLockParams struct {
+	Key         string             `json:"key"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+// Locks the key whether or not it has failures.
+func (q *Queries) UpsertLoginLock(ctx context.Context, arg UpsertLoginLockParams) error {
+	_, err := q.db.Exec(ctx, upsertLoginLock, arg.Key, arg.LockedUntil)
+	return err
+}
@@ -5,50 +5,305 @@
 package db
 
 import (
+	"encoding/json"
+
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AppPassword struct {
+	ID           string             `json:"id"`
+	UserID       string             `json:"user_id"`
+	Name         string             `json:"name"`
+	PasswordHash string             `json:"password_hash"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt   pgtype.Timestamptz `json:"last_used_at"`
+}
+
+type AuditLog struct {
+	Seq        int64              `json:"seq"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Sourcetype string             `json:"sourcetype"`
+	EventType  string             `json:"event_type"`
+	Actor      string             `json:"actor"`
+	SrcIp      string             `json:"src_ip"`
+	TargetPath string             `json:"target_path"`
+	Data       json.RawMessage    `json:"data"`
+	PrevHash   string             `json:"prev_hash"`
+	Hash       string             `json:"hash"`
+}
+
+type CalendarFeed struct {
+	ID            string             `json:"id"`
+	UserID        string             `json:"user_id"`
+	ListID        pgtype.Text        `json:"list_id"`
+	TokenHash     string             `json:"token_hash"`
+	IncludeEvents bool               `json:"include_events"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt    pgtype.Timestamptz `json:"last_used_at"`
+}
+
+type EmailVerificationToken struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+type Folder struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	ParentID  pgtype.Text        `json:"parent_id"`
+	Title     string             `json:"title"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type FolderShare struct {
+	FolderID string `json:"folder_id"`
+	UserID   string `json:"user_id"`
+}
+
+type InviteCode struct {
+	ID        string             `json:"id"`
+	CodeHash  string             `json:"code_hash"`
+	CreatedBy pgtype.Text        `json:"created_by"`
+	MaxUses   int32              `json:"max_uses"`
+	Uses      int32              `json:"uses"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+type IpBlock struct {
+	Ip           string             `json:"ip"`
+	Reason       string             `json:"reason"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	BlockedUntil pgtype.Timestamptz `json:"blocked_until"`
+}
+
+type JwtSigningKey struct {
+	Kid         string             `json:"kid"`
+	Algorithm   string             `json:"algorithm"`
+	PrivateKey  []byte             `json:"private_key"`
+	PublicKey   []byte             `json:"public_key"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ActiveFrom  pgtype.Timestamptz `json:"active_from"`
+	VerifyUntil pgtype.Timestamptz `json:"verify_until"`
+}
+
 type JwtToken struct {
-	Jti       string           `json:"jti"`
-	Family    string           `json:"family"`
-	UserID    string           `json:"user_id"`
-	IsUsed    bool             `json:"is_used"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	Jti       string             `json:"jti"`
+	Family    string             `json:"family"`
+	UserID    string             `json:"user_id"`
+	IsUsed    bool               `json:"is_used"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
 }
 
 type List struct {
-	ID          string           `json:"id"`
-	UserID      string           `json:"user_id"`
-	Title       string           `json:"title"`
-	Description pgtype.Text      `json:"description"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	Title       string             `json:"title"`
+	Description pgtype.Text        `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ArchivedAt  pgtype.Timestamptz `json:"archived_at"`
+	FolderID    pgtype.Text        `json:"folder_id"`
 }
 
 type ListShare struct {
-	ListID string `json:"list_id"`
-	UserID string `json:"user_id"`
+	ListID    string `json:"list_id"`
+	UserID    string `json:"user_id"`
+	ViaFolder bool   `json:"via_folder"`
+}
+
+type ListTemplate struct {
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	Title       string             `json:"title"`
+	Description pgtype.Text        `json:"description"`
+	IsShared    bool               `json:"is_shared"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LoginChallenge struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    string             `json:"user_id"`
+	Attempts  int32              `json:"attempts"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+type LoginFailure struct {
+	Key           string             `json:"key"`
+	Failures      int32              `json:"failures"`
+	LastFailureAt pgtype.Timestamptz `json:"last_failure_at"`
+	LockedUntil   pgtype.Timestamptz `json:"locked_until"`
+}
+
+type OidcLoginState struct {
+	StateHash    string             `json:"state_hash"`
+	CodeVerifier string             `json:"code_verifier"`
+	Nonce        string             `json:"nonce"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type PasswordResetToken struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PersonalAccessToken struct {
+	ID         string             `json:"id"`
+	UserID     string             `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"token_hash"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	Scopes     []string           `json:"scopes"`
+	ListIds    []string           `json:"list_ids"`
+}
+
+type RecoveryCode struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Role struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+}
+
+type SecurityAction struct {
+	ID           int64              `json:"id"`
+	ViolatorType string             `json:"violator_type"`
+	Violator     string             `json:"violator"`
+	Rule         string             `json:"rule"`
+	Action       string             `json:"action"`
+	Score        int32              `json:"score"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type SecurityScore struct {
+	ID           int64              `json:"id"`
+	ViolatorType string             `json:"violator_type"`
+	Violator     string             `json:"violator"`
+	Event        string             `json:"event"`
+	Score        int32              `json:"score"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type Session struct {
+	Family     string             `json:"family"`
+	UserID     string             `json:"user_id"`
+	UserAgent  string             `json:"user_agent"`
+	Ip         string             `json:"ip"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+}
+
+type TemplateTodo struct {
+	ID                string      `json:"id"`
+	TemplateID        string      `json:"template_id"`
+	ParentID          pgtype.Text `json:"parent_id"`
+	Title             string      `json:"title"`
+	Description       pgtype.Text `json:"description"`
+	DueOffset         pgtype.Int4 `json:"due_offset"`
+	EstimatedDuration pgtype.Int4 `json:"estimated_duration"`
+}
+
+type TimeEntry struct {
+	ID        string             `json:"id"`
+	TodoID    string             `json:"todo_id"`
+	UserID    string             `json:"user_id"`
+	StartedAt pgtype.Timestamptz `json:"started_at"`
+	StoppedAt pgtype.Timestamptz `json:"stopped_at"`
+	Note      pgtype.Text        `json:"note"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Todo struct {
-	ID             string           `json:"id"`
-	ParentID       pgtype.Text      `json:"parent_id"`
-	ListID         string           `json:"list_id"`
-	UserID         string           `json:"user_id"`
-	Title          string           `json:"title"`
-	Description    pgtype.Text      `json:"description"`
-	Completed      bool             `json:"completed"`
-	CreatedAt      pgtype.Timestamp `json:"created_at"`
-	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
-	CompleteBefore pgtype.Timestamp `json:"complete_before"`
-	CompletedAt    pgtype.Timestamp `json:"completed_at"`
+	ID                string             `json:"id"`
+	ParentID          pgtype.Text        `json:"parent_id"`
+	ListID            string             `json:"list_id"`
+	UserID            string             `json:"user_id"`
+	Title             string             `json:"title"`
+	Description       pgtype.Text        `json:"description"`
+	Completed         bool               `json:"completed"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CompleteBefore    pgtype.Timestamptz `json:"complete_before"`
+	CompletedAt       pgtype.Timestamptz `json:"completed_at"`
+	EstimatedDuration pgtype.Int4        `json:"estimated_duration"`
+	Priority          int16              `json:"priority"`
+	Tags              []string           `json:"tags"`
+	Recurrence        pgtype.Text        `json:"recurrence"`
+	DueDate           pgtype.Date        `json:"due_date"`
+	IcalUid           pgtype.Text        `json:"ical_uid"`
+	IcalName          pgtype.Text        `json:"ical_name"`
+}
+
+type TokenRevocation struct {
+	UserID    string             `json:"user_id"`
+	Family    pgtype.Text        `json:"family"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Epoch     int32              `json:"epoch"`
 }
 
 type User struct {
-	ID           string           `json:"id"`
-	Username     string           `json:"username"`
-	PasswordHash string           `json:"password_hash"`
-	IsAdmin      bool             `json:"is_admin"`
-	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	ID              string             `json:"id"`
+	Username        string             `json:"username"`
+	PasswordHash    string             `json:"password_hash"`
+	IsAdmin         bool               `json:"is_admin"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	Email           pgtype.Text        `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	TokenEpoch      int32              `json:"token_epoch"`
+}
+
+type UserIdentity struct {
+	Issuer      string             `json:"issuer"`
+	Subject     string             `json:"subject"`
+	UserID      string             `json:"user_id"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	LastLoginAt pgtype.Timestamptz `json:"last_login_at"`
+}
+
+type UserPreference struct {
+	UserID     string             `json:"user_id"`
+	TimeZone   string             `json:"time_zone"`
+	WeekStart  int16              `json:"week_start"`
+	Locale     string             `json:"locale"`
+	DateFormat string             `json:"date_format"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type UserRole struct {
+	UserID    string             `json:"user_id"`
+	Role      string             `json:"role"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserTotp struct {
+	UserID       string             `json:"user_id"`
+	Secret       string             `json:"secret"`
+	Enabled      bool               `json:"enabled"`
+	LastUsedStep int64              `json:"last_used_step"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	EnabledAt    pgtype.Timestamptz `json:"enabled_at"`
 }
@@ -0,0 +1,155 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: oidc.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createOidcLoginState = `-- name: CreateOidcLoginState :exec
+INSERT INTO oidc_login_states (state_hash, code_verifier, nonce, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateOidcLoginStateParams struct {
+	StateHash    string             `json:"state_hash"`
+	CodeVerifier string             `json:"code_verifier"`
+	Nonce        string             `json:"nonce"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateOidcLoginState(ctx context.Context, arg CreateOidcLoginStateParams) error {
+	_, err := q.db.Exec(ctx, createOidcLoginState,
+		arg.StateHash,
+		arg.CodeVerifier,
+		arg.Nonce,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_id)
+VALUES ($1, $2, $3)
+`
+
+type CreateUserIdentityParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+	UserID  string `json:"user_id"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.Exec(ctx, createUserIdentity, arg.Issuer, arg.Subject, arg.UserID)
+	return err
+}
+
+const deleteExpiredOidcLoginStates = `-- name: DeleteExpiredOidcLoginStates :exec
+DELETE FROM oidc_login_states
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredOidcLoginStates(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredOidcLoginStates)
+	return err
+}
+
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+SELECT users.id, users.username, users.password_hash, users.is_admin, users.created_at, users.email, users.email_verified_at, users.token_epoch
+FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2
+`
+
+type GetUserByIdentityParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIdentity, arg.Issuer, arg.Subject)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.TokenEpoch,
+	)
+	return i, err
+}
+
+const getUserIdentitiesByUserId = `-- name: GetUserIdentitiesByUserId :many
+SELECT issuer, subject, user_id, created_at, last_login_at FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetUserIdentitiesByUserId(ctx context.Context, userID string) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, getUserIdentitiesByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserIdentity{}
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.Issuer,
+			&i.Subject,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.LastLoginAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchUserIdentity = `-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = CURRENT_TIMESTAMP
+WHERE issuer = $1 AND subject = $2
+`
+
+type TouchUserIdentityParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error {
+	_, err := q.db.Exec(ctx, touchUserIdentity, arg.Issuer, arg.Subject)
+	return err
+}
+
+const useOidcLoginState = `-- name: UseOidcLoginState :one
+DELETE FROM oidc_login_states
+WHERE state_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING state_hash, code_verifier, nonce, expires_at
+`
+
+// A state can only be used once, so it is deleted as it is read.
+func (q *Queries) UseOidcLoginState(ctx context.Context, stateHash string) (OidcLoginState, error) {
+	row := q.db.QueryRow(ctx, useOidcLoginState, stateHash)
+	var i OidcLoginState
+	err := row.Scan(
+		&i.StateHash,
+		&i.CodeVerifier,
+		&i.Nonce,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
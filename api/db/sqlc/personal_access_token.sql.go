@@ -0,0 +1,159 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: personal_access_token.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPersonalAccessToken = `-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (id, user_id, name, token_hash, expires_at, scopes, list_ids)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, name, token_hash, created_at, expires_at, last_used_at, scopes, list_ids
+`
+
+type CreatePersonalAccessTokenParams struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	Name      string             `json:"name"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Scopes    []string           `json:"scopes"`
+	ListIds   []string           `json:"list_ids"`
+}
+
+func (q *Queries) CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error) {
+	row := q.db.QueryRow(ctx, createPersonalAccessToken,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.ExpiresAt,
+		arg.Scopes,
+		arg.ListIds,
+	)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.Scopes,
+		&i.ListIds,
+	)
+	return i, err
+}
+
+const deletePersonalAccessToken = `-- name: DeletePersonalAccessToken :execrows
+DELETE FROM personal_access_tokens
+WHERE id = $1 AND user_id = $2
+`
+
+type DeletePersonalAccessTokenParams struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+func (q *Queries) DeletePersonalAccessToken(ctx context.Context, arg DeletePersonalAccessTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePersonalAccessToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePersonalAccessTokensByUserId = `-- name: DeletePersonalAccessTokensByUserId :exec
+DELETE FROM personal_access_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeletePersonalAccessTokensByUserId(ctx context.Context, userID string) error {
+	_, err := q.db.Exec(ctx, deletePersonalAccessTokensByUserId, userID)
+	return err
+}
+
+const getPersonalAccessTokenByHash = `-- name: GetPersonalAccessTokenByHash :one
+SELECT t.id, t.user_id, t.name, t.token_hash, t.created_at, t.expires_at, t.last_used_at, t.scopes, t.list_ids, u.username, u.is_admin FROM personal_access_tokens t
+JOIN users u ON t.user_id = u.id
+WHERE t.token_hash = $1
+`
+
+type GetPersonalAccessTokenByHashRow struct {
+	PersonalAccessToken PersonalAccessToken `json:"personal_access_token"`
+	Username            string              `json:"username"`
+	IsAdmin             bool                `json:"is_admin"`
+}
+
+func (q *Queries) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (GetPersonalAccessTokenByHashRow, error) {
+	row := q.db.QueryRow(ctx, getPersonalAccessTokenByHash, tokenHash)
+	var i GetPersonalAccessTokenByHashRow
+	err := row.Scan(
+		&i.PersonalAccessToken.ID,
+		&i.PersonalAccessToken.UserID,
+		&i.PersonalAccessToken.Name,
+		&i.PersonalAccessToken.TokenHash,
+		&i.PersonalAccessToken.CreatedAt,
+		&i.PersonalAccessToken.ExpiresAt,
+		&i.PersonalAccessToken.LastUsedAt,
+		&i.PersonalAccessToken.Scopes,
+		&i.PersonalAccessToken.ListIds,
+		&i.Username,
+		&i.IsAdmin,
+	)
+	return i, err
+}
+
+const getPersonalAccessTokensByUserId = `-- name: GetPersonalAccessTokensByUserId :many
+SELECT id, user_id, name, token_hash, created_at, expires_at, last_used_at, scopes, list_ids FROM personal_access_tokens
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetPersonalAccessTokensByUserId(ctx context.Context, userID string) ([]PersonalAccessToken, error) {
+	rows, err := q.db.Query(ctx, getPersonalAccessTokensByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PersonalAccessToken{}
+	for rows.Next() {
+		var i PersonalAccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.Scopes,
+			&i.ListIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchPersonalAccessToken = `-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) TouchPersonalAccessToken(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, touchPersonalAccessToken, id)
+	return err
+}
@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: preferences.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getUserPreferences = `-- name: GetUserPreferences :one
+SELECT user_id, time_zone, week_start, locale, date_format, updated_at FROM user_preferences
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserPreferences(ctx context.Context, userID string) (UserPreference, error) {
+	row := q.db.QueryRow(ctx, getUserPreferences, userID)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.TimeZone,
+		&i.WeekStart,
+		&i.Locale,
+		&i.DateFormat,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserPreferences = `-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (user_id, time_zone, week_start, locale, date_format)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET time_zone = EXCLUDED.time_zone,
+    week_start = EXCLUDED.week_start,
+    locale = EXCLUDED.locale,
+    date_format = EXCLUDED.date_format,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, time_zone, week_start, locale, date_format, updated_at
+`
+
+type UpsertUserPreferencesParams struct {
+	UserID     string `json:"user_id"`
+	TimeZone   string `json:"time_zone"`
+	WeekStart  int16  `json:"week_start"`
+	Locale     string `json:"locale"`
+	DateFormat string `json:"date_format"`
+}
+
+func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error) {
+	row := q.db.QueryRow(ctx, upsertUserPreferences,
+		arg.UserID,
+		arg.TimeZone,
+		arg.WeekStart,
+		arg.Locale,
+		arg.DateFormat,
+	)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.TimeZone,
+		&i.WeekStart,
+		&i.Locale,
+		&i.DateFormat,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
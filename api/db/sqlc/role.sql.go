@@ -0,0 +1,219 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: role.sql
+
+package db
+
+import (
+	"context"
+)
+
+const addUserRole = `-- name: AddUserRole :execrows
+INSERT INTO user_roles (user_id, role)
+VALUES ($1, $2)
+ON CONFLICT (user_id, role) DO NOTHING
+`
+
+type AddUserRoleParams struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// Does nothing if the user already has the role.
+func (q *Queries) AddUserRole(ctx context.Context, arg AddUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addUserRole, arg.UserID, arg.Role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserRole = `-- name: DeleteUserRole :execrows
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2
+`
+
+type DeleteUserRoleParams struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func (q *Queries) DeleteUserRole(ctx context.Context, arg DeleteUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserRole, arg.UserID, arg.Role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getMissingPermissions = `-- name: GetMissingPermissions :many
+SELECT rp.permission
+FROM user_roles ur
+JOIN role_permissions rp ON rp.role = ur.role
+WHERE ur.user_id = $1
+EXCEPT
+SELECT rp.permission
+FROM user_roles ur
+JOIN role_permissions rp ON rp.role = ur.role
+WHERE ur.user_id = $2
+`
+
+type GetMissingPermissionsParams struct {
+	TargetID string `json:"target_id"`
+	UserID   string `json:"user_id"`
+}
+
+// Returns the permissions the target has through roles that the user does not.
+func (q *Queries) GetMissingPermissions(ctx context.Context, arg GetMissingPermissionsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getMissingPermissions, arg.TargetID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		items = append(items, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPermissions = `-- name: GetPermissions :many
+SELECT name, description FROM permissions
+ORDER BY name
+`
+
+func (q *Queries) GetPermissions(ctx context.Context) ([]Permission, error) {
+	rows, err := q.db.Query(ctx, getPermissions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Permission{}
+	for rows.Next() {
+		var i Permission
+		if err := rows.Scan(&i.Name, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRole = `-- name: GetRole :one
+SELECT name, description FROM roles
+WHERE name = $1
+`
+
+func (q *Queries) GetRole(ctx context.Context, name string) (Role, error) {
+	row := q.db.QueryRow(ctx, getRole, name)
+	var i Role
+	err := row.Scan(&i.Name, &i.Description)
+	return i, err
+}
+
+const getRolePermissions = `-- name: GetRolePermissions :many
+SELECT role, permission FROM role_permissions
+ORDER BY role, permission
+`
+
+func (q *Queries) GetRolePermissions(ctx context.Context) ([]RolePermission, error) {
+	rows, err := q.db.Query(ctx, getRolePermissions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RolePermission{}
+	for rows.Next() {
+		var i RolePermission
+		if err := rows.Scan(&i.Role, &i.Permission); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoles = `-- name: GetRoles :many
+SELECT name, description FROM roles
+ORDER BY name
+`
+
+func (q *Queries) GetRoles(ctx context.Context) ([]Role, error) {
+	rows, err := q.db.Query(ctx, getRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Role{}
+	for rows.Next() {
+		var i Role
+		if err := rows.Scan(&i.Name, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRoles = `-- name: GetUserRoles :many
+SELECT user_id, role, created_at FROM user_roles
+WHERE user_id = $1
+ORDER BY role
+`
+
+func (q *Queries) GetUserRoles(ctx context.Context, userID string) ([]UserRole, error) {
+	rows, err := q.db.Query(ctx, getUserRoles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserRole{}
+	for rows.Next() {
+		var i UserRole
+		if err := rows.Scan(&i.UserID, &i.Role, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const userHasPermission = `-- name: UserHasPermission :one
+SELECT EXISTS (
+    SELECT 1 FROM user_roles ur
+    JOIN role_permissions rp ON rp.role = ur.role
+    WHERE ur.user_id = $1 AND rp.permission = $2
+)
+`
+
+type UserHasPermissionParams struct {
+	UserID     string `json:"user_id"`
+	Permission string `json:"permission"`
+}
+
+func (q *Queries) UserHasPermission(ctx context.Context, arg UserHasPermissionParams) (bool, error) {
+	row := q.db.QueryRow(ctx, userHasPermission, arg.UserID, arg.Permission)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
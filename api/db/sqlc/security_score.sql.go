@@ -0,0 +1,241 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: security_score.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const blockIp = `-- name: BlockIp :exec
+INSERT INTO ip_blocks (ip, reason, blocked_until)
+VALUES ($1, $2, $3)
+ON CONFLICT (ip) DO UPDATE
+SET reason = EXCLUDED.reason,
+    blocked_until = GREATEST(ip_blocks.blocked_until, EXCLUDED.blocked_until)
+`
+
+type BlockIpParams struct {
+	Ip           string             `json:"ip"`
+	Reason       string             `json:"reason"`
+	BlockedUntil pgtype.Timestamptz `json:"blocked_until"`
+}
+
+// Extends a block that is already in place rather than shortening it.
+func (q *Queries) BlockIp(ctx context.Context, arg BlockIpParams) error {
+	_, err := q.db.Exec(ctx, blockIp, arg.Ip, arg.Reason, arg.BlockedUntil)
+	return err
+}
+
+const createSecurityAction = `-- name: CreateSecurityAction :exec
+INSERT INTO security_actions (violator_type, violator, rule, action, score)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateSecurityActionParams struct {
+	ViolatorType string `json:"violator_type"`
+	Violator     string `json:"violator"`
+	Rule         string `json:"rule"`
+	Action       string `json:"action"`
+	Score        int32  `json:"score"`
+}
+
+func (q *Queries) CreateSecurityAction(ctx context.Context, arg CreateSecurityActionParams) error {
+	_, err := q.db.Exec(ctx, createSecurityAction,
+		arg.ViolatorType,
+		arg.Violator,
+		arg.Rule,
+		arg.Action,
+		arg.Score,
+	)
+	return err
+}
+
+const createSecurityScore = `-- name: CreateSecurityScore :exec
+INSERT INTO security_scores (violator_type, violator, event, score)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateSecurityScoreParams struct {
+	ViolatorType string `json:"violator_type"`
+	Violator     string `json:"violator"`
+	Event        string `json:"event"`
+	Score        int32  `json:"score"`
+}
+
+func (q *Queries) CreateSecurityScore(ctx context.Context, arg CreateSecurityScoreParams) error {
+	_, err := q.db.Exec(ctx, createSecurityScore,
+		arg.ViolatorType,
+		arg.Violator,
+		arg.Event,
+		arg.Score,
+	)
+	return err
+}
+
+const deleteExpiredIpBlocks = `-- name: DeleteExpiredIpBlocks :exec
+DELETE FROM ip_blocks
+WHERE blocked_until <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredIpBlocks(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredIpBlocks)
+	return err
+}
+
+const deleteIpBlock = `-- name: DeleteIpBlock :execrows
+DELETE FROM ip_blocks
+WHERE ip = $1
+`
+
+func (q *Queries) DeleteIpBlock(ctx context.Context, ip string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteIpBlock, ip)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSecurityScoresBefore = `-- name: DeleteSecurityScoresBefore :exec
+DELETE FROM security_scores
+WHERE created_at <= $1
+`
+
+func (q *Queries) DeleteSecurityScoresBefore(ctx context.Context, createdAt pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteSecurityScoresBefore, createdAt)
+	return err
+}
+
+const getIpBlocks = `-- name: GetIpBlocks :many
+SELECT ip, reason, created_at, blocked_until FROM ip_blocks
+WHERE blocked_until > CURRENT_TIMESTAMP
+ORDER BY blocked_until DESC
+`
+
+func (q *Queries) GetIpBlocks(ctx context.Context) ([]IpBlock, error) {
+	rows, err := q.db.Query(ctx, getIpBlocks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []IpBlock{}
+	for rows.Next() {
+		var i IpBlock
+		if err := rows.Scan(
+			&i.Ip,
+			&i.Reason,
+			&i.CreatedAt,
+			&i.BlockedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSecurityActionsSince = `-- name: GetSecurityActionsSince :many
+SELECT id, violator_type, violator, rule, action, score, created_at FROM security_actions
+WHERE created_at > $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetSecurityActionsSince(ctx context.Context, createdAt pgtype.Timestamptz) ([]SecurityAction, error) {
+	rows, err := q.db.Query(ctx, getSecurityActionsSince, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SecurityAction{}
+	for rows.Next() {
+		var i SecurityAction
+		if err := rows.Scan(
+			&i.ID,
+			&i.ViolatorType,
+			&i.Violator,
+			&i.Rule,
+			&i.Action,
+			&i.Score,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSecurityScores = `-- name: GetSecurityScores :many
+SELECT violator_type,
+    violator,
+    SUM(score)::INT AS score,
+    COUNT(*) AS events,
+    MAX(created_at)::TIMESTAMPTZ AS last_event_at
+FROM security_scores
+WHERE created_at > $1
+GROUP BY violator_type, violator
+ORDER BY score DESC
+`
+
+type GetSecurityScoresRow struct {
+	ViolatorType string             `json:"violator_type"`
+	Violator     string             `json:"violator"`
+	Score        int32              `json:"score"`
+	Events       int64              `json:"events"`
+	LastEventAt  pgtype.Timestamptz `json:"last_event_at"`
+}
+
+func (q *Queries) GetSecurityScores(ctx context.Context, createdAt pgtype.Timestamptz) ([]GetSecurityScoresRow, error) {
+	rows, err := q.db.Query(ctx, getSecurityScores, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSecurityScoresRow{}
+	for rows.Next() {
+		var i GetSecurityScoresRow
+		if err := rows.Scan(
+			&i.ViolatorType,
+			&i.Violator,
+			&i.Score,
+			&i.Events,
+			&i.LastEventAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumSecurityScore = `-- name: SumSecurityScore :one
+SELECT COALESCE(SUM(score), 0)::INT
+FROM security_scores
+WHERE violator_type = $1 AND violator = $2 AND created_at > $3
+`
+
+type SumSecurityScoreParams struct {
+	ViolatorType string             `json:"violator_type"`
+	Violator     string             `json:"violator"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) SumSecurityScore(ctx context.Context, arg SumSecurityScoreParams) (int32, error) {
+	row := q.db.QueryRow(ctx, sumSecurityScore, arg.ViolatorType, arg.Violator, arg.CreatedAt)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
@@ -0,0 +1,92 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: session.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getSessionByFamily = `-- name: GetSessionByFamily :one
+SELECT family, user_id, user_agent, ip, created_at, last_used_at FROM sessions
+WHERE family = $1
+`
+
+func (q *Queries) GetSessionByFamily(ctx context.Context, family string) (Session, error) {
+	row := q.db.QueryRow(ctx, getSessionByFamily, family)
+	var i Session
+	err := row.Scan(
+		&i.Family,
+		&i.UserID,
+		&i.UserAgent,
+		&i.Ip,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getSessionsByUserId = `-- name: GetSessionsByUserId :many
+SELECT family, user_id, user_agent, ip, created_at, last_used_at FROM sessions s
+WHERE s.user_id = $1 AND EXISTS (
+    SELECT 1 FROM jwt_tokens t
+    WHERE t.family = s.family AND t.expires_at > CURRENT_TIMESTAMP
+)
+ORDER BY s.last_used_at DESC
+`
+
+// Sessions whose refresh tokens have all expired are left out.
+func (q *Queries) GetSessionsByUserId(ctx context.Context, userID string) ([]Session, error) {
+	rows, err := q.db.Query(ctx, getSessionsByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.Family,
+			&i.UserID,
+			&i.UserAgent,
+			&i.Ip,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveSession = `-- name: SaveSession :exec
+INSERT INTO sessions (family, user_id, user_agent, ip)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (family) DO UPDATE
+SET user_agent = EXCLUDED.user_agent,
+    ip = EXCLUDED.ip,
+    last_used_at = CURRENT_TIMESTAMP
+`
+
+type SaveSessionParams struct {
+	Family    string `json:"family"`
+	UserID    string `json:"user_id"`
+	UserAgent string `json:"user_agent"`
+	Ip        string `json:"ip"`
+}
+
+func (q *Queries) SaveSession(ctx context.Context, arg SaveSessionParams) error {
+	_, err := q.db.Exec(ctx, saveSession,
+		arg.Family,
+		arg.UserID,
+		arg.UserAgent,
+		arg.Ip,
+	)
+	return err
+}
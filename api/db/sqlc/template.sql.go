@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: template.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createListTemplate = `-- name: CreateListTemplate :one
+INSERT INTO list_templates (id, user_id, title, description, is_shared)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, title, description, is_shared, created_at, updated_at
+`
+
+type CreateListTemplateParams struct {
+	ID          string      `json:"id"`
+	UserID      string      `json:"user_id"`
+	Title       string      `json:"title"`
+	Description pgtype.Text `json:"description"`
+	IsShared    bool        `json:"is_shared"`
+}
+
+func (q *Queries) CreateListTemplate(ctx context.Context, arg CreateListTemplateParams) (ListTemplate, error) {
+	row := q.db.QueryRow(ctx, createListTemplate,
+		arg.ID,
+		arg.UserID,
+		arg.Title,
+		arg.Description,
+		arg.IsShared,
+	)
+	var i ListTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.IsShared,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createTemplateTodo = `-- name: CreateTemplateTodo :one
+INSERT INTO template_todos (id, template_id, parent_id, title, description, due_offset, estimated_duration)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, template_id, parent_id, title, description, due_offset, estimated_duration
+`
+
+type CreateTemplateTodoParams struct {
+	ID                string      `json:"id"`
+	TemplateID        string      `json:"template_id"`
+	ParentID          pgtype.Text `json:"parent_id"`
+	Title             string      `json:"title"`
+	Description       pgtype.Text `json:"description"`
+	DueOffset         pgtype.Int4 `json:"due_offset"`
+	EstimatedDuration pgtype.Int4 `json:"estimated_duration"`
+}
+
+func (q *Queries) CreateTemplateTodo(ctx context.Context, arg CreateTemplateTodoParams) (TemplateTodo, error) {
+	row := q.db.QueryRow(ctx, createTemplateTodo,
+		arg.ID,
+		arg.TemplateID,
+		arg.ParentID,
+		arg.Title,
+		arg.Description,
+		arg.DueOffset,
+		arg.EstimatedDuration,
+	)
+	var i TemplateTodo
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.ParentID,
+		&i.Title,
+		&i.Description,
+		&i.DueOffset,
+		&i.EstimatedDuration,
+	)
+	return i, err
+}
+
+const deleteListTemplate = `-- name: DeleteListTemplate :execrows
+DELETE FROM list_templates
+WHERE id = $1
+`
+
+func (q *Queries) DeleteListTemplate(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteListTemplate, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getListTemplate = `-- name: GetListTemplate :one
+SELECT id, user_id, title, description, is_shared, created_at, updated_at FROM list_templates
+WHERE id = $1
+`
+
+func (q *Queries) GetListTemplate(ctx context.Context, id string) (ListTemplate, error) {
+	row := q.db.QueryRow(ctx, getListTemplate, id)
+	var i ListTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.IsShared,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getListTemplatesVisibleToUser = `-- name: GetListTemplatesVisibleToUser :many
+SELECT id, user_id, title, description, is_shared, created_at, updated_at FROM list_templates
+WHERE user_id = $1 OR is_shared = TRUE
+ORDER BY title
+`
+
+func (q *Queries) GetListTemplatesVisibleToUser(ctx context.Context, userID string) ([]ListTemplate, error) {
+	rows, err := q.db.Query(ctx, getListTemplatesVisibleToUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTemplate{}
+	for rows.Next() {
+		var i ListTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.IsShared,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateTodosByTemplateId = `-- name: GetTemplateTodosByTemplateId :many
+SELECT id, template_id, parent_id, title, description, due_offset, estimated_duration FROM template_todos
+WHERE template_id = $1
+`
+
+func (q *Queries) GetTemplateTodosByTemplateId(ctx context.Context, templateID string) ([]TemplateTodo, error) {
+	rows, err := q.db.Query(ctx, getTemplateTodosByTemplateId, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TemplateTodo{}
+	for rows.Next() {
+		var i TemplateTodo
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.ParentID,
+			&i.Title,
+			&i.Description,
+			&i.DueOffset,
+			&i.EstimatedDuration,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateListTemplate = `-- name: UpdateListTemplate :one
+UPDATE list_templates
+SET title = $1, description = $2, is_shared = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $4
+RETURNING id, user_id, title, description, is_shared, created_at, updated_at
+`
+
+type UpdateListTemplateParams struct {
+	Title       string      `json:"title"`
+	Description pgtype.Text `json:"description"`
+	IsShared    bool        `json:"is_shared"`
+	ID          string      `json:"id"`
+}
+
+func (q *Queries) UpdateListTemplate(ctx context.Context, arg UpdateListTemplateParams) (ListTemplate, error) {
+	row := q.db.QueryRow(ctx, updateListTemplate,
+		arg.Title,
+		arg.Description,
+		arg.IsShared,
+		arg.ID,
+	)
+	var i ListTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.IsShared,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,271 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: time_entry.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createTimeEntry = `-- name: CreateTimeEntry :one
+INSERT INTO time_entries (id, todo_id, user_id, started_at, stopped_at, note)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, todo_id, user_id, started_at, stopped_at, note, created_at
+`
+
+type CreateTimeEntryParams struct {
+	ID        string             `json:"id"`
+	TodoID    string             `json:"todo_id"`
+	UserID    string             `json:"user_id"`
+	StartedAt pgtype.Timestamptz `json:"started_at"`
+	StoppedAt pgtype.Timestamptz `json:"stopped_at"`
+	Note      pgtype.Text        `json:"note"`
+}
+
+func (q *Queries) CreateTimeEntry(ctx context.Context, arg CreateTimeEntryParams) (TimeEntry, error) {
+	row := q.db.QueryRow(ctx, createTimeEntry,
+		arg.ID,
+		arg.TodoID,
+		arg.UserID,
+		arg.StartedAt,
+		arg.StoppedAt,
+		arg.Note,
+	)
+	var i TimeEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TodoID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTimeEntry = `-- name: DeleteTimeEntry :execrows
+DELETE FROM time_entries
+WHERE id = $1
+`
+
+func (q *Queries) DeleteTimeEntry(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTimeEntry, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getRunningTimeEntryByUserId = `-- name: GetRunningTimeEntryByUserId :one
+SELECT id, todo_id, user_id, started_at, stopped_at, note, created_at FROM time_entries
+WHERE user_id = $1 AND stopped_at IS NULL
+`
+
+func (q *Queries) GetRunningTimeEntryByUserId(ctx context.Context, userID string) (TimeEntry, error) {
+	row := q.db.QueryRow(ctx, getRunningTimeEntryByUserId, userID)
+	var i TimeEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TodoID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTimeEntriesByTodoId = `-- name: GetTimeEntriesByTodoId :many
+SELECT id, todo_id, user_id, started_at, stopped_at, note, created_at FROM time_entries
+WHERE todo_id = $1
+ORDER BY started_at
+`
+
+func (q *Queries) GetTimeEntriesByTodoId(ctx context.Context, todoID string) ([]TimeEntry, error) {
+	rows, err := q.db.Query(ctx, getTimeEntriesByTodoId, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TimeEntry{}
+	for rows.Next() {
+		var i TimeEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.UserID,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTimeEntriesInRange = `-- name: GetTimeEntriesInRange :many
+SELECT te.id, te.todo_id, te.user_id, te.started_at, te.stopped_at, te.note, te.created_at, t.title AS todo_title, t.estimated_duration, l.id AS list_id, l.title AS list_title, u.username
+FROM time_entries te
+JOIN todos t ON te.todo_id = t.id
+JOIN lists l ON t.list_id = l.id
+JOIN users u ON te.user_id = u.id
+WHERE te.stopped_at IS NOT NULL
+    AND te.started_at >= $1
+    AND te.started_at < $2
+    AND ($3::text IS NULL OR l.id = $3::text)
+    AND ($4::text IS NULL OR te.user_id = $4::text)
+ORDER BY te.started_at
+`
+
+type GetTimeEntriesInRangeParams struct {
+	RangeStart pgtype.Timestamptz `json:"range_start"`
+	RangeEnd   pgtype.Timestamptz `json:"range_end"`
+	ListID     pgtype.Text        `json:"list_id"`
+	UserID     pgtype.Text        `json:"user_id"`
+}
+
+type GetTimeEntriesInRangeRow struct {
+	ID                string             `json:"id"`
+	TodoID            string             `json:"todo_id"`
+	UserID            string             `json:"user_id"`
+	StartedAt         pgtype.Timestamptz `json:"started_at"`
+	StoppedAt         pgtype.Timestamptz `json:"stopped_at"`
+	Note              pgtype.Text        `json:"note"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	TodoTitle         string             `json:"todo_title"`
+	EstimatedDuration pgtype.Int4        `json:"estimated_duration"`
+	ListID            string             `json:"list_id"`
+	ListTitle         string             `json:"list_title"`
+	Username          string             `json:"username"`
+}
+
+func (q *Queries) GetTimeEntriesInRange(ctx context.Context, arg GetTimeEntriesInRangeParams) ([]GetTimeEntriesInRangeRow, error) {
+	rows, err := q.db.Query(ctx, getTimeEntriesInRange,
+		arg.RangeStart,
+		arg.RangeEnd,
+		arg.ListID,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTimeEntriesInRangeRow{}
+	for rows.Next() {
+		var i GetTimeEntriesInRangeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.UserID,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.Note,
+			&i.CreatedAt,
+			&i.TodoTitle,
+			&i.EstimatedDuration,
+			&i.ListID,
+			&i.ListTitle,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTimeEntryById = `-- name: GetTimeEntryById :one
+SELECT id, todo_id, user_id, started_at, stopped_at, note, created_at FROM time_entries
+WHERE id = $1
+`
+
+func (q *Queries) GetTimeEntryById(ctx context.Context, id string) (TimeEntry, error) {
+	row := q.db.QueryRow(ctx, getTimeEntryById, id)
+	var i TimeEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TodoID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const stopRunningTimeEntriesByListId = `-- name: StopRunningTimeEntriesByListId :exec
+UPDATE time_entries
+SET stopped_at = $2
+WHERE stopped_at IS NULL AND todo_id IN (
+    SELECT id FROM todos WHERE list_id = $1
+)
+`
+
+type StopRunningTimeEntriesByListIdParams struct {
+	ListID    string             `json:"list_id"`
+	StoppedAt pgtype.Timestamptz `json:"stopped_at"`
+}
+
+func (q *Queries) StopRunningTimeEntriesByListId(ctx context.Context, arg StopRunningTimeEntriesByListIdParams) error {
+	_, err := q.db.Exec(ctx, stopRunningTimeEntriesByListId, arg.ListID, arg.StoppedAt)
+	return err
+}
+
+const stopRunningTimeEntriesByTodoId = `-- name: StopRunningTimeEntriesByTodoId :exec
+UPDATE time_entries
+SET stopped_at = $2
+WHERE todo_id = $1 AND stopped_at IS NULL
+`
+
+type StopRunningTimeEntriesByTodoIdParams struct {
+	TodoID    string             `json:"todo_id"`
+	StoppedAt pgtype.Timestamptz `json:"stopped_at"`
+}
+
+func (q *Queries) StopRunningTimeEntriesByTodoId(ctx context.Context, arg StopRunningTimeEntriesByTodoIdParams) error {
+	_, err := q.db.Exec(ctx, stopRunningTimeEntriesByTodoId, arg.TodoID, arg.StoppedAt)
+	return err
+}
+
+const stopTimeEntry = `-- name: StopTimeEntry :one
+UPDATE time_entries
+SET stopped_at = $2
+WHERE id = $1 AND stopped_at IS NULL
+RETURNING id, todo_id, user_id, started_at, stopped_at, note, created_at
+`
+
+type StopTimeEntryParams struct {
+	ID        string             `json:"id"`
+	StoppedAt pgtype.Timestamptz `json:"stopped_at"`
+}
+
+func (q *Queries) StopTimeEntry(ctx context.Context, arg StopTimeEntryParams) (TimeEntry, error) {
+	row := q.db.QueryRow(ctx, stopTimeEntry, arg.ID, arg.StoppedAt)
+	var i TimeEntry
+	err := row.Scan(
+		&i.ID,
+		&i.TodoID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
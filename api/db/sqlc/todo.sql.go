@@ -12,19 +12,24 @@ import (
 )
 
 const createTodo = `-- name: CreateTodo :one
-INSERT INTO todos (id, list_id, user_id, parent_id, title, description, complete_before)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at
+INSERT INTO todos (id, list_id, user_id, parent_id, title, description, complete_before, estimated_duration, priority, tags, recurrence, due_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name
 `
 
 type CreateTodoParams struct {
-	ID             string           `json:"id"`
-	ListID         string           `json:"list_id"`
-	UserID         string           `json:"user_id"`
-	ParentID       pgtype.Text      `json:"parent_id"`
-	Title          string           `json:"title"`
-	Description    pgtype.Text      `json:"description"`
-	CompleteBefore pgtype.Timestamp `json:"complete_before"`
+	ID                string             `json:"id"`
+	ListID            string             `json:"list_id"`
+	UserID            string             `json:"user_id"`
+	ParentID          pgtype.Text        `json:"parent_id"`
+	Title             string             `json:"title"`
+	Description       pgtype.Text        `json:"description"`
+	CompleteBefore    pgtype.Timestamptz `json:"complete_before"`
+	EstimatedDuration pgtype.Int4        `json:"estimated_duration"`
+	Priority          int16              `json:"priority"`
+	Tags              []string           `json:"tags"`
+	Recurrence        pgtype.Text        `json:"recurrence"`
+	DueDate           pgtype.Date        `json:"due_date"`
 }
 
 func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, error) {
@@ -36,6 +41,11 @@ func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, e
 		arg.Title,
 		arg.Description,
 		arg.CompleteBefore,
+		arg.EstimatedDuration,
+		arg.Priority,
+		arg.Tags,
+		arg.Recurrence,
+		arg.DueDate,
 	)
 	var i Todo
 	err := row.Scan(
@@ -50,6 +60,13 @@ func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, e
 		&i.UpdatedAt,
 		&i.CompleteBefore,
 		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
 	)
 	return i, err
 }
@@ -79,8 +96,80 @@ func (q *Queries) DeleteTodoByIdWithListId(ctx context.Context, arg DeleteTodoBy
 	return err
 }
 
+const getTodoByIcalName = `-- name: GetTodoByIcalName :one
+SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name FROM todos
+WHERE list_id = $1 AND COALESCE(ical_name, id || '.ics') = $2::text
+`
+
+type GetTodoByIcalNameParams struct {
+	ListID string `json:"list_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) GetTodoByIcalName(ctx context.Context, arg GetTodoByIcalNameParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, getTodoByIcalName, arg.ListID, arg.Name)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.ListID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompleteBefore,
+		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
+	)
+	return i, err
+}
+
+const getTodoByIcalUid = `-- name: GetTodoByIcalUid :one
+SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name FROM todos
+WHERE list_id = $1 AND COALESCE(ical_uid, id) = $2::text
+`
+
+type GetTodoByIcalUidParams struct {
+	ListID string `json:"list_id"`
+	Uid    string `json:"uid"`
+}
+
+func (q *Queries) GetTodoByIcalUid(ctx context.Context, arg GetTodoByIcalUidParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, getTodoByIcalUid, arg.ListID, arg.Uid)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.ListID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompleteBefore,
+		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
+	)
+	return i, err
+}
+
 const getTodoByIdWithListId = `-- name: GetTodoByIdWithListId :one
-SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at FROM todos
+SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name FROM todos
 WHERE id = $1 AND list_id = $2
 `
 
@@ -104,12 +193,19 @@ func (q *Queries) GetTodoByIdWithListId(ctx context.Context, arg GetTodoByIdWith
 		&i.UpdatedAt,
 		&i.CompleteBefore,
 		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
 	)
 	return i, err
 }
 
 const getTodosAccessibleByUserId = `-- name: GetTodosAccessibleByUserId :many
-SELECT t.id, t.parent_id, t.list_id, t.user_id, t.title, t.description, t.completed, t.created_at, t.updated_at, t.complete_before, t.completed_at FROM todos t
+SELECT t.id, t.parent_id, t.list_id, t.user_id, t.title, t.description, t.completed, t.created_at, t.updated_at, t.complete_before, t.completed_at, t.estimated_duration, t.priority, t.tags, t.recurrence, t.due_date, t.ical_uid, t.ical_name FROM todos t
 JOIN lists l ON t.list_id = l.id
 WHERE l.user_id = $1 OR l.id IN (
     SELECT ls.list_id FROM list_shares ls WHERE ls.user_id = $1
@@ -137,6 +233,13 @@ func (q *Queries) GetTodosAccessibleByUserId(ctx context.Context, userID string)
 			&i.UpdatedAt,
 			&i.CompleteBefore,
 			&i.CompletedAt,
+			&i.EstimatedDuration,
+			&i.Priority,
+			&i.Tags,
+			&i.Recurrence,
+			&i.DueDate,
+			&i.IcalUid,
+			&i.IcalName,
 		); err != nil {
 			return nil, err
 		}
@@ -149,7 +252,7 @@ func (q *Queries) GetTodosAccessibleByUserId(ctx context.Context, userID string)
 }
 
 const getTodosByList = `-- name: GetTodosByList :many
-SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at FROM todos
+SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name FROM todos
 WHERE list_id = $1
 `
 
@@ -174,6 +277,13 @@ func (q *Queries) GetTodosByList(ctx context.Context, listID string) ([]Todo, er
 			&i.UpdatedAt,
 			&i.CompleteBefore,
 			&i.CompletedAt,
+			&i.EstimatedDuration,
+			&i.Priority,
+			&i.Tags,
+			&i.Recurrence,
+			&i.DueDate,
+			&i.IcalUid,
+			&i.IcalName,
 		); err != nil {
 			return nil, err
 		}
@@ -186,7 +296,7 @@ func (q *Queries) GetTodosByList(ctx context.Context, listID string) ([]Todo, er
 }
 
 const getTodosByListIds = `-- name: GetTodosByListIds :many
-SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at FROM todos
+SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name FROM todos
 WHERE list_id = ANY($1::text[])
 `
 
@@ -211,6 +321,13 @@ func (q *Queries) GetTodosByListIds(ctx context.Context, dollar_1 []string) ([]T
 			&i.UpdatedAt,
 			&i.CompleteBefore,
 			&i.CompletedAt,
+			&i.EstimatedDuration,
+			&i.Priority,
+			&i.Tags,
+			&i.Recurrence,
+			&i.DueDate,
+			&i.IcalUid,
+			&i.IcalName,
 		); err != nil {
 			return nil, err
 		}
@@ -222,19 +339,172 @@ func (q *Queries) GetTodosByListIds(ctx context.Context, dollar_1 []string) ([]T
 	return items, nil
 }
 
+const getTodosByListOrdered = `-- name: GetTodosByListOrdered :many
+SELECT id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name FROM todos
+WHERE list_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetTodosByListOrdered(ctx context.Context, listID string) ([]Todo, error) {
+	rows, err := q.db.Query(ctx, getTodosByListOrdered, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Todo{}
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.ListID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Completed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompleteBefore,
+			&i.CompletedAt,
+			&i.EstimatedDuration,
+			&i.Priority,
+			&i.Tags,
+			&i.Recurrence,
+			&i.DueDate,
+			&i.IcalUid,
+			&i.IcalName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importTodo = `-- name: ImportTodo :one
+INSERT INTO todos (id, list_id, user_id, parent_id, title, description, completed, completed_at, complete_before, due_date, estimated_duration, priority, tags, recurrence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name
+`
+
+type ImportTodoParams struct {
+	ID                string             `json:"id"`
+	ListID            string             `json:"list_id"`
+	UserID            string             `json:"user_id"`
+	ParentID          pgtype.Text        `json:"parent_id"`
+	Title             string             `json:"title"`
+	Description       pgtype.Text        `json:"description"`
+	Completed         bool               `json:"completed"`
+	CompletedAt       pgtype.Timestamptz `json:"completed_at"`
+	CompleteBefore    pgtype.Timestamptz `json:"complete_before"`
+	DueDate           pgtype.Date        `json:"due_date"`
+	EstimatedDuration pgtype.Int4        `json:"estimated_duration"`
+	Priority          int16              `json:"priority"`
+	Tags              []string           `json:"tags"`
+	Recurrence        pgtype.Text        `json:"recurrence"`
+}
+
+func (q *Queries) ImportTodo(ctx context.Context, arg ImportTodoParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, importTodo,
+		arg.ID,
+		arg.ListID,
+		arg.UserID,
+		arg.ParentID,
+		arg.Title,
+		arg.Description,
+		arg.Completed,
+		arg.CompletedAt,
+		arg.CompleteBefore,
+		arg.DueDate,
+		arg.EstimatedDuration,
+		arg.Priority,
+		arg.Tags,
+		arg.Recurrence,
+	)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.ListID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompleteBefore,
+		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
+	)
+	return i, err
+}
+
+const setTodoIcalNames = `-- name: SetTodoIcalNames :one
+UPDATE todos
+SET ical_uid = $1, ical_name = $2
+WHERE id = $3
+RETURNING id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name
+`
+
+type SetTodoIcalNamesParams struct {
+	IcalUid  pgtype.Text `json:"ical_uid"`
+	IcalName pgtype.Text `json:"ical_name"`
+	ID       string      `json:"id"`
+}
+
+func (q *Queries) SetTodoIcalNames(ctx context.Context, arg SetTodoIcalNamesParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, setTodoIcalNames, arg.IcalUid, arg.IcalName, arg.ID)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.ListID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompleteBefore,
+		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
+	)
+	return i, err
+}
+
 const updateTodo = `-- name: UpdateTodo :one
 UPDATE todos
-SET title = $1, description = $2, completed = $3, complete_before = $4, updated_at = CURRENT_TIMESTAMP, completed_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE NULL END
-WHERE id = $5
-RETURNING id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at
+SET title = $1, description = $2, completed = $3, complete_before = $4, estimated_duration = $5, priority = $6, tags = $7, recurrence = $8, due_date = $9, updated_at = CURRENT_TIMESTAMP, completed_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE NULL END
+WHERE id = $10
+RETURNING id, parent_id, list_id, user_id, title, description, completed, created_at, updated_at, complete_before, completed_at, estimated_duration, priority, tags, recurrence, due_date, ical_uid, ical_name
 `
 
 type UpdateTodoParams struct {
-	Title          string           `json:"title"`
-	Description    pgtype.Text      `json:"description"`
-	Completed      bool             `json:"completed"`
-	CompleteBefore pgtype.Timestamp `json:"complete_before"`
-	ID             string           `json:"id"`
+	Title             string             `json:"title"`
+	Description       pgtype.Text        `json:"description"`
+	Completed         bool               `json:"completed"`
+	CompleteBefore    pgtype.Timestamptz `json:"complete_before"`
+	EstimatedDuration pgtype.Int4        `json:"estimated_duration"`
+	Priority          int16              `json:"priority"`
+	Tags              []string           `json:"tags"`
+	Recurrence        pgtype.Text        `json:"recurrence"`
+	DueDate           pgtype.Date        `json:"due_date"`
+	ID                string             `json:"id"`
 }
 
 func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, error) {
@@ -243,6 +513,11 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, e
 		arg.Description,
 		arg.Completed,
 		arg.CompleteBefore,
+		arg.EstimatedDuration,
+		arg.Priority,
+		arg.Tags,
+		arg.Recurrence,
+		arg.DueDate,
 		arg.ID,
 	)
 	var i Todo
@@ -258,6 +533,13 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, e
 		&i.UpdatedAt,
 		&i.CompleteBefore,
 		&i.CompletedAt,
+		&i.EstimatedDuration,
+		&i.Priority,
+		&i.Tags,
+		&i.Recurrence,
+		&i.DueDate,
+		&i.IcalUid,
+		&i.IcalName,
 	)
 	return i, err
 }
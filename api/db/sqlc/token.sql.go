@@ -17,11 +17,11 @@ VALUES ($1, $2, $3, $4, $5)
 `
 
 type CreateJwtTokenParams struct {
-	Jti       string           `json:"jti"`
-	UserID    string           `json:"user_id"`
-	Family    string           `json:"family"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	Jti       string             `json:"jti"`
+	UserID    string             `json:"user_id"`
+	Family    string             `json:"family"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
 }
 
 func (q *Queries) CreateJwtToken(ctx context.Context, arg CreateJwtTokenParams) error {
@@ -36,10 +36,14 @@ func (q *Queries) CreateJwtToken(ctx context.Context, arg CreateJwtTokenParams)
 }
 
 const deleteJwtTokenByFamily = `-- name: DeleteJwtTokenByFamily :execrows
-DELETE FROM jwt_tokens
-WHERE family = $1
+WITH deleted_sessions AS (
+    DELETE FROM sessions s WHERE s.family = $1
+)
+DELETE FROM jwt_tokens t
+WHERE t.family = $1
 `
 
+// Deletes the session of the family with it.
 func (q *Queries) DeleteJwtTokenByFamily(ctx context.Context, family string) (int64, error) {
 	result, err := q.db.Exec(ctx, deleteJwtTokenByFamily, family)
 	if err != nil {
@@ -49,8 +53,11 @@ func (q *Queries) DeleteJwtTokenByFamily(ctx context.Context, family string) (in
 }
 
 const deleteJwtTokenByUserIdExcludeFamily = `-- name: DeleteJwtTokenByUserIdExcludeFamily :exec
-DELETE FROM jwt_tokens
-WHERE user_id = $1 AND family != $2
+WITH deleted_sessions AS (
+    DELETE FROM sessions s WHERE s.user_id = $1 AND s.family != $2
+)
+DELETE FROM jwt_tokens t
+WHERE t.user_id = $1 AND t.family != $2
 `
 
 type DeleteJwtTokenByUserIdExcludeFamilyParams struct {
@@ -58,16 +65,21 @@ type DeleteJwtTokenByUserIdExcludeFamilyParams struct {
 	Family string `json:"family"`
 }
 
+// Deletes the sessions of the families with them.
 func (q *Queries) DeleteJwtTokenByUserIdExcludeFamily(ctx context.Context, arg DeleteJwtTokenByUserIdExcludeFamilyParams) error {
 	_, err := q.db.Exec(ctx, deleteJwtTokenByUserIdExcludeFamily, arg.UserID, arg.Family)
 	return err
 }
 
 const deleteJwtTokensByUserId = `-- name: DeleteJwtTokensByUserId :exec
-DELETE FROM jwt_tokens
-WHERE user_id = $1
+WITH deleted_sessions AS (
+    DELETE FROM sessions s WHERE s.user_id = $1
+)
+DELETE FROM jwt_tokens t
+WHERE t.user_id = $1
 `
 
+// Deletes the sessions of the user with them.
 func (q *Queries) DeleteJwtTokensByUserId(ctx context.Context, userID string) error {
 	_, err := q.db.Exec(ctx, deleteJwtTokensByUserId, userID)
 	return err
@@ -0,0 +1,77 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: token_revocation.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createTokenRevocation = `-- name: CreateTokenRevocation :exec
+INSERT INTO token_revocations (user_id, family, revoked_at, expires_at, epoch)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateTokenRevocationParams struct {
+	UserID    string             `json:"user_id"`
+	Family    pgtype.Text        `json:"family"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Epoch     int32              `json:"epoch"`
+}
+
+func (q *Queries) CreateTokenRevocation(ctx context.Context, arg CreateTokenRevocationParams) error {
+	_, err := q.db.Exec(ctx, createTokenRevocation,
+		arg.UserID,
+		arg.Family,
+		arg.RevokedAt,
+		arg.ExpiresAt,
+		arg.Epoch,
+	)
+	return err
+}
+
+const deleteExpiredTokenRevocations = `-- name: DeleteExpiredTokenRevocations :exec
+DELETE FROM token_revocations
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredTokenRevocations(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredTokenRevocations)
+	return err
+}
+
+const getTokenRevocations = `-- name: GetTokenRevocations :many
+SELECT user_id, family, revoked_at, expires_at, epoch FROM token_revocations
+WHERE expires_at > CURRENT_TIMESTAMP
+`
+
+func (q *Queries) GetTokenRevocations(ctx context.Context) ([]TokenRevocation, error) {
+	rows, err := q.db.Query(ctx, getTokenRevocations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TokenRevocation{}
+	for rows.Next() {
+		var i TokenRevocation
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Family,
+			&i.RevokedAt,
+			&i.ExpiresAt,
+			&i.Epoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,223 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: totp.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const attemptLoginChallenge = `-- name: AttemptLoginChallenge :one
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+RETURNING token_hash, user_id, attempts, expires_at
+`
+
+// Counts the attempt and returns the challenge, unless it has expired.
+func (q *Queries) AttemptLoginChallenge(ctx context.Context, tokenHash string) (LoginChallenge, error) {
+	row := q.db.QueryRow(ctx, attemptLoginChallenge, tokenHash)
+	var i LoginChallenge
+	err := row.Scan(
+		&i.TokenHash,
+		&i.UserID,
+		&i.Attempts,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const countRecoveryCodes = `-- name: CountRecoveryCodes :one
+SELECT COUNT(*) FROM recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) CountRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createLoginChallenge = `-- name: CreateLoginChallenge :exec
+INSERT INTO login_challenges (token_hash, user_id, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateLoginChallengeParams struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    string             `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) error {
+	_, err := q.db.Exec(ctx, createLoginChallenge, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (id, user_id, code_hash)
+VALUES ($1, $2, $3)
+`
+
+type CreateRecoveryCodeParams struct {
+	ID       string `json:"id"`
+	UserID   string `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.ID, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteExpiredLoginChallenges = `-- name: DeleteExpiredLoginChallenges :exec
+DELETE FROM login_challenges
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredLoginChallenges(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredLoginChallenges)
+	return err
+}
+
+const deleteLoginChallenge = `-- name: DeleteLoginChallenge :exec
+DELETE FROM login_challenges
+WHERE token_hash = $1
+`
+
+func (q *Queries) DeleteLoginChallenge(ctx context.Context, tokenHash string) error {
+	_, err := q.db.Exec(ctx, deleteLoginChallenge, tokenHash)
+	return err
+}
+
+const deleteRecoveryCodesByUserId = `-- name: DeleteRecoveryCodesByUserId :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodesByUserId(ctx context.Context, userID string) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodesByUserId, userID)
+	return err
+}
+
+const deleteUserTotp = `-- name: DeleteUserTotp :execrows
+DELETE FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTotp(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserTotp, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enableUserTotp = `-- name: EnableUserTotp :exec
+UPDATE user_totp
+SET enabled = TRUE, enabled_at = CURRENT_TIMESTAMP, last_used_step = $2
+WHERE user_id = $1
+`
+
+type EnableUserTotpParams struct {
+	UserID       string `json:"user_id"`
+	LastUsedStep int64  `json:"last_used_step"`
+}
+
+func (q *Queries) EnableUserTotp(ctx context.Context, arg EnableUserTotpParams) error {
+	_, err := q.db.Exec(ctx, enableUserTotp, arg.UserID, arg.LastUsedStep)
+	return err
+}
+
+const getUserTotp = `-- name: GetUserTotp :one
+SELECT user_id, secret, enabled, last_used_step, created_at, enabled_at FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserTotp(ctx context.Context, userID string) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, getUserTotp, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Enabled,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.EnabledAt,
+	)
+	return i, err
+}
+
+const saveUserTotp = `-- name: SaveUserTotp :one
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = CURRENT_TIMESTAMP
+WHERE user_totp.enabled = FALSE
+RETURNING user_id, secret, enabled, last_used_step, created_at, enabled_at
+`
+
+type SaveUserTotpParams struct {
+	UserID string `json:"user_id"`
+	Secret string `json:"secret"`
+}
+
+// Starts over an enrolment that has not been enabled.
+func (q *Queries) SaveUserTotp(ctx context.Context, arg SaveUserTotpParams) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, saveUserTotp, arg.UserID, arg.Secret)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Enabled,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.EnabledAt,
+	)
+	return i, err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+DELETE FROM recovery_codes
+WHERE user_id = $1 AND code_hash = $2
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   string `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useUserTotpStep = `-- name: UseUserTotpStep :execrows
+UPDATE user_totp
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2
+`
+
+type UseUserTotpStepParams struct {
+	UserID       string `json:"user_id"`
+	LastUsedStep int64  `json:"last_used_step"`
+}
+
+// Fails to update if the step, or a later one, has been used already.
+func (q *Queries) UseUserTotpStep(ctx context.Context, arg UseUserTotpStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useUserTotpStep, arg.UserID, arg.LastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -12,23 +12,25 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, username, password_hash, is_admin)
-VALUES ($1, $2, $3, $4)
+INSERT INTO users (id, username, password_hash, is_admin, email, email_verified_at)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, username, is_admin, created_at
 `
 
 type CreateUserParams struct {
-	ID           string `json:"id"`
-	Username     string `json:"username"`
-	PasswordHash string `json:"password_hash"`
-	IsAdmin      bool   `json:"is_admin"`
+	ID              string             `json:"id"`
+	Username        string             `json:"username"`
+	PasswordHash    string             `json:"password_hash"`
+	IsAdmin         bool               `json:"is_admin"`
+	Email           pgtype.Text        `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
 }
 
 type CreateUserRow struct {
-	ID        string           `json:"id"`
-	Username  string           `json:"username"`
-	IsAdmin   bool             `json:"is_admin"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ID        string             `json:"id"`
+	Username  string             `json:"username"`
+	IsAdmin   bool               `json:"is_admin"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
@@ -37,6 +39,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 		arg.Username,
 		arg.PasswordHash,
 		arg.IsAdmin,
+		arg.Email,
+		arg.EmailVerifiedAt,
 	)
 	var i CreateUserRow
 	err := row.Scan(
@@ -61,16 +65,42 @@ func (q *Queries) DeleteUser(ctx context.Context, id string) (int64, error) {
 	return result.RowsAffected(), nil
 }
 
+const getAdminEmails = `-- name: GetAdminEmails :many
+SELECT email::TEXT
+FROM users
+WHERE is_admin AND email IS NOT NULL
+`
+
+func (q *Queries) GetAdminEmails(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, getAdminEmails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllUsers = `-- name: GetAllUsers :many
 SELECT id, username, is_admin, created_at
 FROM users
 `
 
 type GetAllUsersRow struct {
-	ID        string           `json:"id"`
-	Username  string           `json:"username"`
-	IsAdmin   bool             `json:"is_admin"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ID        string             `json:"id"`
+	Username  string             `json:"username"`
+	IsAdmin   bool               `json:"is_admin"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
@@ -99,7 +129,7 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, username, password_hash, is_admin, created_at
+SELECT id, username, password_hash, is_admin, created_at, email, email_verified_at, token_epoch
 FROM users
 WHERE id = $1
 `
@@ -113,12 +143,15 @@ func (q *Queries) GetUserById(ctx context.Context, id string) (User, error) {
 		&i.PasswordHash,
 		&i.IsAdmin,
 		&i.CreatedAt,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.TokenEpoch,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, password_hash, is_admin, created_at
+SELECT id, username, password_hash, is_admin, created_at, email, email_verified_at, token_epoch
 FROM users
 WHERE username = $1
 `
@@ -132,32 +165,57 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.PasswordHash,
 		&i.IsAdmin,
 		&i.CreatedAt,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.TokenEpoch,
 	)
 	return i, err
 }
 
+const incrementUserTokenEpoch = `-- name: IncrementUserTokenEpoch :one
+UPDATE users
+SET token_epoch = token_epoch + 1
+WHERE id = $1
+RETURNING token_epoch
+`
+
+func (q *Queries) IncrementUserTokenEpoch(ctx context.Context, id string) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementUserTokenEpoch, id)
+	var token_epoch int32
+	err := row.Scan(&token_epoch)
+	return token_epoch, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET username = $2, is_admin = $3
+SET username = $2, is_admin = $3, email = $4,
+    email_verified_at = CASE WHEN email IS DISTINCT FROM $4 THEN NULL ELSE email_verified_at END
 WHERE id = $1
 RETURNING id, username, is_admin, created_at
 `
 
 type UpdateUserParams struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	IsAdmin  bool   `json:"is_admin"`
+	ID       string      `json:"id"`
+	Username string      `json:"username"`
+	IsAdmin  bool        `json:"is_admin"`
+	Email    pgtype.Text `json:"email"`
 }
 
 type UpdateUserRow struct {
-	ID        string           `json:"id"`
-	Username  string           `json:"username"`
-	IsAdmin   bool             `json:"is_admin"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ID        string             `json:"id"`
+	Username  string             `json:"username"`
+	IsAdmin   bool               `json:"is_admin"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+// A changed email address has to be verified again.
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
-	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Username, arg.IsAdmin)
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.ID,
+		arg.Username,
+		arg.IsAdmin,
+		arg.Email,
+	)
 	var i UpdateUserRow
 	err := row.Scan(
 		&i.ID,
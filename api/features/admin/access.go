@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Gets the requester if they have the permission. Returns nil if not, in which
+// case the error is already pushed to gin.Context.
+func (controller *AdminController) authorize(permission string, ctx *gin.Context) *db.User {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return nil
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return nil
+	}
+	if !authz.Authorize(
+		controller.db,
+		reqUser,
+		permission,
+		logging.SecurityScoreMedium,
+		ctx.FullPath(),
+		ctx,
+	) {
+		return nil
+	}
+	return reqUser
+}
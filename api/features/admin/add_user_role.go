@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Assigns the role to the user. Responds with created if the user did not
+// have it yet.
+func (controller *AdminController) AddUserRole(ctx *gin.Context) {
+	reqUser := controller.authorize(authz.PermissionRolesManage, ctx)
+	if reqUser == nil {
+		return
+	}
+
+	userID := ctx.Param("userID")
+	roleName := ctx.Param("role")
+	if _, err := controller.db.GetUserById(ctx, userID); errors.Is(err, pgx.ErrNoRows) {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user", file, line, err, ctx)
+		return
+	}
+	if _, err := controller.db.GetRole(ctx, roleName); errors.Is(err, pgx.ErrNoRows) {
+		ctx.Error(gterrors.NewGtValueError(roleName, "unknown role"))
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get role", file, line, err, ctx)
+		return
+	}
+
+	args := &db.AddUserRoleParams{UserID: userID, Role: roleName}
+	rows, err := controller.db.AddUserRole(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to add role to user", file, line, err, ctx)
+		return
+	}
+	if rows == 0 {
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&db.UserRole{UserID: userID, Role: roleName},
+		nil,
+		logging.ObjectEventSubUserRole,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "ok"})
+}
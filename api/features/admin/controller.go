@@ -0,0 +1,15 @@
+package admin
+
+import (
+	"context"
+	db "go-todo/db/sqlc"
+)
+
+type AdminController struct {
+	db  *db.Queries
+	ctx context.Context
+}
+
+func NewController(db *db.Queries, ctx context.Context) *AdminController {
+	return &AdminController{db: db, ctx: ctx}
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+	"go-todo/util/registration"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultInviteCodeLifeSpan = 7 * 24 * time.Hour
+
+// Creates a code that lets people create accounts while registration is
+// invite-only. The code is only returned in this response.
+func (controller *AdminController) CreateInviteCode(ctx *gin.Context) {
+	payload := &schemas.CreateInviteCode{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	reqUser := controller.authorize(authz.PermissionUsersManage, ctx)
+	if reqUser == nil {
+		return
+	}
+
+	maxUses := int32(1)
+	if payload.MaxUses != nil {
+		maxUses = *payload.MaxUses
+	}
+	expiresAt := time.Now().UTC().Add(defaultInviteCodeLifeSpan)
+	if payload.ExpiresAt != nil {
+		if !payload.ExpiresAt.After(time.Now()) {
+			ctx.Error(gterrors.NewGtValueError(payload.ExpiresAt.String(), "expires_at must be in the future"))
+			return
+		}
+		expiresAt = payload.ExpiresAt.UTC()
+	}
+
+	code, codeHash, err := secret.Generate(registration.InviteCodePrefix)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate invite code", file, line, err, ctx)
+		return
+	}
+	args := &db.CreateInviteCodeParams{
+		ID:        uuid.New().String(),
+		CodeHash:  codeHash,
+		CreatedBy: pgtype.Text{String: reqUser.ID, Valid: true},
+		MaxUses:   maxUses,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+	invite, err := controller.db.CreateInviteCode(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create invite code", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&invite,
+		nil,
+		logging.ObjectEventSubInviteCode,
+	)
+	response := inviteCodeResponse(&invite)
+	response["code"] = code
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "invite_code": response})
+}
+
+// Returns the invite code without its hash.
+func inviteCodeResponse(invite *db.InviteCode) gin.H {
+	return gin.H{
+		"id":         invite.ID,
+		"created_by": invite.CreatedBy,
+		"max_uses":   invite.MaxUses,
+		"uses":       invite.Uses,
+		"created_at": invite.CreatedAt,
+		"expires_at": invite.ExpiresAt,
+	}
+}
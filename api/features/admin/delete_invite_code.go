@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deletes the invite code, so that no further accounts can be created with
+// it. Accounts already created with it are kept.
+func (controller *AdminController) DeleteInviteCode(ctx *gin.Context) {
+	reqUser := controller.authorize(authz.PermissionUsersManage, ctx)
+	if reqUser == nil {
+		return
+	}
+
+	inviteID := ctx.Param("inviteID")
+	rows, err := controller.db.DeleteInviteCode(ctx, inviteID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete invite code", file, line, err, ctx)
+		return
+	} else if rows == 0 {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventDelete,
+		reqUser,
+		"deleted",
+		inviteID,
+		logging.ObjectEventSubInviteCode,
+	)
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/gterrors"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+	"go-todo/util/risk"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lifts the block a security rule put on the ip.
+func (controller *AdminController) DeleteIpBlock(ctx *gin.Context) {
+	if reqUser := controller.authorize(authz.PermissionSecurityManage, ctx); reqUser == nil {
+		return
+	}
+
+	unblocked, err := risk.Unblock(ctx, controller.db, ctx.Param("ip"))
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to unblock ip", file, line, err, ctx)
+		return
+	} else if !unblocked {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Takes the role from the user.
+func (controller *AdminController) DeleteUserRole(ctx *gin.Context) {
+	reqUser := controller.authorize(authz.PermissionRolesManage, ctx)
+	if reqUser == nil {
+		return
+	}
+
+	userRole := &db.UserRole{UserID: ctx.Param("userID"), Role: ctx.Param("role")}
+	args := &db.DeleteUserRoleParams{UserID: userRole.UserID, Role: userRole.Role}
+	rows, err := controller.db.DeleteUserRole(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete role of user", file, line, err, ctx)
+		return
+	} else if rows == 0 {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventDelete,
+		reqUser,
+		userRole,
+		nil,
+		logging.ObjectEventSubUserRole,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultAuditLogLimit = 100
+	maxAuditLogLimit     = 1000
+)
+
+// Returns audit log entries, newest first. Filters on the query params actor,
+// event_type, sourcetype (audit or security), target_path (by prefix) and the
+// from and to time range. Pages further back with before_seq set to the
+// next_before_seq of the response.
+func (controller *AdminController) ReadAuditLog(ctx *gin.Context) {
+	reqUser := controller.authorize(authz.PermissionAuditRead, ctx)
+	if reqUser == nil {
+		return
+	}
+
+	loc, err := database.GetUserLocation(ctx, controller.db, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user time zone", file, line, err, ctx)
+		return
+	}
+	from, to, ok := mycontext.GetTimeRangeQuery(ctx, loc)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultAuditLogLimit)))
+	if err != nil || limit < 1 || limit > maxAuditLogLimit {
+		ctx.Error(gterrors.NewGtValueError(ctx.Query("limit"), "limit must be a number from 1 to 1000"))
+		return
+	}
+	optional := func(key string) pgtype.Text {
+		value := ctx.Query(key)
+		return pgtype.Text{String: value, Valid: value != ""}
+	}
+	args := &db.SearchAuditLogParams{
+		Actor:      optional("actor"),
+		EventType:  optional("event_type"),
+		Sourcetype: optional("sourcetype"),
+		TargetPath: optional("target_path"),
+		RangeStart: pgtype.Timestamptz{Time: from, Valid: true},
+		RangeEnd:   pgtype.Timestamptz{Time: to, Valid: true},
+		MaxEntries: int32(limit),
+	}
+	if value := ctx.Query("before_seq"); value != "" {
+		beforeSeq, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			ctx.Error(gterrors.NewGtValueError(value, "before_seq must be a number"))
+			return
+		}
+		args.BeforeSeq = pgtype.Int8{Int64: beforeSeq, Valid: true}
+	}
+
+	entries, err := controller.db.SearchAuditLog(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to search audit log", file, line, err, ctx)
+		return
+	}
+
+	response := gin.H{
+		"status":  "ok",
+		"entries": entries,
+	}
+	if len(entries) == limit {
+		response["next_before_seq"] = entries[len(entries)-1].Seq
+	}
+	ctx.JSON(http.StatusOK, response)
+}
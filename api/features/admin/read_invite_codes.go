@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns every invite code, including expired and used up ones, without the
+// codes themselves.
+func (controller *AdminController) ReadInviteCodes(ctx *gin.Context) {
+	if reqUser := controller.authorize(authz.PermissionUsersRead, ctx); reqUser == nil {
+		return
+	}
+
+	invites, err := controller.db.GetInviteCodes(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get invite codes", file, line, err, ctx)
+		return
+	}
+	response := make([]gin.H, 0, len(invites))
+	for _, invite := range invites {
+		response = append(response, inviteCodeResponse(&invite))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "invite_codes": response})
+}
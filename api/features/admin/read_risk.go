@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+	"go-todo/util/risk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Returns the current risk state: the score of every violator with events in
+// the window, the actions rules fired in it, blocked ips and locked logins,
+// and the rules themselves.
+func (controller *AdminController) ReadRisk(ctx *gin.Context) {
+	if reqUser := controller.authorize(authz.PermissionAuditRead, ctx); reqUser == nil {
+		return
+	}
+
+	rules, err := risk.Rules()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get security rules", file, line, err, ctx)
+		return
+	}
+	window, err := risk.Window()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get security score window", file, line, err, ctx)
+		return
+	}
+	since := pgtype.Timestamptz{Time: time.Now().Add(-window), Valid: true}
+
+	scores, err := controller.db.GetSecurityScores(ctx, since)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get security scores", file, line, err, ctx)
+		return
+	}
+	actions, err := controller.db.GetSecurityActionsSince(ctx, since)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get security actions", file, line, err, ctx)
+		return
+	}
+	ipBlocks, err := controller.db.GetIpBlocks(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get ip blocks", file, line, err, ctx)
+		return
+	}
+	loginLocks, err := controller.db.GetActiveLoginLocks(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get login locks", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":         "ok",
+		"window_minutes": int(window.Minutes()),
+		"scores":         scores,
+		"actions":        actions,
+		"ip_blocks":      ipBlocks,
+		"login_locks":    loginLocks,
+		"rules":          rules,
+	})
+}
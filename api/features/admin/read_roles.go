@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/schemas"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the roles with their permissions, and every permission. Admins have
+// all of them without a role.
+func (controller *AdminController) ReadRoles(ctx *gin.Context) {
+	if reqUser := controller.authorize(authz.PermissionUsersRead, ctx); reqUser == nil {
+		return
+	}
+
+	roles, err := controller.db.GetRoles(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get roles", file, line, err, ctx)
+		return
+	}
+	rolePermissions, err := controller.db.GetRolePermissions(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get role permissions", file, line, err, ctx)
+		return
+	}
+	permissions, err := controller.db.GetPermissions(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get permissions", file, line, err, ctx)
+		return
+	}
+
+	byRole := map[string][]string{}
+	for _, rolePermission := range rolePermissions {
+		byRole[rolePermission.Role] = append(byRole[rolePermission.Role], rolePermission.Permission)
+	}
+	response := make([]schemas.ResponseRole, 0, len(roles))
+	for _, role := range roles {
+		rolePermissions := byRole[role.Name]
+		if rolePermissions == nil {
+			rolePermissions = []string{}
+		}
+		response = append(response, schemas.ResponseRole{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: rolePermissions,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":      "ok",
+		"roles":       response,
+		"permissions": permissions,
+	})
+}
@@ -0,0 +1,42 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	"go-todo/gterrors"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Returns the roles assigned to the user.
+func (controller *AdminController) ReadUserRoles(ctx *gin.Context) {
+	if reqUser := controller.authorize(authz.PermissionUsersRead, ctx); reqUser == nil {
+		return
+	}
+
+	userID := ctx.Param("userID")
+	if _, err := controller.db.GetUserById(ctx, userID); errors.Is(err, pgx.ErrNoRows) {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user", file, line, err, ctx)
+		return
+	}
+	userRoles, err := controller.db.GetUserRoles(ctx, userID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get roles of user", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"roles":  userRoles,
+	})
+}
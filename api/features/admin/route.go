@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"go-todo/middleware"
+	"go-todo/util/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminRoutes struct {
+	adminController *AdminController
+}
+
+func NewRoutes(adminController *AdminController) *AdminRoutes {
+	return &AdminRoutes{adminController}
+}
+
+func (routes *AdminRoutes) Register(rg *gin.RouterGroup) {
+	router := rg.Group("/admin")
+	router.Use(
+		middleware.JwtAuthMiddleware(routes.adminController.db),
+		middleware.RequireScopes(jwt.ScopeUsersAdmin),
+	)
+	router.GET("/risk", routes.adminController.ReadRisk)
+	router.DELETE("/risk/ip-blocks/:ip", routes.adminController.DeleteIpBlock)
+	router.GET("/audit", routes.adminController.ReadAuditLog)
+	router.GET("/audit/verify", routes.adminController.VerifyAuditLog)
+	router.GET("/roles", routes.adminController.ReadRoles)
+	router.GET("/users/:userID/roles", routes.adminController.ReadUserRoles)
+	router.PUT("/users/:userID/roles/:role", routes.adminController.AddUserRole)
+	router.DELETE("/users/:userID/roles/:role", routes.adminController.DeleteUserRole)
+	router.GET("/invites", routes.adminController.ReadInviteCodes)
+	router.POST("/invites", routes.adminController.CreateInviteCode)
+	router.DELETE("/invites/:inviteID", routes.adminController.DeleteInviteCode)
+}
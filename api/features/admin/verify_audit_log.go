@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/util/auditlog"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Checks the hash chain of the whole audit log and responds with the first
+// entry that breaks it, if any.
+func (controller *AdminController) VerifyAuditLog(ctx *gin.Context) {
+	if reqUser := controller.authorize(authz.PermissionAuditRead, ctx); reqUser == nil {
+		return
+	}
+
+	verification, err := auditlog.Verify(ctx, controller.db)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to verify audit log", file, line, err, ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":       "ok",
+		"verification": verification,
+	})
+}
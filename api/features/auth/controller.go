@@ -6,17 +6,20 @@ import (
 	"go-todo/logging"
 	"go-todo/util/jwt"
 	"go-todo/util/mycontext"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AuthController struct {
-	db  *db.Queries
-	ctx context.Context
+	db   *db.Queries
+	pool *pgxpool.Pool
+	ctx  context.Context
 }
 
-func NewController(db *db.Queries, ctx context.Context) *AuthController {
-	return &AuthController{db: db, ctx: ctx}
+func NewController(db *db.Queries, pool *pgxpool.Pool, ctx context.Context) *AuthController {
+	return &AuthController{db: db, pool: pool, ctx: ctx}
 }
 
 func logTokenEventUse(success bool, token *jwt.GtClaims, c *gin.Context) {
@@ -55,6 +58,8 @@ func generateTokens(family string, user db.User) (
 		user.Username,
 		user.ID,
 		user.IsAdmin,
+		refreshClaims.Family,
+		user.TokenEpoch,
 	)
 	if err != nil {
 
@@ -63,6 +68,24 @@ func generateTokens(family string, user db.User) (
 	return
 }
 
+// Longest user agent kept for a session, the rest is cut off.
+const maxUserAgentLength = 500
+
+// Records the device using the session, so that the user can recognize it.
+func (controller *AuthController) saveSession(family string, userID string, c *gin.Context) error {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+	args := &db.SaveSessionParams{
+		Family:    family,
+		UserID:    userID,
+		UserAgent: userAgent,
+		Ip:        c.ClientIP(),
+	}
+	return controller.db.SaveSession(c, *args)
+}
+
 func failedToGenerateJwtError(err error, file string, line int, c *gin.Context) {
 	mycontext.CtxAddGtInternalError("failed to generate jwt", file, line, err, c)
 }
@@ -70,5 +93,3 @@ func failedToGenerateJwtError(err error, file string, line int, c *gin.Context)
 func failedToSaveJwtToDbError(err error, file string, line int, c *gin.Context) {
 	mycontext.CtxAddGtInternalError("failed to save jwt to db", file, line, err, c)
 }
-
-// TODO Add ResetPassword (Requires email to be implemented)
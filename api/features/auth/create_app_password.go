@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Creates an app password for clients that cannot use jwts, like CalDAV
+// clients. The password is only returned in this response. Changing the
+// password of the user deletes it.
+func (controller *AuthController) CreateAppPassword(ctx *gin.Context) {
+	payload := &schemas.CreateAppPassword{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	password, passwordHash, err := secret.Generate("")
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate app password", file, line, err, ctx)
+		return
+	}
+
+	args := &db.CreateAppPasswordParams{
+		ID:           uuid.New().String(),
+		UserID:       reqUser.ID,
+		Name:         payload.Name,
+		PasswordHash: passwordHash,
+	}
+	appPassword, err := controller.db.CreateAppPassword(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create app password", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&appPassword,
+		nil,
+		logging.ObjectEventSubAppPassword,
+	)
+	response := appPasswordResponse(&appPassword)
+	response["password"] = password
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "app_password": response})
+}
+
+// Returns the app password without its hash.
+func appPasswordResponse(appPassword *db.AppPassword) gin.H {
+	return gin.H{
+		"id":           appPassword.ID,
+		"name":         appPassword.Name,
+		"created_at":   appPassword.CreatedAt,
+		"last_used_at": appPassword.LastUsedAt,
+	}
+}
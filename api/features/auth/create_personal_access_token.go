@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+	"slices"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/jwt"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Creates a personal access token for scripts, which is used like an access
+// jwt but does not need refreshing. It only has the scopes given and, with
+// list_ids, only reaches those lists. The token is only returned in this
+// response. Changing the password of the user deletes it.
+func (controller *AuthController) CreatePersonalAccessToken(ctx *gin.Context) {
+	payload := &schemas.CreatePersonalAccessToken{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	expiresAt := pgtype.Timestamptz{}
+	if payload.ExpiresAt != nil {
+		if !payload.ExpiresAt.After(time.Now()) {
+			ctx.Error(gterrors.NewGtValueError(payload.ExpiresAt.String(), "expires_at must be in the future"))
+			return
+		}
+		expiresAt = pgtype.Timestamptz{Time: payload.ExpiresAt.UTC(), Valid: true}
+	}
+
+	// A token cannot create one allowed to do more than itself.
+	tokenScopes, _ := mycontext.GetTokenScopes(ctx)
+	if missing := jwt.MissingScope(tokenScopes, payload.Scopes...); missing != "" {
+		ctx.Error(gterrors.NewGtValueError(missing, "scopes must be a subset of the scopes of the requester"))
+		return
+	}
+	var listIDs []string
+	if len(payload.ListIDs) > 0 {
+		listArgs := &db.GetListsAccessibleByUserIdParams{UserID: reqUser.ID, IncludeArchived: true}
+		lists, err := controller.db.GetListsAccessibleByUserId(ctx, *listArgs)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get lists", file, line, err, ctx)
+			return
+		}
+		for _, listID := range payload.ListIDs {
+			if !slices.ContainsFunc(lists, func(list db.List) bool { return list.ID == listID }) {
+				ctx.Error(gterrors.NewGtValueError(listID, "list_ids must be lists accessible by the requester"))
+				return
+			}
+			if !slices.Contains(listIDs, listID) {
+				listIDs = append(listIDs, listID)
+			}
+		}
+	}
+	scopes := []string{}
+	for _, scope := range payload.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	token, tokenHash, err := secret.Generate(jwt.PersonalAccessTokenPrefix)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate personal access token", file, line, err, ctx)
+		return
+	}
+
+	args := &db.CreatePersonalAccessTokenParams{
+		ID:        uuid.New().String(),
+		UserID:    reqUser.ID,
+		Name:      payload.Name,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		Scopes:    scopes,
+		ListIds:   listIDs,
+	}
+	pat, err := controller.db.CreatePersonalAccessToken(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create personal access token", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&pat,
+		nil,
+		logging.ObjectEventSubPersonalAccessToken,
+	)
+	response := personalAccessTokenResponse(&pat)
+	response["token"] = token
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "personal_access_token": response})
+}
+
+// Returns the token without its hash.
+func personalAccessTokenResponse(pat *db.PersonalAccessToken) gin.H {
+	return gin.H{
+		"id":           pat.ID,
+		"name":         pat.Name,
+		"created_at":   pat.CreatedAt,
+		"expires_at":   pat.ExpiresAt,
+		"scopes":       pat.Scopes,
+		"list_ids":     pat.ListIds,
+		"last_used_at": pat.LastUsedAt,
+	}
+}
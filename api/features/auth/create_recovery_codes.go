@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/passwd"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Replaces the recovery codes of the requester, which requires the password.
+// The new codes are only shown in this response.
+func (controller *AuthController) CreateRecoveryCodes(ctx *gin.Context) {
+	var payload *schemas.CreateRecoveryCodes
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	if !passwd.Compare(payload.Password, reqUser.PasswordHash) {
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonInvalidCredentials,
+				errors.New("provided credentials are incorrect"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	}
+	userTotp, err := controller.db.GetUserTotp(ctx, reqUser.ID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !userTotp.Enabled) {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get totp", file, line, err, ctx)
+		return
+	}
+
+	var codes []string
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		codes, err = replaceRecoveryCodes(ctx, qtx, reqUser.ID)
+		return err
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create recovery codes", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&userTotp,
+		nil,
+		logging.ObjectEventSubTotp,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "recovery_codes": codes})
+}
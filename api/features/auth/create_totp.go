@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/totp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Starts enrolling the requester in two-factor authentication. Returns the
+// secret and the URI to show as a QR code for the authenticator app. Nothing
+// changes at login until the enrolment is confirmed with EnableTotp.
+func (controller *AuthController) CreateTotp(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate totp secret", file, line, err, ctx)
+		return
+	}
+	// Saving fails to return a row if the enrolment is already enabled.
+	args := &db.SaveUserTotpParams{UserID: reqUser.ID, Secret: secret}
+	userTotp, err := controller.db.SaveUserTotp(ctx, *args)
+	if errors.Is(err, pgx.ErrNoRows) {
+		ctx.Error(gterrors.ErrTwoFactorEnabled).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save totp", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&userTotp,
+		nil,
+		logging.ObjectEventSubTotp,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":           "created",
+		"secret":           secret,
+		"provisioning_uri": totp.ProvisioningURI(totpIssuer, reqUser.Username, secret),
+	})
+}
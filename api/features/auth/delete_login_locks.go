@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/lockout"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Unlocks the username and/or ip given as query params and clears their
+// failed logins. Only admins can unlock, and only users they may manage.
+func (controller *AuthController) DeleteLoginLocks(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+	if !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionSessionsManage,
+		logging.SecurityScoreMedium,
+		"login locks",
+		ctx,
+	) {
+		return
+	}
+
+	keys := []string{}
+	if username := ctx.Query("username"); username != "" {
+		// Usernames without a user have nobody to manage.
+		user, err := controller.db.GetUserByUsername(ctx, username)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get user from db", file, line, err, ctx)
+			return
+		}
+		if err == nil && user.ID != reqUser.ID && !authz.AuthorizeManage(
+			controller.db,
+			reqUser,
+			&user,
+			logging.SecurityScoreHigh,
+			ctx,
+		) {
+			return
+		}
+		keys = append(keys, lockout.UsernameKey(username))
+	}
+	if ip := ctx.Query("ip"); ip != "" {
+		keys = append(keys, lockout.IpKey(ip))
+	}
+	if len(keys) == 0 {
+		ctx.Error(gterrors.NewGtValueError("username", "username or ip is required"))
+		return
+	}
+
+	rows, err := controller.db.DeleteLoginFailures(ctx, keys)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete login failures", file, line, err, ctx)
+		return
+	} else if rows == 0 {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
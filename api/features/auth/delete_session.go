@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/revocation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Logs out the session by deleting its refresh tokens. Admins can log out
+// sessions of any user.
+func (controller *AuthController) DeleteSession(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	family := ctx.Param("family")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	session, err := controller.db.GetSessionByFamily(ctx, family)
+	if errors.Is(err, pgx.ErrNoRows) {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get session", file, line, err, ctx)
+		return
+	}
+	if session.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionSessionsManage,
+		logging.SecurityScoreMedium,
+		fmt.Sprintf("session: %v", family),
+		ctx,
+	) {
+		return
+	}
+	if session.UserID != reqUser.ID && !controller.authorizeManageUser(reqUser, session.UserID, ctx) {
+		return
+	}
+
+	if _, err := controller.db.DeleteJwtTokenByFamily(ctx, family); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete jwt family", file, line, err, ctx)
+		return
+	}
+	if err := revocation.RevokeSession(ctx, controller.db, session.UserID, family); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+		return
+	}
+
+	logging.LogSessionEvent(
+		true,
+		ctx.FullPath(),
+		reqUser.Username,
+		logging.SessionEventTypeRevoke,
+		ctx.ClientIP(),
+	)
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
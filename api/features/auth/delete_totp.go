@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/passwd"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Disables two-factor authentication of the requester, which requires the
+// password, and deletes the recovery codes.
+func (controller *AuthController) DeleteTotp(ctx *gin.Context) {
+	var payload *schemas.DisableTotp
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	if !passwd.Compare(payload.Password, reqUser.PasswordHash) {
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonInvalidCredentials,
+				errors.New("provided credentials are incorrect"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var rows int64
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		if rows, err = qtx.DeleteUserTotp(ctx, reqUser.ID); err != nil {
+			return err
+		}
+		return qtx.DeleteRecoveryCodesByUserId(ctx, reqUser.ID)
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete totp", file, line, err, ctx)
+		return
+	}
+	if rows == 0 {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventDelete,
+		reqUser,
+		"deleted",
+		reqUser.ID,
+		logging.ObjectEventSubTotp,
+	)
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/totp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Enables two-factor authentication once the requester proves the
+// authenticator works with a code from it. Returns the recovery codes, which
+// are only shown in this response.
+func (controller *AuthController) EnableTotp(ctx *gin.Context) {
+	var payload *schemas.EnableTotp
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	userTotp, err := controller.db.GetUserTotp(ctx, reqUser.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get totp", file, line, err, ctx)
+		return
+	}
+	if userTotp.Enabled {
+		ctx.Error(gterrors.ErrTwoFactorEnabled).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	step, ok := totp.Validate(userTotp.Secret, payload.Code, time.Now())
+	if !ok {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventTwoFactorFailed,
+			ctx.FullPath(),
+			reqUser.Username,
+			ctx.ClientIP(),
+		)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonTwoFactorInvalid,
+				errors.New("totp code is incorrect"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var codes []string
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		args := &db.EnableUserTotpParams{UserID: reqUser.ID, LastUsedStep: step}
+		if err := qtx.EnableUserTotp(ctx, *args); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(ctx, qtx, reqUser.ID)
+		return err
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to enable totp", file, line, err, ctx)
+		return
+	}
+
+	oldTotp := userTotp
+	userTotp.Enabled = true
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&userTotp,
+		&oldTotp,
+		logging.ObjectEventSubTotp,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "recovery_codes": codes})
+}
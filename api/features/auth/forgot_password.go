@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/config"
+	"go-todo/util/mail"
+	"go-todo/util/mycontext"
+	"go-todo/util/registration"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	passwordResetPrefix   = "gtr_"
+	passwordResetLifeSpan = 30 * time.Minute
+)
+
+// Emails a password reset link to the user, if they have an email address.
+// The response is the same whether the username exists or not, and the email
+// is sent after responding, so that neither reveals it.
+func (controller *AuthController) ForgotPassword(ctx *gin.Context) {
+	var payload *schemas.ForgotPassword
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+
+	user, err := controller.db.GetUserByUsername(ctx, payload.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventPasswordResetToUnknownUsername,
+			ctx.FullPath(),
+			payload.Username,
+			ctx.ClientIP(),
+		)
+		ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user from db", file, line, err, ctx)
+		return
+	}
+	if !user.Email.Valid {
+		ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+		return
+	}
+	settings, err := registration.Get()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get registration settings", file, line, err, ctx)
+		return
+	}
+	// Reset links only go to addresses that are known to be the user's.
+	if settings.RequireEmailVerification && !user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+		return
+	}
+
+	if err := controller.db.DeleteExpiredPasswordResetTokens(ctx); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete expired password reset tokens", file, line, err, ctx)
+		return
+	}
+	// Only the newest link works.
+	if err := controller.db.DeletePasswordResetTokensByUserId(ctx, user.ID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete password reset tokens", file, line, err, ctx)
+		return
+	}
+	token, tokenHash, err := secret.Generate(passwordResetPrefix)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate password reset token", file, line, err, ctx)
+		return
+	}
+	args := &db.CreatePasswordResetTokenParams{
+		TokenHash: tokenHash,
+		UserID:    user.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().UTC().Add(passwordResetLifeSpan), Valid: true},
+	}
+	if err := controller.db.CreatePasswordResetToken(ctx, *args); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save password reset token", file, line, err, ctx)
+		return
+	}
+
+	go sendPasswordResetEmail(user.Email.String, user.Username, token)
+	ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+}
+
+func sendPasswordResetEmail(to string, username string, token string) {
+	logError := func(err error) {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "failed to send password reset email")
+	}
+	config, err := config.Get()
+	if err != nil {
+		logError(err)
+		return
+	}
+
+	link := token
+	if config.PasswordResetURL != "" {
+		resetURL, err := url.Parse(config.PasswordResetURL)
+		if err != nil {
+			logError(err)
+			return
+		}
+		query := resetURL.Query()
+		query.Set("token", token)
+		resetURL.RawQuery = query.Encode()
+		link = resetURL.String()
+	}
+	body := fmt.Sprintf(
+		"Hi %v,\n\nsomeone asked to reset the password of your account. Use this link within %v minutes to choose a new one:\n\n%v\n\nIf it was not you, you can ignore this email.\n",
+		username,
+		int(passwordResetLifeSpan.Minutes()),
+		link,
+	)
+	if err := mail.Send(to, "Reset your password", body); err != nil {
+		logError(err)
+	}
+}
@@ -2,16 +2,22 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 	"go-todo/logging"
 	"go-todo/schemas"
 	"go-todo/util/jwt"
+	"go-todo/util/lockout"
 	"go-todo/util/mycontext"
 	"go-todo/util/passwd"
+	"go-todo/util/registration"
 	"go-todo/util/validate"
+	"math"
 	"net/http"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -26,6 +32,25 @@ func (controller *AuthController) Login(ctx *gin.Context) {
 
 	username := payload.Username
 	password := payload.Password
+	lockedUntil, err := lockout.LockedUntil(ctx, controller.db, username, ctx.ClientIP())
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to check login lock", file, line, err, ctx)
+		return
+	} else if !lockedUntil.IsZero() {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventLoginWhileLocked,
+			ctx.FullPath(),
+			username,
+			ctx.ClientIP(),
+		)
+		retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		ctx.Error(gterrors.ErrLoginLocked).SetType(gin.ErrorTypePublic)
+		return
+	}
+
 	ok, err := validate.Username(username)
 	if err != nil {
 		_, file, line, _ := runtime.Caller(0)
@@ -51,6 +76,7 @@ func (controller *AuthController) Login(ctx *gin.Context) {
 				username,
 				ctx.ClientIP(),
 			)
+			controller.recordLoginFailure(username, ctx)
 
 			ctx.Error(
 				gterrors.NewGtAuthError(
@@ -81,6 +107,7 @@ func (controller *AuthController) Login(ctx *gin.Context) {
 			logging.SessionEventTypeLogin,
 			ctx.ClientIP(),
 		)
+		controller.recordLoginFailure(username, ctx)
 
 		ctx.Error(
 			gterrors.NewGtAuthError(
@@ -91,9 +118,77 @@ func (controller *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
+	settings, err := registration.Get()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get registration settings", file, line, err, ctx)
+		return
+	}
+	// Users without an email address, like the ones from before verification
+	// was required, have nothing to verify.
+	if settings.RequireEmailVerification && user.Email.Valid && !user.EmailVerifiedAt.Valid {
+		logging.LogSessionEvent(
+			false,
+			ctx.FullPath(),
+			user.Username,
+			logging.SessionEventTypeLogin,
+			ctx.ClientIP(),
+		)
+		ctx.Error(gterrors.ErrEmailNotVerified).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	userTotp, err := controller.db.GetUserTotp(ctx, user.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get totp", file, line, err, ctx)
+		return
+	} else if err == nil && userTotp.Enabled {
+		controller.createLoginChallenge(&user, ctx)
+		return
+	}
+
+	controller.issueTokens(&user, ctx)
+}
+
+// Counts the failed login towards locking the username and ip. Errors are
+// only logged, the login has failed either way.
+func (controller *AuthController) recordLoginFailure(username string, ctx *gin.Context) {
+	lockedOut, err := lockout.RecordFailure(ctx, controller.db, username, ctx.ClientIP())
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to record login failure: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+		return
+	}
+	for _, key := range lockedOut {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreHigh,
+			logging.SecurityEventLoginLockedOut,
+			ctx.FullPath(),
+			key,
+			ctx.ClientIP(),
+		)
+	}
+}
+
+// Starts a new session for the user, whose credentials have been checked, and
+// responds with its jwts.
+func (controller *AuthController) issueTokens(user *db.User, ctx *gin.Context) {
+	// Failures are only reset once every factor has been checked, so that
+	// wrong second factors keep counting towards the lock.
+	if err := lockout.Reset(ctx, controller.db, user.Username); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to reset login failures", file, line, err, ctx)
+		return
+	}
+
 	refreshToken, refreshClaims, accessToken, accessClaims, err := generateTokens(
 		"",
-		user,
+		*user,
 	)
 	if err != nil {
 		_, file, line, _ := runtime.Caller(0)
@@ -105,8 +200,8 @@ func (controller *AuthController) Login(ctx *gin.Context) {
 		Jti:       refreshClaims.ID,
 		UserID:    refreshClaims.Subject,
 		Family:    refreshClaims.Family,
-		CreatedAt: pgtype.Timestamp{Time: refreshClaims.IssuedAt.Time, Valid: true},
-		ExpiresAt: pgtype.Timestamp{Time: refreshClaims.ExpiresAt.Time, Valid: true},
+		CreatedAt: pgtype.Timestamptz{Time: refreshClaims.IssuedAt.Time, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: refreshClaims.ExpiresAt.Time, Valid: true},
 	}
 
 	if err := controller.db.CreateJwtToken(ctx, *args); err != nil {
@@ -114,6 +209,11 @@ func (controller *AuthController) Login(ctx *gin.Context) {
 		failedToSaveJwtToDbError(err, file, line, ctx)
 		return
 	}
+	if err := controller.saveSession(refreshClaims.Family, user.ID, ctx); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save session", file, line, err, ctx)
+		return
+	}
 
 	logging.LogSessionEvent(
 		true,
@@ -8,6 +8,7 @@ import (
 	"go-todo/schemas"
 	"go-todo/util/jwt"
 	"go-todo/util/mycontext"
+	"go-todo/util/revocation"
 	"net/http"
 	"runtime"
 
@@ -15,7 +16,6 @@ import (
 )
 
 func (controller *AuthController) Logout(ctx *gin.Context) {
-	// TODO Add access jwt to redis blacklist
 	var payload *schemas.Refresh
 	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
 		return
@@ -79,6 +79,11 @@ func (controller *AuthController) Logout(ctx *gin.Context) {
 		mycontext.CtxAddGtInternalError("", file, line, errIfNil, ctx)
 		return
 	}
+	if err := revocation.RevokeSession(ctx, controller.db, claims.Subject, claims.Family); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+		return
+	}
 
 	logging.LogSessionEvent(
 		true,
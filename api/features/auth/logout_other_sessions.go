@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/revocation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logs out every session of the requester except the one of the request. With
+// the user_id query param admins log out every session of that user.
+func (controller *AuthController) LogoutOtherSessions(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	userID, ok := controller.sessionUserId(reqUser, authz.PermissionSessionsManage, ctx)
+	if !ok {
+		return
+	}
+	// Requests without a session, like ones with a personal access token, log
+	// out every session.
+	current := mycontext.GetTokenSession(ctx)
+	if userID == reqUser.ID && current != "" {
+		sessions, err := controller.db.GetSessionsByUserId(ctx, userID)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get sessions", file, line, err, ctx)
+			return
+		}
+		args := &db.DeleteJwtTokenByUserIdExcludeFamilyParams{UserID: userID, Family: current}
+		if err := controller.db.DeleteJwtTokenByUserIdExcludeFamily(ctx, *args); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to delete jwt families", file, line, err, ctx)
+			return
+		}
+		for _, session := range sessions {
+			if session.Family == current {
+				continue
+			}
+			if err := revocation.RevokeSession(ctx, controller.db, userID, session.Family); err != nil {
+				_, file, line, _ := runtime.Caller(0)
+				mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+				return
+			}
+		}
+	} else {
+		if err := controller.db.DeleteJwtTokensByUserId(ctx, userID); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to delete jwt families", file, line, err, ctx)
+			return
+		}
+		if _, err := revocation.RevokeUser(ctx, controller.db, userID); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+			return
+		}
+	}
+
+	logging.LogSessionEvent(
+		true,
+		ctx.FullPath(),
+		reqUser.Username,
+		logging.SessionEventTypeRevoke,
+		ctx.ClientIP(),
+	)
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
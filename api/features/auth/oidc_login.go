@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/oidc"
+	"go-todo/util/revocation"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	oidcStatePrefix   = "gto_"
+	oidcStateLifeSpan = 10 * time.Minute
+	// Usernames tried with a number appended before provisioning gives up.
+	maxUsernameSuffix = 1000
+)
+
+// Gets the provider, or responds with not found when OpenID Connect login is
+// not configured. Returns nil if the response has been set.
+func getOidcProvider(ctx *gin.Context) *oidc.Provider {
+	provider, err := oidc.Get()
+	if errors.Is(err, oidc.ErrDisabled) {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return nil
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get oidc provider", file, line, err, ctx)
+		return nil
+	}
+	return provider
+}
+
+// Starts an OpenID Connect login. Responds with the url of the provider's
+// login page, which sends the user back to the configured redirect url with
+// the code and state for OidcCallback.
+func (controller *AuthController) OidcAuthorize(ctx *gin.Context) {
+	provider := getOidcProvider(ctx)
+	if provider == nil {
+		return
+	}
+
+	if err := controller.db.DeleteExpiredOidcLoginStates(ctx); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete expired oidc login states", file, line, err, ctx)
+		return
+	}
+	state, stateHash, err := secret.Generate(oidcStatePrefix)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate oidc state", file, line, err, ctx)
+		return
+	}
+	nonce, err := oidc.GenerateVerifier()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate oidc nonce", file, line, err, ctx)
+		return
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate oidc code verifier", file, line, err, ctx)
+		return
+	}
+
+	authURL, err := provider.AuthorizationURL(ctx, state, nonce, verifier)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get oidc authorization url", file, line, err, ctx)
+		return
+	}
+	expiresAt := time.Now().UTC().Add(oidcStateLifeSpan)
+	args := &db.CreateOidcLoginStateParams{
+		StateHash:    stateHash,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+	if err := controller.db.CreateOidcLoginState(ctx, *args); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save oidc login state", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":            "ok",
+		"authorization_url": authURL,
+		"expires_at":        expiresAt,
+	})
+}
+
+// Finishes an OpenID Connect login with the code and state the provider sent
+// back. Logs in the user linked to the identity, creating one on the first
+// login, and responds with jwts like Login. Two-factor authentication is left
+// to the provider.
+func (controller *AuthController) OidcCallback(ctx *gin.Context) {
+	provider := getOidcProvider(ctx)
+	if provider == nil {
+		return
+	}
+	var payload *schemas.OidcCallback
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+
+	loginState, err := controller.db.UseOidcLoginState(ctx, secret.Hash(payload.State))
+	if errors.Is(err, pgx.ErrNoRows) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventOidcLoginFailed,
+			ctx.FullPath(),
+			"oidc state",
+			ctx.ClientIP(),
+		)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonTokenInvalid,
+				errors.New("oidc state not found or expired"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get oidc login state", file, line, err, ctx)
+		return
+	}
+
+	identity, err := provider.Login(ctx, payload.Code, loginState.Nonce, loginState.CodeVerifier)
+	if errors.Is(err, oidc.ErrRejected) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreMedium,
+			logging.SecurityEventOidcLoginFailed,
+			ctx.FullPath(),
+			err.Error(),
+			ctx.ClientIP(),
+		)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonInvalidCredentials,
+				err,
+			),
+		).SetType(gterrors.GetGinErrorType())
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to log in with oidc", file, line, err, ctx)
+		return
+	}
+
+	user, err := controller.oidcUser(provider, identity, ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user for oidc identity", file, line, err, ctx)
+		return
+	}
+	controller.issueTokens(user, ctx)
+}
+
+// Returns the user linked to the identity, after updating is_admin from the
+// groups, or provisions a new one.
+func (controller *AuthController) oidcUser(provider *oidc.Provider, identity *oidc.Identity, ctx *gin.Context) (*db.User, error) {
+	isAdmin, mapsAdmin := provider.IsAdmin(identity)
+	identityArgs := &db.GetUserByIdentityParams{Issuer: identity.Issuer, Subject: identity.Subject}
+	user, err := controller.db.GetUserByIdentity(ctx, *identityArgs)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return controller.provisionOidcUser(identity, isAdmin, mapsAdmin, ctx)
+	} else if err != nil {
+		return nil, err
+	}
+
+	touchArgs := &db.TouchUserIdentityParams{Issuer: identity.Issuer, Subject: identity.Subject}
+	if err := controller.db.TouchUserIdentity(ctx, *touchArgs); err != nil {
+		return nil, err
+	}
+	if !mapsAdmin || user.IsAdmin == isAdmin {
+		return &user, nil
+	}
+
+	oldUser := db.CreateUserRow{ID: user.ID, Username: user.Username, IsAdmin: user.IsAdmin, CreatedAt: user.CreatedAt}
+	updateArgs := &db.UpdateUserParams{ID: user.ID, Username: user.Username, IsAdmin: isAdmin, Email: user.Email}
+	if _, err := controller.db.UpdateUser(ctx, *updateArgs); err != nil {
+		return nil, fmt.Errorf("failed to update is_admin: %w", err)
+	}
+	// Access jwts carry is_admin, so the ones issued before the change are
+	// revoked. The jwts of this login are issued in the new token epoch.
+	if user.TokenEpoch, err = revocation.RevokeUser(ctx, controller.db, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to revoke access jwts: %w", err)
+	}
+	user.IsAdmin = isAdmin
+	newUser := db.CreateUserRow{ID: user.ID, Username: user.Username, IsAdmin: user.IsAdmin, CreatedAt: user.CreatedAt}
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		&user,
+		&newUser,
+		&oldUser,
+		logging.ObjectEventSubUser,
+	)
+	return &user, nil
+}
+
+// Creates a user for the identity and links them. The user has no password,
+// so they can only log in through the provider. Like with CreateUser the
+// first user becomes admin, unless is_admin is mapped from the groups.
+func (controller *AuthController) provisionOidcUser(
+	identity *oidc.Identity,
+	isAdmin bool,
+	mapsAdmin bool,
+	ctx *gin.Context,
+) (*db.User, error) {
+	if !mapsAdmin {
+		users, err := controller.db.GetAllUsers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users: %w", err)
+		}
+		isAdmin = len(users) == 0
+	}
+
+	var created db.CreateUserRow
+	err := database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		username, err := availableUsername(qtx, oidcUsername(identity), ctx)
+		if err != nil {
+			return err
+		}
+		args := &db.CreateUserParams{
+			ID:       uuid.New().String(),
+			Username: username,
+			IsAdmin:  isAdmin,
+		}
+		if created, err = qtx.CreateUser(ctx, *args); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		identityArgs := &db.CreateUserIdentityParams{
+			Issuer:  identity.Issuer,
+			Subject: identity.Subject,
+			UserID:  created.ID,
+		}
+		if err := qtx.CreateUserIdentity(ctx, *identityArgs); err != nil {
+			return fmt.Errorf("failed to link identity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		nil,
+		&created,
+		nil,
+		logging.ObjectEventSubUser,
+	)
+	return &db.User{
+		ID:        created.ID,
+		Username:  created.Username,
+		IsAdmin:   created.IsAdmin,
+		CreatedAt: created.CreatedAt,
+	}, nil
+}
+
+// Longest username in bytes that validate.Username accepts.
+const maxUsernameLength = 20
+
+// Makes a username that passes validate.Username from the preferred username
+// of the identity, or the local part of the email.
+func oidcUsername(identity *oidc.Identity) string {
+	name := identity.PreferredUsername
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	var builder strings.Builder
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '-' {
+			r = '_'
+		}
+		if builder.Len()+utf8.RuneLen(r) > maxUsernameLength {
+			break
+		}
+		builder.WriteRune(r)
+	}
+	if builder.Len() < 3 {
+		return "user"
+	}
+	return builder.String()
+}
+
+// Returns name, or name with the lowest number appended that is not taken.
+func availableUsername(queries *db.Queries, name string, ctx *gin.Context) (string, error) {
+	candidate := name
+	for suffix := 2; suffix <= maxUsernameSuffix; suffix++ {
+		_, err := queries.GetUserByUsername(ctx, candidate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return candidate, nil
+		} else if err != nil {
+			return "", fmt.Errorf("failed to get user: %w", err)
+		}
+		ending := fmt.Sprintf("-%d", suffix)
+		base := name
+		for len(base)+len(ending) > maxUsernameLength {
+			_, size := utf8.DecodeLastRuneInString(base)
+			base = base[:len(base)-size]
+		}
+		candidate = base + ending
+	}
+	return "", fmt.Errorf("no username available for %v", name)
+}
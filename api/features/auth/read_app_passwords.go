@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (controller *AuthController) ReadAppPasswords(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	appPasswords, err := controller.db.GetAppPasswordsByUserId(ctx, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get app passwords", file, line, err, ctx)
+		return
+	}
+
+	response := make([]gin.H, 0, len(appPasswords))
+	for _, appPassword := range appPasswords {
+		response = append(response, appPasswordResponse(&appPassword))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "app_passwords": response})
+}
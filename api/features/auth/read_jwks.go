@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"net/http"
+
+	"go-todo/util/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the public keys jwts are signed with as a JSON Web Key Set, so that
+// other services can verify access jwts, which have the at+jwt typ header.
+// The set is empty when jwts are signed with HS512.
+func (controller *AuthController) ReadJwks(ctx *gin.Context) {
+	// Keys are published before they sign, so caching briefly is safe.
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwt.JWKS())
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+	"strings"
+
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the usernames and ips that cannot log in at the moment because of
+// failed logins. Only admins can see them.
+func (controller *AuthController) ReadLoginLocks(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+	if !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionSessionsRead,
+		logging.SecurityScoreMedium,
+		"login locks",
+		ctx,
+	) {
+		return
+	}
+
+	locks, err := controller.db.GetActiveLoginLocks(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get login locks", file, line, err, ctx)
+		return
+	}
+	response := make([]gin.H, 0, len(locks))
+	for _, lock := range locks {
+		kind, value, _ := strings.Cut(lock.Key, ":")
+		response = append(response, gin.H{
+			kind:              value,
+			"failures":        lock.Failures,
+			"last_failure_at": lock.LastFailureAt,
+			"locked_until":    lock.LockedUntil,
+		})
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "locks": response})
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the OpenID Connect identities the requester logs in with, or with
+// the user_id query param the ones of another user, which only admins can do.
+func (controller *AuthController) ReadOidcIdentities(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	userID, ok := controller.sessionUserId(reqUser, authz.PermissionSessionsRead, ctx)
+	if !ok {
+		return
+	}
+	identities, err := controller.db.GetUserIdentitiesByUserId(ctx, userID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get identities", file, line, err, ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "identities": identities})
+}
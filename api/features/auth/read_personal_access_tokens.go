@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (controller *AuthController) ReadPersonalAccessTokens(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	pats, err := controller.db.GetPersonalAccessTokensByUserId(ctx, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get personal access tokens", file, line, err, ctx)
+		return
+	}
+
+	response := make([]gin.H, 0, len(pats))
+	for _, pat := range pats {
+		response = append(response, personalAccessTokenResponse(&pat))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "personal_access_tokens": response})
+}
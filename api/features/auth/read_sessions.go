@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Returns the active sessions of the requester, or with the user_id query
+// param the ones of another user, which only admins can do. The session of the
+// request is marked as current.
+func (controller *AuthController) ReadSessions(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	userID, ok := controller.sessionUserId(reqUser, authz.PermissionSessionsRead, ctx)
+	if !ok {
+		return
+	}
+	sessions, err := controller.db.GetSessionsByUserId(ctx, userID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get sessions", file, line, err, ctx)
+		return
+	}
+
+	current := mycontext.GetTokenSession(ctx)
+	response := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, gin.H{
+			"family":       session.Family,
+			"user_agent":   session.UserAgent,
+			"ip":           session.Ip,
+			"created_at":   session.CreatedAt,
+			"last_used_at": session.LastUsedAt,
+			"current":      session.Family == current,
+		})
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "sessions": response})
+}
+
+// Returns the user whose sessions are handled, which is the requester unless
+// another one is given with the user_id query param, which needs permission.
+func (controller *AuthController) sessionUserId(reqUser *db.User, permission string, ctx *gin.Context) (string, bool) {
+	userID := ctx.DefaultQuery("user_id", reqUser.ID)
+	if userID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		permission,
+		logging.SecurityScoreMedium,
+		fmt.Sprintf("userID: %v", userID),
+		ctx,
+	) {
+		return "", false
+	}
+	if userID != reqUser.ID && !controller.authorizeManageUser(reqUser, userID, ctx) {
+		return "", false
+	}
+	return userID, true
+}
+
+// Checks that the requester may manage the user with the id, see
+// authz.CanManage. Users that do not exist pass, as there is nothing of them to
+// manage.
+func (controller *AuthController) authorizeManageUser(reqUser *db.User, userID string, ctx *gin.Context) bool {
+	target, err := controller.db.GetUserById(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user from db", file, line, err, ctx)
+		return false
+	}
+	return authz.AuthorizeManage(controller.db, reqUser, &target, logging.SecurityScoreHigh, ctx)
+}
@@ -9,6 +9,7 @@ import (
 	"go-todo/schemas"
 	"go-todo/util/jwt"
 	"go-todo/util/mycontext"
+	"go-todo/util/revocation"
 	"net/http"
 	"runtime"
 
@@ -94,6 +95,11 @@ func (controller *AuthController) Refresh(ctx *gin.Context) {
 			mycontext.CtxAddGtInternalError("", file, line, errIfNil, ctx)
 			return
 		}
+		if err := revocation.RevokeSession(ctx, controller.db, dbToken.UserID, dbToken.Family); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+			return
+		}
 		ctx.Error(
 			gterrors.NewGtAuthError(
 				gterrors.GtAuthErrorReasonTokenReuse,
@@ -148,8 +154,8 @@ func (controller *AuthController) Refresh(ctx *gin.Context) {
 		Jti:       refreshClaims.ID,
 		UserID:    refreshClaims.Subject,
 		Family:    refreshClaims.Family,
-		CreatedAt: pgtype.Timestamp{Time: refreshClaims.IssuedAt.Time, Valid: true},
-		ExpiresAt: pgtype.Timestamp{Time: refreshClaims.ExpiresAt.Time, Valid: true},
+		CreatedAt: pgtype.Timestamptz{Time: refreshClaims.IssuedAt.Time, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: refreshClaims.ExpiresAt.Time, Valid: true},
 	}
 
 	if err := controller.db.CreateJwtToken(ctx, *args); err != nil {
@@ -159,6 +165,13 @@ func (controller *AuthController) Refresh(ctx *gin.Context) {
 		failedToSaveJwtToDbError(err, file, line, ctx)
 		return
 	}
+	if err := controller.saveSession(refreshClaims.Family, user.ID, ctx); err != nil {
+		logTokenEventUse(false, decodedRefreshToken, ctx)
+		logSessionRefresh(false)
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save session", file, line, err, ctx)
+		return
+	}
 
 	logSessionRefresh(true)
 	logTokenCreations([]*jwt.GtClaims{refreshClaims, accessClaims}, ctx)
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+
+	"go-todo/schemas"
+	"go-todo/util/mycontext"
+	"go-todo/util/registration"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Sends a new verification link to the user, if their email address is not
+// verified yet. Like ForgotPassword the response is the same either way and
+// the email is sent after responding.
+func (controller *AuthController) ResendVerification(ctx *gin.Context) {
+	var payload *schemas.ResendVerification
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+
+	user, err := controller.db.GetUserByUsername(ctx, payload.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user from db", file, line, err, ctx)
+		return
+	}
+	if !user.Email.Valid || user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+		return
+	}
+
+	token, err := registration.CreateVerificationToken(ctx, controller.db, user.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create email verification token", file, line, err, ctx)
+		return
+	}
+
+	go registration.SendVerificationEmail(user.Email.String, user.Username, token)
+	ctx.JSON(http.StatusAccepted, gin.H{"status": "ok"})
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/passwd"
+	"go-todo/util/revocation"
+	"go-todo/util/secret"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Sets a new password with the token of a reset link. Like UpdatePassword it
+// logs out every session, but no new jwts are issued, the user logs in with
+// the new password.
+func (controller *AuthController) ResetPassword(ctx *gin.Context) {
+	var payload *schemas.ResetPassword
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+
+	// Checked before the token is used, so that it still works with a
+	// better password.
+	isPasswdValid, err := validate.Password(payload.NewPassword)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("error validating password", file, line, err, ctx)
+		return
+	} else if !isPasswdValid {
+		ctx.Error(gterrors.ErrPasswordUnsatisfied).SetType(gin.ErrorTypePublic)
+		return
+	}
+	newPasswordHash, err := passwd.Hash(payload.NewPassword)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to hash new password", file, line, err, ctx)
+		return
+	}
+
+	var userID string
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		var err error
+		if userID, err = qtx.UsePasswordResetToken(ctx, secret.Hash(payload.Token)); err != nil {
+			return err
+		}
+		args := &db.UpdateUserPasswordParams{ID: userID, PasswordHash: newPasswordHash}
+		if err := qtx.UpdateUserPassword(ctx, *args); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		if err := qtx.DeleteJwtTokensByUserId(ctx, userID); err != nil {
+			return fmt.Errorf("failed to remove refresh jwts: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreMedium,
+			logging.SecurityEventPasswordResetTokenInvalid,
+			ctx.FullPath(),
+			"password reset token",
+			ctx.ClientIP(),
+		)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonTokenInvalid,
+				errors.New("password reset token not found or expired"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to reset password", file, line, err, ctx)
+		return
+	}
+
+	if _, err := revocation.RevokeUser(ctx, controller.db, userID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+		return
+	}
+	if err := revocation.RevokeCredentials(ctx, controller.db, userID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to revoke credentials", file, line, err, ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
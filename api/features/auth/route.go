@@ -2,6 +2,7 @@ package auth
 
 import (
 	"go-todo/middleware"
+	"go-todo/util/jwt"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,9 +16,58 @@ func NewRoutes(authController *AuthController) *AuthRoutes {
 }
 
 func (routes *AuthRoutes) Register(rg *gin.RouterGroup) {
+	jwtAuth := middleware.JwtAuthMiddleware(routes.authController.db)
+	usersAdmin := middleware.RequireScopes(jwt.ScopeUsersAdmin)
 	router := rg.Group("/auth")
 	router.POST("/login", routes.authController.Login)
-	router.POST("/logout", middleware.JwtAuthMiddleware(), routes.authController.Logout)
+	router.POST("/login/verify", routes.authController.VerifyLogin)
+	router.POST("/logout", jwtAuth, routes.authController.Logout)
 	router.POST("/refresh", routes.authController.Refresh)
-	router.POST("/update-password", middleware.JwtAuthMiddleware(), routes.authController.UpdatePassword)
+	router.POST("/update-password", jwtAuth, usersAdmin, routes.authController.UpdatePassword)
+	router.POST("/forgot-password", routes.authController.ForgotPassword)
+	router.POST("/reset-password", routes.authController.ResetPassword)
+	router.POST("/verify-email", routes.authController.VerifyEmail)
+	router.POST("/resend-verification", routes.authController.ResendVerification)
+
+	appPasswordRouter := router.Group("/app-password")
+	appPasswordRouter.Use(jwtAuth, usersAdmin)
+	appPasswordRouter.GET("/", routes.authController.ReadAppPasswords)
+	appPasswordRouter.POST("/", routes.authController.CreateAppPassword)
+	appPasswordRouter.DELETE("/:appPasswordID", routes.authController.DeleteAppPassword)
+
+	tokenRouter := router.Group("/token")
+	tokenRouter.Use(jwtAuth, usersAdmin)
+	tokenRouter.GET("/", routes.authController.ReadPersonalAccessTokens)
+	tokenRouter.POST("/", routes.authController.CreatePersonalAccessToken)
+	tokenRouter.DELETE("/:tokenID", routes.authController.DeletePersonalAccessToken)
+
+	sessionRouter := router.Group("/sessions")
+	sessionRouter.Use(jwtAuth, usersAdmin)
+	sessionRouter.GET("/", routes.authController.ReadSessions)
+	sessionRouter.POST("/logout-others", routes.authController.LogoutOtherSessions)
+	sessionRouter.DELETE("/:family", routes.authController.DeleteSession)
+
+	totpRouter := router.Group("/totp")
+	totpRouter.Use(jwtAuth, usersAdmin)
+	totpRouter.GET("/", routes.authController.ReadTotp)
+	totpRouter.POST("/", routes.authController.CreateTotp)
+	totpRouter.DELETE("/", routes.authController.DeleteTotp)
+	totpRouter.POST("/enable", routes.authController.EnableTotp)
+	totpRouter.POST("/recovery-codes", routes.authController.CreateRecoveryCodes)
+
+	lockRouter := router.Group("/login-locks")
+	lockRouter.Use(jwtAuth, usersAdmin)
+	lockRouter.GET("/", routes.authController.ReadLoginLocks)
+	lockRouter.DELETE("/", routes.authController.DeleteLoginLocks)
+
+	oidcRouter := router.Group("/oidc")
+	oidcRouter.POST("/authorize", routes.authController.OidcAuthorize)
+	oidcRouter.POST("/callback", routes.authController.OidcCallback)
+	oidcRouter.GET("/identities", jwtAuth, usersAdmin, routes.authController.ReadOidcIdentities)
+}
+
+// Registers the endpoints that are expected at the root, next to
+// /.well-known.
+func (routes *AuthRoutes) RegisterWellKnown(rg *gin.RouterGroup) {
+	rg.GET("/.well-known/jwks.json", routes.authController.ReadJwks)
 }
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	db "go-todo/db/sqlc"
+	"go-todo/util/secret"
+
+	"github.com/google/uuid"
+)
+
+// Shown in authenticator apps next to the username.
+const totpIssuer = "go-todo"
+
+const recoveryCodeCount = 10
+
+// Replaces the recovery codes of the user with new ones, which are returned
+// formatted like ABCD-EFGH-IJKL-MNOP and stored hashed.
+func replaceRecoveryCodes(c context.Context, queries *db.Queries, userID string) ([]string, error) {
+	if err := queries.DeleteRecoveryCodesByUserId(c, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		// 80 bits, enough that a fast hash is sufficient.
+		bytes := make([]byte, 10)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		code := base32.StdEncoding.EncodeToString(bytes)
+		args := &db.CreateRecoveryCodeParams{
+			ID:       uuid.New().String(),
+			UserID:   userID,
+			CodeHash: secret.Hash(code),
+		}
+		if err := queries.CreateRecoveryCode(c, *args); err != nil {
+			return nil, fmt.Errorf("failed to create recovery code: %w", err)
+		}
+		codes = append(codes, fmt.Sprintf("%v-%v-%v-%v", code[0:4], code[4:8], code[8:12], code[12:16]))
+	}
+	return codes, nil
+}
+
+// Hashes a recovery code as entered by the user, ignoring case, dashes and
+// spaces.
+func hashRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return secret.Hash(code)
+}
@@ -7,6 +7,7 @@ import (
 	"go-todo/schemas"
 	"go-todo/util/mycontext"
 	"go-todo/util/passwd"
+	"go-todo/util/revocation"
 	"go-todo/util/validate"
 	"net/http"
 	"runtime"
@@ -77,6 +78,19 @@ func (controller *AuthController) UpdatePassword(ctx *gin.Context) {
 		return
 	}
 
+	// Revoked before the new jwts are issued, which stay valid as they are
+	// issued in the new token epoch.
+	if user.TokenEpoch, err = revocation.RevokeUser(ctx, controller.db, user.ID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to revoke access jwts", file, line, err, ctx)
+		return
+	}
+	if err := revocation.RevokeCredentials(ctx, controller.db, user.ID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to revoke credentials", file, line, err, ctx)
+		return
+	}
+
 	refreshToken, refreshClaims, accessToken, _, err := generateTokens(
 		"",
 		user,
@@ -91,8 +105,8 @@ func (controller *AuthController) UpdatePassword(ctx *gin.Context) {
 		Jti:       refreshClaims.ID,
 		UserID:    refreshClaims.Subject,
 		Family:    refreshClaims.Family,
-		CreatedAt: pgtype.Timestamp{Time: refreshClaims.IssuedAt.Time, Valid: true},
-		ExpiresAt: pgtype.Timestamp{Time: refreshClaims.ExpiresAt.Time, Valid: true},
+		CreatedAt: pgtype.Timestamptz{Time: refreshClaims.IssuedAt.Time, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: refreshClaims.ExpiresAt.Time, Valid: true},
 	}
 
 	if err := controller.db.CreateJwtToken(ctx, *refreshArgs); err != nil {
@@ -100,6 +114,11 @@ func (controller *AuthController) UpdatePassword(ctx *gin.Context) {
 		failedToSaveJwtToDbError(err, file, line, ctx)
 		return
 	}
+	if err := controller.saveSession(refreshClaims.Family, user.ID, ctx); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save session", file, line, err, ctx)
+		return
+	}
 
 	deleteArgs := &db.DeleteJwtTokenByUserIdExcludeFamilyParams{
 		UserID: userID,
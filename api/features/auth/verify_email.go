@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Marks the email address of the user as verified with the token of a
+// verification link, after which they can log in.
+func (controller *AuthController) VerifyEmail(ctx *gin.Context) {
+	var payload *schemas.VerifyEmail
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+
+	var userID string
+	err := database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		var err error
+		if userID, err = qtx.UseEmailVerificationToken(ctx, secret.Hash(payload.Token)); err != nil {
+			return err
+		}
+		if err := qtx.MarkUserEmailVerified(ctx, userID); err != nil {
+			return fmt.Errorf("failed to mark email as verified: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreMedium,
+			logging.SecurityEventEmailVerificationTokenInvalid,
+			ctx.FullPath(),
+			"email verification token",
+			ctx.ClientIP(),
+		)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonTokenInvalid,
+				errors.New("email verification token not found or expired"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to verify email", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
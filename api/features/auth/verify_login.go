@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/lockout"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+	"go-todo/util/totp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	loginChallengePrefix   = "gtc_"
+	loginChallengeLifeSpan = 5 * time.Minute
+	// Wrong codes accepted before the challenge is deleted and the login has
+	// to start over with the password.
+	loginChallengeAttempts = 5
+)
+
+// Responds with a challenge token instead of jwts, for users with two-factor
+// authentication. VerifyLogin exchanges it and a code for the jwts.
+func (controller *AuthController) createLoginChallenge(user *db.User, ctx *gin.Context) {
+	if err := controller.db.DeleteExpiredLoginChallenges(ctx); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete expired login challenges", file, line, err, ctx)
+		return
+	}
+	token, tokenHash, err := secret.Generate(loginChallengePrefix)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate login challenge", file, line, err, ctx)
+		return
+	}
+
+	expiresAt := time.Now().UTC().Add(loginChallengeLifeSpan)
+	args := &db.CreateLoginChallengeParams{
+		TokenHash: tokenHash,
+		UserID:    user.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+	if err := controller.db.CreateLoginChallenge(ctx, *args); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save login challenge", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":          "two-factor-required",
+		"challenge_token": token,
+		"expires_at":      expiresAt,
+	})
+}
+
+// Second step of logging in with two-factor authentication. Takes the
+// challenge token from Login and a code from the authenticator, or a recovery
+// code, and responds with jwts like Login.
+func (controller *AuthController) VerifyLogin(ctx *gin.Context) {
+	var payload *schemas.VerifyLogin
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+
+	tokenHash := secret.Hash(payload.ChallengeToken)
+	challenge, err := controller.db.AttemptLoginChallenge(ctx, tokenHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventTwoFactorFailed,
+			ctx.FullPath(),
+			"login challenge",
+			ctx.ClientIP(),
+		)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonTokenInvalid,
+				errors.New("login challenge not found or expired"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get login challenge", file, line, err, ctx)
+		return
+	}
+
+	user, err := controller.db.GetUserById(ctx, challenge.UserID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user from db", file, line, err, ctx)
+		return
+	}
+	// Wrong codes count towards locking the username, which holds for the
+	// challenges already handed out as well.
+	lockedUntil, err := lockout.LockedUntil(ctx, controller.db, user.Username, ctx.ClientIP())
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to check login lock", file, line, err, ctx)
+		return
+	} else if !lockedUntil.IsZero() {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventLoginWhileLocked,
+			ctx.FullPath(),
+			user.Username,
+			ctx.ClientIP(),
+		)
+		retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		ctx.Error(gterrors.ErrLoginLocked).SetType(gin.ErrorTypePublic)
+		return
+	}
+	userTotp, err := controller.db.GetUserTotp(ctx, user.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get totp", file, line, err, ctx)
+		return
+	}
+
+	ok, err := controller.checkSecondFactor(&userTotp, payload.Code, ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to check code", file, line, err, ctx)
+		return
+	}
+	if !ok {
+		score := logging.SecurityScoreMedium
+		if challenge.Attempts >= loginChallengeAttempts {
+			score = logging.SecurityScoreHigh
+			if err := controller.db.DeleteLoginChallenge(ctx, tokenHash); err != nil {
+				_, file, line, _ := runtime.Caller(0)
+				mycontext.CtxAddGtInternalError("failed to delete login challenge", file, line, err, ctx)
+				return
+			}
+		}
+		logging.LogSecurityEvent(
+			score,
+			logging.SecurityEventTwoFactorFailed,
+			ctx.FullPath(),
+			user.Username,
+			ctx.ClientIP(),
+		)
+		logging.LogSessionEvent(
+			false,
+			ctx.FullPath(),
+			user.Username,
+			logging.SessionEventTypeLogin,
+			ctx.ClientIP(),
+		)
+		controller.recordLoginFailure(user.Username, ctx)
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonTwoFactorInvalid,
+				errors.New("two-factor code is incorrect"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := controller.db.DeleteLoginChallenge(ctx, tokenHash); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete login challenge", file, line, err, ctx)
+		return
+	}
+	controller.issueTokens(&user, ctx)
+}
+
+// Checks a code from the authenticator, which cannot be used twice, or uses
+// up a recovery code.
+func (controller *AuthController) checkSecondFactor(userTotp *db.UserTotp, code string, ctx *gin.Context) (bool, error) {
+	if step, ok := totp.Validate(userTotp.Secret, code, time.Now()); ok {
+		args := &db.UseUserTotpStepParams{UserID: userTotp.UserID, LastUsedStep: step}
+		rows, err := controller.db.UseUserTotpStep(ctx, *args)
+		return rows > 0, err
+	}
+	args := &db.UseRecoveryCodeParams{UserID: userTotp.UserID, CodeHash: hashRecoveryCode(code)}
+	rows, err := controller.db.UseRecoveryCode(ctx, *args)
+	return rows > 0, err
+}
@@ -0,0 +1,102 @@
+package caldav
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Gets the user the app password belongs to. Returns nil on failure, in which
+// case the error is already pushed to gin.Context.
+func (controller *CaldavController) getRequester(ctx *gin.Context) *db.User {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user from app password", file, line, err, ctx)
+		return nil
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return nil
+	}
+	return reqUser
+}
+
+// Gets the list if it is owned by or shared with the user. Returns nil if
+// not, in which case the error is already pushed to gin.Context.
+func (controller *CaldavController) getAccessibleList(
+	reqUser *db.User,
+	listID string,
+	ctx *gin.Context,
+) *db.List {
+	listIds, err := controller.db.GetListIdsAccessible(ctx, reqUser.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError(
+			"failed to get list accessible by user",
+			file,
+			line,
+			err,
+			ctx,
+		)
+		return nil
+	}
+	if !slices.Contains(listIds, listID) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventForbiddenAction,
+			ctx.FullPath(),
+			listID,
+			reqUser.ID,
+		)
+		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+		return nil
+	}
+
+	list, err := controller.db.GetList(ctx, listID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get list", file, line, err, ctx)
+		return nil
+	}
+	return &list
+}
+
+// Gets the todo stored under the resource name in the list. Returns nil if
+// there is none, without pushing an error, as PUT creates missing resources.
+// ok is false on failure, in which case the error is already pushed to
+// gin.Context.
+func (controller *CaldavController) getResourceTodo(
+	listID string,
+	resource string,
+	ctx *gin.Context,
+) (todo *db.Todo, ok bool) {
+	args := &db.GetTodoByIcalNameParams{ListID: listID, Name: resource}
+	found, err := controller.db.GetTodoByIcalName(ctx, *args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, true
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get todo", file, line, err, ctx)
+		return nil, false
+	}
+	return &found, true
+}
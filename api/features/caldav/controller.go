@@ -0,0 +1,22 @@
+package caldav
+
+import (
+	"context"
+	db "go-todo/db/sqlc"
+	"go-todo/features/todo"
+)
+
+type CaldavController struct {
+	db *db.Queries
+	// Creates, updates and deletes go through the todo handlers' logic.
+	todoController *todo.TodoController
+	ctx            context.Context
+}
+
+func NewController(
+	db *db.Queries,
+	todoController *todo.TodoController,
+	ctx context.Context,
+) *CaldavController {
+	return &CaldavController{db: db, todoController: todoController, ctx: ctx}
+}
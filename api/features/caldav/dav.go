@@ -0,0 +1,190 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go-todo/gterrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	nsDav      = "DAV:"
+	nsCaldav   = "urn:ietf:params:xml:ns:caldav"
+	nsCalendar = "http://calendarserver.org/ns/"
+
+	// Request bodies are small XML documents or single todos.
+	maxBodySize = 1 << 20
+)
+
+var prefixes = map[string]string{
+	nsDav:      "d",
+	nsCaldav:   "c",
+	nsCalendar: "cs",
+}
+
+// A property of a resource with its value as XML.
+type davProp struct {
+	name  xml.Name
+	value string
+}
+
+func prop(space, local, value string) davProp {
+	return davProp{name: xml.Name{Space: space, Local: local}, value: value}
+}
+
+// One resource in a multistatus response. With status set, the resource is
+// reported without properties, like a missing href of a multiget.
+type davResponse struct {
+	href    string
+	found   []davProp
+	missing []xml.Name
+	status  int
+}
+
+// The properties a PROPFIND or REPORT asks for.
+type propRequest struct {
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     struct {
+		Names []struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	} `xml:"DAV: prop"`
+}
+
+// Picks the requested properties out of the available ones. calendar-data is
+// only returned when asked for by name.
+func (req *propRequest) selectProps(available []davProp) davResponse {
+	response := davResponse{}
+	switch {
+	case req.PropName != nil:
+		for _, p := range available {
+			response.found = append(response.found, davProp{name: p.name})
+		}
+	case req.AllProp != nil || len(req.Prop.Names) == 0:
+		for _, p := range available {
+			if p.name.Space == nsCaldav && p.name.Local == "calendar-data" {
+				continue
+			}
+			response.found = append(response.found, p)
+		}
+	default:
+	requested:
+		for _, name := range req.Prop.Names {
+			for _, p := range available {
+				if p.name == name.XMLName {
+					response.found = append(response.found, p)
+					continue requested
+				}
+			}
+			response.missing = append(response.missing, name.XMLName)
+		}
+	}
+	return response
+}
+
+// Reads the XML body into v. An empty body leaves v as it is. Returns false on
+// failure, in which case the error is already pushed to gin.Context.
+func bindXML(v any, ctx *gin.Context) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodySize))
+	if err != nil {
+		ctx.Error(gterrors.NewGtValueError("body", err.Error()))
+		return false
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return true
+	}
+	if err := xml.Unmarshal(body, v); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return false
+	}
+	return true
+}
+
+// Whether the Depth header asks for the children of a collection. Infinity is
+// treated like 1, as no collection here has nested collections.
+func wantsChildren(ctx *gin.Context) bool {
+	depth := ctx.GetHeader("Depth")
+	return depth == "1" || strings.EqualFold(depth, "infinity")
+}
+
+func writeMultistatus(ctx *gin.Context, responses []davResponse) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus`)
+	for _, space := range []string{nsDav, nsCaldav, nsCalendar} {
+		fmt.Fprintf(&b, ` xmlns:%v="%v"`, prefixes[space], space)
+	}
+	b.WriteString(">")
+	for _, response := range responses {
+		b.WriteString("<d:response><d:href>")
+		xml.EscapeText(&b, []byte(response.href))
+		b.WriteString("</d:href>")
+		if response.status != 0 {
+			writeStatus(&b, response.status)
+		}
+		if len(response.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, p := range response.found {
+				b.WriteString(element(p.name, p.value))
+			}
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusOK)
+			b.WriteString("</d:propstat>")
+		}
+		if len(response.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range response.missing {
+				b.WriteString(element(name, ""))
+			}
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusNotFound)
+			b.WriteString("</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+	ctx.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", []byte(b.String()))
+}
+
+func writeStatus(b *strings.Builder, status int) {
+	fmt.Fprintf(b, "<d:status>HTTP/1.1 %d %v</d:status>", status, http.StatusText(status))
+}
+
+// Writes the element with value as its content. Elements of namespaces
+// without a prefix declare their namespace.
+func element(name xml.Name, value string) string {
+	tag := name.Local
+	declaration := ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(name.Space))
+		declaration = fmt.Sprintf(` xmlns:x="%v"`, b.String())
+	}
+	if value == "" {
+		return fmt.Sprintf("<%v%v/>", tag, declaration)
+	}
+	return fmt.Sprintf("<%v%v>%v</%v>", tag, declaration, value, tag)
+}
+
+func href(path string) string {
+	var b strings.Builder
+	b.WriteString("<d:href>")
+	xml.EscapeText(&b, []byte(path))
+	b.WriteString("</d:href>")
+	return b.String()
+}
+
+func text(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
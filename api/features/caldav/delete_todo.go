@@ -0,0 +1,37 @@
+package caldav
+
+import (
+	"net/http"
+
+	"go-todo/gterrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deletes the todo through the same logic as the todo endpoint.
+func (controller *CaldavController) DeleteTodo(ctx *gin.Context) {
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	list := controller.getAccessibleList(reqUser, ctx.Param("listID"), ctx)
+	if list == nil {
+		return
+	}
+	todo, ok := controller.getResourceTodo(list.ID, ctx.Param("resource"), ctx)
+	if !ok {
+		return
+	} else if todo == nil {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+	if ok := checkPreconditions(todo, ctx); !ok {
+		return
+	}
+
+	if ok := controller.todoController.DeleteTodoAs(reqUser, list.ID, todo.ID, ctx); !ok {
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+package caldav
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/ical"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (controller *CaldavController) GetTodo(ctx *gin.Context) {
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	list := controller.getAccessibleList(reqUser, ctx.Param("listID"), ctx)
+	if list == nil {
+		return
+	}
+	todo, ok := controller.getResourceTodo(list.ID, ctx.Param("resource"), ctx)
+	if !ok {
+		return
+	} else if todo == nil {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	ctx.Header("ETag", etag(todo))
+	ctx.Header("Last-Modified", todo.UpdatedAt.Time.UTC().Format(http.TimeFormat))
+	ctx.Header("Content-Type", "text/calendar; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	if err := ical.WriteTodo(ctx.Writer, ical.FromTodo(todo)); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to write todo: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+	}
+}
+
+// Checks the If-Match and If-None-Match headers against the todo, which is nil
+// if the resource does not exist. Returns false if they fail, in which case
+// the error is already pushed to gin.Context.
+func checkPreconditions(todo *db.Todo, ctx *gin.Context) bool {
+	matches := func(header string) bool {
+		if todo == nil {
+			return false
+		}
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag(todo) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifMatch := ctx.GetHeader("If-Match"); ifMatch != "" && !matches(ifMatch) {
+		ctx.Error(gterrors.ErrPreconditionFailed).SetType(gin.ErrorTypePublic)
+		return false
+	}
+	if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" && matches(ifNoneMatch) {
+		ctx.Error(gterrors.ErrPreconditionFailed).SetType(gin.ErrorTypePublic)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package caldav
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sends clients looking for the server to its root.
+func (controller *CaldavController) WellKnown(ctx *gin.Context) {
+	ctx.Redirect(http.StatusMovedPermanently, rootPath)
+}
+
+// Tells clients which parts of WebDAV and CalDAV are supported.
+func (controller *CaldavController) Options(ctx *gin.Context) {
+	ctx.Header("DAV", "1, 3, calendar-access")
+	ctx.Header("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *CaldavController) PropfindRoot(ctx *gin.Context) {
+	req := &propRequest{}
+	if ok := bindXML(req, ctx); !ok {
+		return
+	}
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	root := req.selectProps(rootProps())
+	root.href = rootPath
+	responses := []davResponse{root}
+	if wantsChildren(ctx) {
+		principal := req.selectProps(principalProps(reqUser))
+		principal.href = principalPath
+		home := req.selectProps(homeProps())
+		home.href = homePath
+		responses = append(responses, principal, home)
+	}
+	writeMultistatus(ctx, responses)
+}
+
+func (controller *CaldavController) PropfindPrincipal(ctx *gin.Context) {
+	req := &propRequest{}
+	if ok := bindXML(req, ctx); !ok {
+		return
+	}
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	principal := req.selectProps(principalProps(reqUser))
+	principal.href = principalPath
+	writeMultistatus(ctx, []davResponse{principal})
+}
+
+// Describes the collection of lists and, with depth 1, every list accessible
+// by the user.
+func (controller *CaldavController) PropfindHome(ctx *gin.Context) {
+	req := &propRequest{}
+	if ok := bindXML(req, ctx); !ok {
+		return
+	}
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	home := req.selectProps(homeProps())
+	home.href = homePath
+	responses := []davResponse{home}
+	if wantsChildren(ctx) {
+		args := &db.GetListsAccessibleByUserIdParams{UserID: reqUser.ID, IncludeArchived: true}
+		lists, err := controller.db.GetListsAccessibleByUserId(ctx, *args)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get lists", file, line, err, ctx)
+			return
+		}
+		listIds := make([]string, 0, len(lists))
+		for _, list := range lists {
+			listIds = append(listIds, list.ID)
+		}
+		todos, err := controller.db.GetTodosByListIds(ctx, listIds)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get todos", file, line, err, ctx)
+			return
+		}
+		todosByList := make(map[string][]db.Todo)
+		for _, todo := range todos {
+			todosByList[todo.ListID] = append(todosByList[todo.ListID], todo)
+		}
+		for _, list := range lists {
+			response := req.selectProps(listProps(reqUser, &list, todosByList[list.ID]))
+			response.href = listPath(list.ID)
+			responses = append(responses, response)
+		}
+	}
+	writeMultistatus(ctx, responses)
+}
+
+// Describes the list and, with depth 1, every todo in it.
+func (controller *CaldavController) PropfindList(ctx *gin.Context) {
+	req := &propRequest{}
+	if ok := bindXML(req, ctx); !ok {
+		return
+	}
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	list := controller.getAccessibleList(reqUser, ctx.Param("listID"), ctx)
+	if list == nil {
+		return
+	}
+	todos, err := controller.db.GetTodosByList(ctx, list.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get todos", file, line, err, ctx)
+		return
+	}
+
+	response := req.selectProps(listProps(reqUser, list, todos))
+	response.href = listPath(list.ID)
+	responses := []davResponse{response}
+	if wantsChildren(ctx) {
+		for _, todo := range todos {
+			props, err := todoProps(&todo, req.wantsData())
+			if err != nil {
+				_, file, line, _ := runtime.Caller(0)
+				mycontext.CtxAddGtInternalError("failed to write todo", file, line, err, ctx)
+				return
+			}
+			response := req.selectProps(props)
+			response.href = todoPath(&todo)
+			responses = append(responses, response)
+		}
+	}
+	writeMultistatus(ctx, responses)
+}
+
+func (controller *CaldavController) PropfindTodo(ctx *gin.Context) {
+	req := &propRequest{}
+	if ok := bindXML(req, ctx); !ok {
+		return
+	}
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	list := controller.getAccessibleList(reqUser, ctx.Param("listID"), ctx)
+	if list == nil {
+		return
+	}
+	todo, ok := controller.getResourceTodo(list.ID, ctx.Param("resource"), ctx)
+	if !ok {
+		return
+	} else if todo == nil {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	props, err := todoProps(todo, req.wantsData())
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to write todo", file, line, err, ctx)
+		return
+	}
+	response := req.selectProps(props)
+	response.href = todoPath(todo)
+	writeMultistatus(ctx, []davResponse{response})
+}
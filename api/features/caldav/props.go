@@ -0,0 +1,153 @@
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/util/ical"
+)
+
+const (
+	rootPath      = "/caldav/"
+	principalPath = "/caldav/principal/"
+	homePath      = "/caldav/lists/"
+)
+
+func listPath(listID string) string {
+	return homePath + url.PathEscape(listID) + "/"
+}
+
+// The name a todo is stored under in its list, the one its client chose or
+// "<id>.ics".
+func resourceName(todo *db.Todo) string {
+	if todo.IcalName.Valid {
+		return todo.IcalName.String
+	}
+	return todo.ID + ".ics"
+}
+
+func todoPath(todo *db.Todo) string {
+	return listPath(todo.ListID) + url.PathEscape(resourceName(todo))
+}
+
+// Changes whenever the todo does, as every update sets updated_at.
+func etag(todo *db.Todo) string {
+	return `"` + stamp(todo.UpdatedAt.Time) + `"`
+}
+
+func stamp(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro(), 36)
+}
+
+// Changes whenever the list or one of its todos is changed, added or removed,
+// which tells clients to sync the list.
+func ctag(list *db.List, todos []db.Todo) string {
+	sorted := slices.Clone(todos)
+	slices.SortFunc(sorted, func(a, b db.Todo) int { return strings.Compare(a.ID, b.ID) })
+	hash := sha256.New()
+	hash.Write([]byte(list.ID + stamp(list.UpdatedAt.Time)))
+	for _, todo := range sorted {
+		hash.Write([]byte(todo.ID + stamp(todo.UpdatedAt.Time)))
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// Properties every resource has that point clients to the principal and the
+// lists.
+func discoveryProps() []davProp {
+	return []davProp{
+		prop(nsDav, "current-user-principal", href(principalPath)),
+		prop(nsDav, "principal-URL", href(principalPath)),
+		prop(nsCaldav, "calendar-home-set", href(homePath)),
+		prop(nsDav, "owner", href(principalPath)),
+	}
+}
+
+func rootProps() []davProp {
+	return append(
+		[]davProp{
+			prop(nsDav, "resourcetype", "<d:collection/>"),
+			prop(nsDav, "displayname", "go-todo"),
+		},
+		discoveryProps()...,
+	)
+}
+
+func principalProps(reqUser *db.User) []davProp {
+	return append(
+		[]davProp{
+			prop(nsDav, "resourcetype", "<d:collection/><d:principal/>"),
+			prop(nsDav, "displayname", text(reqUser.Username)),
+		},
+		discoveryProps()...,
+	)
+}
+
+func homeProps() []davProp {
+	return append(
+		[]davProp{
+			prop(nsDav, "resourcetype", "<d:collection/>"),
+			prop(nsDav, "displayname", "Lists"),
+		},
+		discoveryProps()...,
+	)
+}
+
+// Lists are calendars of VTODOs. Archived lists are read-only.
+func listProps(reqUser *db.User, list *db.List, todos []db.Todo) []davProp {
+	privileges := "<d:privilege><d:read/></d:privilege>"
+	if !list.ArchivedAt.Valid {
+		privileges += "<d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege>" +
+			"<d:privilege><d:bind/></d:privilege>" +
+			"<d:privilege><d:unbind/></d:privilege>"
+	}
+	props := []davProp{
+		prop(nsDav, "resourcetype", "<d:collection/><c:calendar/>"),
+		prop(nsDav, "displayname", text(list.Title)),
+		prop(nsCaldav, "supported-calendar-component-set", `<c:comp name="VTODO"/>`),
+		prop(nsDav, "current-user-privilege-set", privileges),
+		prop(nsCalendar, "getctag", ctag(list, todos)),
+	}
+	if list.Description.Valid && list.Description.String != "" {
+		props = append(props, prop(nsCaldav, "calendar-description", text(list.Description.String)))
+	}
+	return append(props, discoveryProps()...)
+}
+
+// The properties of a todo. calendar-data is only added with withData set, as
+// writing it is the expensive part.
+func todoProps(todo *db.Todo, withData bool) ([]davProp, error) {
+	props := []davProp{
+		prop(nsDav, "resourcetype", ""),
+		prop(nsDav, "getetag", text(etag(todo))),
+		prop(nsDav, "getcontenttype", "text/calendar; charset=utf-8; component=VTODO"),
+		prop(nsDav, "getlastmodified", todo.UpdatedAt.Time.UTC().Format(http.TimeFormat)),
+	}
+	if withData {
+		var data bytes.Buffer
+		if err := ical.WriteTodo(&data, ical.FromTodo(todo)); err != nil {
+			return nil, err
+		}
+		props = append(props, prop(nsCaldav, "calendar-data", text(data.String())))
+	}
+	return props, nil
+}
+
+// Whether calendar-data is asked for by name.
+func (req *propRequest) wantsData() bool {
+	for _, name := range req.Prop.Names {
+		if name.XMLName.Space == nsCaldav && name.XMLName.Local == "calendar-data" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package caldav
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"slices"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/ical"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Creates or replaces the todo stored under the resource name. Both go through
+// the same logic as the todo endpoints.
+func (controller *CaldavController) PutTodo(ctx *gin.Context) {
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	resource := ctx.Param("resource")
+	list := controller.getAccessibleList(reqUser, ctx.Param("listID"), ctx)
+	if list == nil {
+		return
+	}
+	todo, ok := controller.getResourceTodo(list.ID, resource, ctx)
+	if !ok {
+		return
+	}
+	if ok := checkPreconditions(todo, ctx); !ok {
+		return
+	}
+
+	loc, err := database.GetUserLocation(ctx, controller.db, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user time zone", file, line, err, ctx)
+		return
+	}
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodySize)
+	parsed, err := ical.ParseTodo(body, loc)
+	if err != nil {
+		ctx.Error(gterrors.NewGtValueError(resource, err.Error()))
+		return
+	}
+
+	tags := []string{}
+	for _, category := range parsed.Categories {
+		if validate.Tag(category) && !slices.Contains(tags, category) {
+			tags = append(tags, category)
+		}
+	}
+	recurrence := ""
+	if validate.Recurrence(parsed.RRule) {
+		recurrence = parsed.RRule
+	}
+	var completeBefore *time.Time
+	var dueDate *string
+	if parsed.Due != nil && parsed.AllDay {
+		date := parsed.Due.Format(time.DateOnly)
+		dueDate = &date
+	} else if parsed.Due != nil {
+		completeBefore = parsed.Due
+	}
+
+	if todo != nil {
+		// The client sends the whole todo, so anything missing is removed.
+		if completeBefore == nil && dueDate == nil {
+			epoch := time.Unix(0, 0).UTC()
+			noDate := ""
+			completeBefore = &epoch
+			dueDate = &noDate
+		}
+		payload := &schemas.UpdateTodo{
+			Title:          &parsed.Summary,
+			Description:    &parsed.Description,
+			Completed:      &parsed.Completed,
+			Priority:       &parsed.Priority,
+			Tags:           tags,
+			CompleteBefore: completeBefore,
+			DueDate:        dueDate,
+			Recurrence:     &recurrence,
+		}
+		if todo = controller.todoController.UpdateTodoAs(reqUser, list.ID, todo.ID, payload, ctx); todo == nil {
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	payload := &schemas.CreateTodo{
+		Title:          parsed.Summary,
+		CompleteBefore: completeBefore,
+		DueDate:        dueDate,
+		Priority:       &parsed.Priority,
+		Tags:           tags,
+	}
+	if parsed.Description != "" {
+		payload.Description = &parsed.Description
+	}
+	if recurrence != "" {
+		payload.Recurrence = &recurrence
+	}
+	if parsed.RelatedTo != "" {
+		args := &db.GetTodoByIcalUidParams{ListID: list.ID, Uid: parsed.RelatedTo}
+		parent, err := controller.db.GetTodoByIcalUid(ctx, *args)
+		if err == nil {
+			payload.ParentID = &parent.ID
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get parent todo", file, line, err, ctx)
+			return
+		}
+	}
+	// Keeps the UID and name the client chose, so it finds the todo again.
+	// They are set with the todo, so that a failure leaves no todo behind
+	// that a retry would duplicate.
+	setNames := func(qtx *db.Queries, todo *db.Todo) error {
+		args := &db.SetTodoIcalNamesParams{
+			IcalUid:  pgtype.Text{String: parsed.UID, Valid: true},
+			IcalName: pgtype.Text{String: resource, Valid: true},
+			ID:       todo.ID,
+		}
+		_, err := qtx.SetTodoIcalNames(ctx, *args)
+		return err
+	}
+	if todo = controller.todoController.CreateTodoWith(reqUser, list.ID, payload, setNames, ctx); todo == nil {
+		return
+	}
+	if parsed.Completed {
+		completed := &schemas.UpdateTodo{Completed: &parsed.Completed}
+		if todo = controller.todoController.UpdateTodoAs(reqUser, list.ID, todo.ID, completed, ctx); todo == nil {
+			return
+		}
+	}
+	// No ETag is sent, as the stored todo is not byte for byte what the client
+	// sent. Clients fetch it again instead.
+	ctx.Status(http.StatusCreated)
+}
@@ -0,0 +1,117 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+
+	"go-todo/gterrors"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+type report struct {
+	XMLName xml.Name
+	propRequest
+	Hrefs  []string `xml:"DAV: href"`
+	Filter struct {
+		CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// Whether todos can match the filter. Only the component is looked at, other
+// filters like time ranges match every todo.
+func (filter *compFilter) matchesTodos() bool {
+	if filter.Name == "" || len(filter.CompFilters) == 0 {
+		return true
+	}
+	for _, child := range filter.CompFilters {
+		if strings.EqualFold(child.Name, "VTODO") {
+			return true
+		}
+	}
+	return false
+}
+
+// Answers the calendar-query and calendar-multiget reports on a list.
+func (controller *CaldavController) Report(ctx *gin.Context) {
+	req := &report{}
+	if ok := bindXML(req, ctx); !ok {
+		return
+	}
+	reqUser := controller.getRequester(ctx)
+	if reqUser == nil {
+		return
+	}
+
+	list := controller.getAccessibleList(reqUser, ctx.Param("listID"), ctx)
+	if list == nil {
+		return
+	}
+
+	responses := []davResponse{}
+	switch {
+	case req.XMLName.Space == nsCaldav && req.XMLName.Local == "calendar-query":
+		if !req.Filter.CompFilter.matchesTodos() {
+			break
+		}
+		todos, err := controller.db.GetTodosByList(ctx, list.ID)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get todos", file, line, err, ctx)
+			return
+		}
+		for _, todo := range todos {
+			props, err := todoProps(&todo, req.wantsData())
+			if err != nil {
+				_, file, line, _ := runtime.Caller(0)
+				mycontext.CtxAddGtInternalError("failed to write todo", file, line, err, ctx)
+				return
+			}
+			response := req.selectProps(props)
+			response.href = todoPath(&todo)
+			responses = append(responses, response)
+		}
+	case req.XMLName.Space == nsCaldav && req.XMLName.Local == "calendar-multiget":
+		for _, href := range req.Hrefs {
+			// Hrefs may be full URLs.
+			if u, err := url.Parse(href); err == nil {
+				href = u.Path
+			}
+			dir, resource := path.Split(href)
+			if dir != listPath(list.ID) {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			todo, ok := controller.getResourceTodo(list.ID, resource, ctx)
+			if !ok {
+				return
+			} else if todo == nil {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			props, err := todoProps(todo, req.wantsData())
+			if err != nil {
+				_, file, line, _ := runtime.Caller(0)
+				mycontext.CtxAddGtInternalError("failed to write todo", file, line, err, ctx)
+				return
+			}
+			response := req.selectProps(props)
+			response.href = todoPath(todo)
+			responses = append(responses, response)
+		}
+	default:
+		ctx.Error(gterrors.NewGtValueError(req.XMLName.Local, "unsupported report")).SetType(gin.ErrorTypePublic)
+		return
+	}
+	writeMultistatus(ctx, responses)
+}
@@ -0,0 +1,41 @@
+package caldav
+
+import (
+	"go-todo/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	methodPropfind = "PROPFIND"
+	methodReport   = "REPORT"
+)
+
+type CaldavRoutes struct {
+	caldavController *CaldavController
+}
+
+func NewRoutes(caldavController *CaldavController) *CaldavRoutes {
+	return &CaldavRoutes{caldavController}
+}
+
+// Registers the CalDAV server under /caldav. Clients look for it at
+// /.well-known/caldav, so rg should be the root group.
+func (routes *CaldavRoutes) Register(rg *gin.RouterGroup) {
+	rg.GET("/.well-known/caldav", routes.caldavController.WellKnown)
+	rg.Handle(methodPropfind, "/.well-known/caldav", routes.caldavController.WellKnown)
+	rg.OPTIONS("/caldav/*path", routes.caldavController.Options)
+
+	router := rg.Group("/caldav")
+	// CalDAV clients cannot refresh jwts, they send an app password instead.
+	router.Use(middleware.AppPasswordAuthMiddleware(routes.caldavController.db))
+	router.Handle(methodPropfind, "/", routes.caldavController.PropfindRoot)
+	router.Handle(methodPropfind, "/principal/", routes.caldavController.PropfindPrincipal)
+	router.Handle(methodPropfind, "/lists/", routes.caldavController.PropfindHome)
+	router.Handle(methodPropfind, "/lists/:listID/", routes.caldavController.PropfindList)
+	router.Handle(methodPropfind, "/lists/:listID/:resource", routes.caldavController.PropfindTodo)
+	router.Handle(methodReport, "/lists/:listID/", routes.caldavController.Report)
+	router.GET("/lists/:listID/:resource", routes.caldavController.GetTodo)
+	router.PUT("/lists/:listID/:resource", routes.caldavController.PutTodo)
+	router.DELETE("/lists/:listID/:resource", routes.caldavController.DeleteTodo)
+}
@@ -0,0 +1,15 @@
+package calendar
+
+import (
+	"context"
+	db "go-todo/db/sqlc"
+)
+
+type CalendarController struct {
+	db  *db.Queries
+	ctx context.Context
+}
+
+func NewController(db *db.Queries, ctx context.Context) *CalendarController {
+	return &CalendarController{db: db, ctx: ctx}
+}
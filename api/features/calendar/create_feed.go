@@ -0,0 +1,108 @@
+package calendar
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"slices"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Creates a calendar feed of every list of the requester, or of one list. The
+// token is only returned in this response.
+func (controller *CalendarController) CreateFeed(ctx *gin.Context) {
+	payload := &schemas.CreateCalendarFeed{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	listID := pgtype.Text{}
+	if payload.ListID != nil {
+		listIds, err := controller.db.GetListIdsAccessible(ctx, reqUser.ID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError(
+				"failed to get list accessible by user",
+				file,
+				line,
+				err,
+				ctx,
+			)
+			return
+		}
+		if !slices.Contains(listIds, *payload.ListID) {
+			logging.LogSecurityEvent(
+				logging.SecurityScoreLow,
+				logging.SecurityEventForbiddenAction,
+				ctx.FullPath(),
+				*payload.ListID,
+				reqUser.ID,
+			)
+			ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+			return
+		}
+		listID = pgtype.Text{String: *payload.ListID, Valid: true}
+	}
+
+	token, tokenHash, err := secret.Generate("")
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate feed token", file, line, err, ctx)
+		return
+	}
+
+	args := &db.CreateCalendarFeedParams{
+		ID:            uuid.New().String(),
+		UserID:        reqUser.ID,
+		ListID:        listID,
+		TokenHash:     tokenHash,
+		IncludeEvents: payload.IncludeEvents,
+	}
+	feed, err := controller.db.CreateCalendarFeed(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create calendar feed", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&feed,
+		nil,
+		logging.ObjectEventSubCalendarFeed,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "feed": feedResponse(&feed, token)})
+}
@@ -0,0 +1,92 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/ical"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Serves the iCalendar feed for the token in the path, like
+// /calendar/<token>.ics. Every todo with a due date in the feed's lists is a
+// VTODO.
+func (controller *CalendarController) ReadFeed(ctx *gin.Context) {
+	token, ok := strings.CutSuffix(ctx.Param("token"), ".ics")
+	if !ok {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	feed, err := controller.db.GetCalendarFeedByTokenHash(ctx, secret.Hash(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logging.LogSecurityEvent(
+				logging.SecurityScoreLow,
+				logging.SecurityEventCalendarTokenUnknown,
+				ctx.FullPath(),
+				"calendar feed",
+				ctx.ClientIP(),
+			)
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get calendar feed", file, line, err, ctx)
+		return
+	}
+
+	name := "Todos"
+	if feed.ListID.Valid {
+		list, err := controller.db.GetList(ctx, feed.ListID.String)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get list", file, line, err, ctx)
+			return
+		}
+		name = list.Title
+	}
+
+	args := &db.GetDueTodosForCalendarParams{UserID: feed.UserID, ListID: feed.ListID}
+	rows, err := controller.db.GetDueTodosForCalendar(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get todos", file, line, err, ctx)
+		return
+	}
+	todos := make([]*ical.Todo, 0, len(rows))
+	for _, row := range rows {
+		todos = append(todos, ical.FromTodo(&row.Todo))
+	}
+
+	if err := controller.db.TouchCalendarFeed(ctx, feed.ID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to update calendar feed last use: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+	}
+
+	ctx.Header("Content-Type", "text/calendar; charset=utf-8")
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Status(http.StatusOK)
+	if err := ical.WriteCalendar(ctx.Writer, name, todos, feed.IncludeEvents); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to write calendar feed: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+	}
+}
@@ -0,0 +1,48 @@
+package calendar
+
+import (
+	"net/http"
+	"runtime"
+
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the calendar feeds of the requester. Tokens are only shown when a
+// feed is created or its token regenerated.
+func (controller *CalendarController) ReadFeeds(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	feeds, err := controller.db.GetCalendarFeedsByUserId(ctx, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get calendar feeds", file, line, err, ctx)
+		return
+	}
+
+	response := make([]gin.H, 0, len(feeds))
+	for _, feed := range feeds {
+		response = append(response, feedResponse(&feed, ""))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "feeds": response})
+}
@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/secret"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Replaces the token of the feed. The old feed url stops working at once.
+func (controller *CalendarController) RegenerateFeedToken(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	feedID := ctx.Param("feedID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	oldFeed := controller.getOwnedFeed(reqUser, feedID, ctx)
+	if oldFeed == nil {
+		return
+	}
+
+	token, tokenHash, err := secret.Generate("")
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to generate feed token", file, line, err, ctx)
+		return
+	}
+	args := &db.UpdateCalendarFeedTokenParams{TokenHash: tokenHash, ID: oldFeed.ID}
+	feed, err := controller.db.UpdateCalendarFeedToken(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to update calendar feed", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&feed,
+		oldFeed,
+		logging.ObjectEventSubCalendarFeed,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "feed": feedResponse(&feed, token)})
+}
@@ -0,0 +1,24 @@
+package calendar
+
+import (
+	db "go-todo/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the feed without its token hash. The token and the path of the feed
+// are only added when token is given.
+func feedResponse(feed *db.CalendarFeed, token string) gin.H {
+	response := gin.H{
+		"id":             feed.ID,
+		"list_id":        feed.ListID,
+		"include_events": feed.IncludeEvents,
+		"created_at":     feed.CreatedAt,
+		"last_used_at":   feed.LastUsedAt,
+	}
+	if token != "" {
+		response["token"] = token
+		response["path"] = "/api/v1/calendar/" + token + ".ics"
+	}
+	return response
+}
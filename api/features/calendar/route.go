@@ -0,0 +1,33 @@
+package calendar
+
+import (
+	"go-todo/middleware"
+	"go-todo/util/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarRoutes struct {
+	calendarController *CalendarController
+}
+
+func NewRoutes(calendarController *CalendarController) *CalendarRoutes {
+	return &CalendarRoutes{calendarController}
+}
+
+func (routes *CalendarRoutes) Register(rg *gin.RouterGroup) {
+	router := rg.Group("/calendar")
+	// Calendar apps cannot send a jwt, the feed token in the path authenticates
+	// them instead.
+	router.GET("/:token", routes.calendarController.ReadFeed)
+
+	feedRouter := router.Group("/feed")
+	feedRouter.Use(
+		middleware.JwtAuthMiddleware(routes.calendarController.db),
+		middleware.RequireScopes(jwt.ScopeUsersAdmin),
+	)
+	feedRouter.GET("/", routes.calendarController.ReadFeeds)
+	feedRouter.POST("/", routes.calendarController.CreateFeed)
+	feedRouter.POST("/:feedID/token", routes.calendarController.RegenerateFeedToken)
+	feedRouter.DELETE("/:feedID", routes.calendarController.DeleteFeed)
+}
@@ -0,0 +1,46 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Gets the folder if the user owns it or may manage all lists. Returns nil if
+// not, in which case the error is already pushed to gin.Context.
+func (controller *FolderController) getOwnedFolder(
+	reqUser *db.User,
+	folderID string,
+	ctx *gin.Context,
+) *db.Folder {
+	folder, err := controller.db.GetFolder(ctx, folderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return nil
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get folder", file, line, err, ctx)
+		return nil
+	}
+	if folder.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("folder: %v", folderID),
+		ctx,
+	) {
+		return nil
+	}
+	return &folder
+}
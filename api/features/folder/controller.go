@@ -0,0 +1,18 @@
+package folder
+
+import (
+	"context"
+	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FolderController struct {
+	db   *db.Queries
+	pool *pgxpool.Pool
+	ctx  context.Context
+}
+
+func NewController(db *db.Queries, pool *pgxpool.Pool, ctx context.Context) *FolderController {
+	return &FolderController{db: db, pool: pool, ctx: ctx}
+}
@@ -0,0 +1,81 @@
+package folder
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (controller *FolderController) CreateFolder(ctx *gin.Context) {
+	payload := &schemas.CreateFolder{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	if ok := validate.LengthTitle(payload.Title); !ok {
+		ctx.Error(gterrors.NewGtValueError(payload.Title, "title too long"))
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	parentID := pgtype.Text{}
+	if payload.ParentID != nil {
+		parent := controller.getOwnedFolder(reqUser, *payload.ParentID, ctx)
+		if parent == nil {
+			return
+		}
+		parentID = pgtype.Text{String: parent.ID, Valid: true}
+	}
+
+	args := &db.CreateFolderParams{
+		ID:       uuid.New().String(),
+		UserID:   reqUser.ID,
+		ParentID: parentID,
+		Title:    payload.Title,
+	}
+	folder, err := controller.db.CreateFolder(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create folder", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&folder,
+		nil,
+		logging.ObjectEventSubFolder,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "folder": folder})
+}
@@ -0,0 +1,85 @@
+package folder
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Deletes the folder with its subfolders. Lists inside are moved to the root
+// and lose the shares they got through the deleted folders.
+func (controller *FolderController) DeleteFolder(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	folderID := ctx.Param("folderID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	folder := controller.getOwnedFolder(reqUser, folderID, ctx)
+	if folder == nil {
+		return
+	}
+
+	var rows int64
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		folderIds, listIds, err := subtreeListIds(ctx, qtx, folder.ID)
+		if err != nil {
+			return err
+		}
+		oldUserIds, err := chainShareUserIds(ctx, qtx, pgtype.Text{String: folder.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		subtreeUserIds, err := qtx.GetFolderShareUserIds(ctx, folderIds)
+		if err != nil {
+			return fmt.Errorf("failed to get folder shares: %w", err)
+		}
+		oldUserIds = append(oldUserIds, subtreeUserIds...)
+
+		rows, err = qtx.DeleteFolder(ctx, folder.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete folder: %w", err)
+		}
+		return syncListShares(ctx, qtx, listIds, oldUserIds, []string{})
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete folder", file, line, err, ctx)
+		return
+	}
+
+	if rows != 0 {
+		logging.LogObjectEvent(
+			ctx.FullPath(),
+			ctx.ClientIP(),
+			logging.ObjectEventDelete,
+			reqUser,
+			"deleted",
+			folder.ID,
+			logging.ObjectEventSubFolder,
+		)
+	}
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
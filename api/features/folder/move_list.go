@@ -0,0 +1,127 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Moves the list into the folder. The list must be owned by the owner of the
+// folder and gets shared with the users the folder is shared with.
+func (controller *FolderController) MoveList(ctx *gin.Context) {
+	controller.setListFolder(true, ctx)
+}
+
+// Moves the list out of the folder to the root.
+func (controller *FolderController) RemoveList(ctx *gin.Context) {
+	controller.setListFolder(false, ctx)
+}
+
+func (controller *FolderController) setListFolder(move bool, ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	folderID := ctx.Param("folderID")
+	listID := ctx.Param("listID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	folder := controller.getOwnedFolder(reqUser, folderID, ctx)
+	if folder == nil {
+		return
+	}
+
+	oldList, err := controller.db.GetList(ctx, listID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get list", file, line, err, ctx)
+		return
+	}
+	if oldList.UserID != folder.UserID {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventForbiddenAction,
+			ctx.FullPath(),
+			fmt.Sprintf("folder: %v, list: %v", folderID, listID),
+			reqUser.ID,
+		)
+		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	newFolderID := pgtype.Text{String: folder.ID, Valid: true}
+	if !move {
+		if oldList.FolderID != newFolderID {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		newFolderID = pgtype.Text{}
+	}
+	if oldList.FolderID == newFolderID {
+		ctx.JSON(http.StatusOK, gin.H{"status": "not-modified", "list": oldList})
+		return
+	}
+
+	var newList db.List
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		oldUserIds, err := chainShareUserIds(ctx, qtx, oldList.FolderID)
+		if err != nil {
+			return err
+		}
+		newUserIds, err := chainShareUserIds(ctx, qtx, newFolderID)
+		if err != nil {
+			return err
+		}
+
+		args := &db.SetListFolderParams{FolderID: newFolderID, ID: oldList.ID}
+		newList, err = qtx.SetListFolder(ctx, *args)
+		if err != nil {
+			return fmt.Errorf("failed to move list: %w", err)
+		}
+		return syncListShares(ctx, qtx, []string{newList.ID}, oldUserIds, newUserIds)
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to move list", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&newList,
+		&oldList,
+		logging.ObjectEventSubList,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "list": newList})
+}
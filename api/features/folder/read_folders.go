@@ -0,0 +1,101 @@
+package folder
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the folder trees of the requester with their lists, the folders
+// shared with the requester and the owned lists that are in no folder.
+func (controller *FolderController) ReadFolders(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	includeArchived, err := strconv.ParseBool(ctx.DefaultQuery("include_archived", "false"))
+	if err != nil {
+		ctx.Error(gterrors.NewGtValueError(
+			ctx.Query("include_archived"),
+			"include_archived must be a boolean",
+		))
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	ownedFolders, err := controller.db.GetFoldersByOwnerId(ctx, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get folders", file, line, err, ctx)
+		return
+	}
+	sharedFolders, err := controller.db.GetFoldersSharedWithUserId(ctx, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get shared folders", file, line, err, ctx)
+		return
+	}
+
+	folderIds := make([]string, 0, len(ownedFolders)+len(sharedFolders))
+	for _, folder := range ownedFolders {
+		folderIds = append(folderIds, folder.ID)
+	}
+	for _, folder := range sharedFolders {
+		folderIds = append(folderIds, folder.ID)
+	}
+	listArgs := &db.GetListsByFolderIdsParams{
+		FolderIds:       folderIds,
+		IncludeArchived: includeArchived,
+	}
+	folderLists, err := controller.db.GetListsByFolderIds(ctx, *listArgs)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get lists in folders", file, line, err, ctx)
+		return
+	}
+
+	ownedLists, err := controller.db.GetListsByOwnerId(
+		ctx,
+		db.GetListsByOwnerIdParams{UserID: reqUser.ID, IncludeArchived: includeArchived},
+	)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get lists", file, line, err, ctx)
+		return
+	}
+	rootLists := make([]db.List, 0, len(ownedLists))
+	for _, list := range ownedLists {
+		if !list.FolderID.Valid {
+			rootLists = append(rootLists, list)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"folders": buildTree(ownedFolders, folderLists),
+		"shared":  buildTree(sharedFolders, folderLists),
+		"lists":   rootLists,
+	})
+}
@@ -0,0 +1,36 @@
+package folder
+
+import (
+	"go-todo/middleware"
+	"go-todo/util/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderRoutes struct {
+	folderController *FolderController
+}
+
+func NewRoutes(folderController *FolderController) *FolderRoutes {
+	return &FolderRoutes{folderController}
+}
+
+func (routes *FolderRoutes) Register(rg *gin.RouterGroup) {
+	router := rg.Group("/folder")
+
+	router.Use(middleware.JwtAuthMiddleware(routes.folderController.db))
+	readLists := middleware.RequireScopes(jwt.ScopeListsRead)
+	writeLists := middleware.RequireScopes(jwt.ScopeListsWrite)
+
+	router.GET("/", readLists, routes.folderController.ReadFolders)
+	router.POST("/", writeLists, routes.folderController.CreateFolder)
+	router.PATCH("/:folderID", writeLists, routes.folderController.UpdateFolder)
+	router.DELETE("/:folderID", writeLists, routes.folderController.DeleteFolder)
+
+	router.PUT("/:folderID/list/:listID", writeLists, routes.folderController.MoveList)
+	router.DELETE("/:folderID/list/:listID", writeLists, routes.folderController.RemoveList)
+
+	router.GET("/:folderID/share", readLists, routes.folderController.ReadFolderShares)
+	router.POST("/:folderID/share", writeLists, routes.folderController.CreateFolderShare)
+	router.DELETE("/:folderID/share/:userID", writeLists, routes.folderController.DeleteFolderShare)
+}
@@ -0,0 +1,218 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+func (controller *FolderController) ReadFolderShares(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	folderID := ctx.Param("folderID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	folder := controller.getOwnedFolder(reqUser, folderID, ctx)
+	if folder == nil {
+		return
+	}
+
+	shares, err := controller.db.GetFolderShares(ctx, folder.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get folder shares", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "shares": shares})
+}
+
+// Shares the folder, and so every list in it and its subfolders, with the
+// user.
+func (controller *FolderController) CreateFolderShare(ctx *gin.Context) {
+	payload := &schemas.CreateFolderShare{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	folderID := ctx.Param("folderID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	folder := controller.getOwnedFolder(reqUser, folderID, ctx)
+	if folder == nil {
+		return
+	}
+
+	user, err := controller.db.GetUserById(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user", file, line, err, ctx)
+		return
+	}
+	if user.ID == folder.UserID {
+		ctx.Error(gterrors.NewGtValueError(user.ID, "folder cannot be shared with its owner"))
+		return
+	}
+
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		args := &db.CreateFolderShareParams{FolderID: folder.ID, UserID: user.ID}
+		if err := qtx.CreateFolderShare(ctx, *args); err != nil {
+			return fmt.Errorf("failed to share folder: %w", err)
+		}
+		_, listIds, err := subtreeListIds(ctx, qtx, folder.ID)
+		if err != nil {
+			return err
+		}
+		return syncListShares(ctx, qtx, listIds, []string{}, []string{user.ID})
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to share folder", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		folder,
+		nil,
+		logging.ObjectEventSubFolder,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created"})
+}
+
+// Unshares the folder. The user keeps access to lists still shared with them
+// through an ancestor folder.
+func (controller *FolderController) DeleteFolderShare(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	folderID := ctx.Param("folderID")
+	userID := ctx.Param("userID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	folder := controller.getOwnedFolder(reqUser, folderID, ctx)
+	if folder == nil {
+		return
+	}
+
+	var rows int64
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		args := &db.DeleteFolderShareParams{FolderID: folder.ID, UserID: userID}
+		rows, err = qtx.DeleteFolderShare(ctx, *args)
+		if err != nil {
+			return fmt.Errorf("failed to unshare folder: %w", err)
+		}
+		if rows == 0 {
+			return nil
+		}
+
+		folderIds, err := qtx.GetFolderSubtreeIds(ctx, folder.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get subfolders: %w", err)
+		}
+		listArgs := &db.GetListsByFolderIdsParams{FolderIds: folderIds, IncludeArchived: true}
+		lists, err := qtx.GetListsByFolderIds(ctx, *listArgs)
+		if err != nil {
+			return fmt.Errorf("failed to get lists in folders: %w", err)
+		}
+		for _, list := range lists {
+			userIds, err := chainShareUserIds(ctx, qtx, list.FolderID)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(userIds, userID) {
+				continue
+			}
+			shareArgs := &db.DeleteFolderListSharesParams{ListID: list.ID, UserIds: []string{userID}}
+			if err := qtx.DeleteFolderListShares(ctx, *shareArgs); err != nil {
+				return fmt.Errorf("failed to remove list share: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to unshare folder", file, line, err, ctx)
+		return
+	}
+
+	if rows == 0 {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		folder,
+		nil,
+		logging.ObjectEventSubFolder,
+	)
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
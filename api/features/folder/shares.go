@@ -0,0 +1,82 @@
+package folder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Sharing a folder shares every list inside it, and inside its subfolders,
+// through list_shares marked via_folder. These keep them in sync when folders
+// or lists move around, without touching shares made on a list itself.
+
+// Returns the users the folder or any of its ancestors is shared with.
+func chainShareUserIds(ctx context.Context, q *db.Queries, folderID pgtype.Text) ([]string, error) {
+	if !folderID.Valid {
+		return []string{}, nil
+	}
+	ancestorIds, err := q.GetFolderAncestorIds(ctx, folderID.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder ancestors: %w", err)
+	}
+	userIds, err := q.GetFolderShareUserIds(ctx, ancestorIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder shares: %w", err)
+	}
+	return userIds, nil
+}
+
+// Removes the shares of the lists with users in oldUserIds that are not in
+// newUserIds, and shares the lists with every user in newUserIds.
+func syncListShares(
+	ctx context.Context,
+	q *db.Queries,
+	listIds []string,
+	oldUserIds []string,
+	newUserIds []string,
+) error {
+	removedUserIds := make([]string, 0, len(oldUserIds))
+	for _, userID := range oldUserIds {
+		if !slices.Contains(newUserIds, userID) {
+			removedUserIds = append(removedUserIds, userID)
+		}
+	}
+
+	for _, listID := range listIds {
+		if len(removedUserIds) > 0 {
+			args := &db.DeleteFolderListSharesParams{ListID: listID, UserIds: removedUserIds}
+			if err := q.DeleteFolderListShares(ctx, *args); err != nil {
+				return fmt.Errorf("failed to remove list shares: %w", err)
+			}
+		}
+		for _, userID := range newUserIds {
+			args := &db.CreateFolderListShareParams{ListID: listID, UserID: userID}
+			if err := q.CreateFolderListShare(ctx, *args); err != nil {
+				return fmt.Errorf("failed to share list: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the ids of the lists in the folder and its subfolders.
+func subtreeListIds(ctx context.Context, q *db.Queries, folderID string) ([]string, []string, error) {
+	folderIds, err := q.GetFolderSubtreeIds(ctx, folderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get subfolders: %w", err)
+	}
+	args := &db.GetListsByFolderIdsParams{FolderIds: folderIds, IncludeArchived: true}
+	lists, err := q.GetListsByFolderIds(ctx, *args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get lists in folders: %w", err)
+	}
+	listIds := make([]string, 0, len(lists))
+	for _, list := range lists {
+		listIds = append(listIds, list.ID)
+	}
+	return folderIds, listIds, nil
+}
@@ -0,0 +1,40 @@
+package folder
+
+import (
+	db "go-todo/db/sqlc"
+)
+
+type folderNode struct {
+	db.Folder
+	Lists    []db.List     `json:"lists"`
+	Children []*folderNode `json:"children"`
+}
+
+// Builds the folder trees with lists placed in their folders. Folders whose
+// parent is not among folders become roots.
+func buildTree(folders []db.Folder, lists []db.List) []*folderNode {
+	nodes := make(map[string]*folderNode, len(folders))
+	for _, folder := range folders {
+		nodes[folder.ID] = &folderNode{
+			Folder:   folder,
+			Lists:    []db.List{},
+			Children: []*folderNode{},
+		}
+	}
+	for _, list := range lists {
+		if node, ok := nodes[list.FolderID.String]; ok && list.FolderID.Valid {
+			node.Lists = append(node.Lists, list)
+		}
+	}
+
+	roots := []*folderNode{}
+	for _, folder := range folders {
+		node := nodes[folder.ID]
+		if parent, ok := nodes[folder.ParentID.String]; ok && folder.ParentID.Valid {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,146 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Renames the folder or moves it under another folder. Shares of the lists
+// inside follow the folder to its new place.
+func (controller *FolderController) UpdateFolder(ctx *gin.Context) {
+	var payload *schemas.UpdateFolder
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	} else if payload.Title == nil && payload.ParentID == nil {
+		ctx.Error(errors.New("either title or parent_id is required")).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	folderID := ctx.Param("folderID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	oldFolder := controller.getOwnedFolder(reqUser, folderID, ctx)
+	if oldFolder == nil {
+		return
+	}
+
+	title := oldFolder.Title
+	parentID := oldFolder.ParentID
+	if payload.Title != nil {
+		title = *payload.Title
+	}
+	if !validate.LengthTitle(title) {
+		ctx.Error(gterrors.NewGtValueError(title, "title too long"))
+		return
+	}
+	if payload.ParentID != nil && *payload.ParentID == "" {
+		parentID = pgtype.Text{}
+	} else if payload.ParentID != nil {
+		parent := controller.getOwnedFolder(reqUser, *payload.ParentID, ctx)
+		if parent == nil {
+			return
+		}
+		if parent.UserID != oldFolder.UserID {
+			ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+			return
+		}
+		ancestorIds, err := controller.db.GetFolderAncestorIds(ctx, parent.ID)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get folder ancestors", file, line, err, ctx)
+			return
+		}
+		if slices.Contains(ancestorIds, oldFolder.ID) {
+			ctx.Error(gterrors.NewGtValueError(
+				parent.ID,
+				"folder cannot be moved inside itself",
+			))
+			return
+		}
+		parentID = pgtype.Text{String: parent.ID, Valid: true}
+	}
+
+	var newFolder db.Folder
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		oldUserIds, err := chainShareUserIds(ctx, qtx, oldFolder.ParentID)
+		if err != nil {
+			return err
+		}
+
+		args := &db.UpdateFolderParams{
+			Title:    title,
+			ParentID: parentID,
+			ID:       oldFolder.ID,
+		}
+		newFolder, err = qtx.UpdateFolder(ctx, *args)
+		if err != nil {
+			return fmt.Errorf("failed to update folder: %w", err)
+		}
+		if newFolder.ParentID == oldFolder.ParentID {
+			return nil
+		}
+
+		folderIds, listIds, err := subtreeListIds(ctx, qtx, newFolder.ID)
+		if err != nil {
+			return err
+		}
+		newUserIds, err := chainShareUserIds(ctx, qtx, newFolder.ParentID)
+		if err != nil {
+			return err
+		}
+		// Shares of the moved folder and its subfolders stay in place.
+		subtreeUserIds, err := qtx.GetFolderShareUserIds(ctx, folderIds)
+		if err != nil {
+			return fmt.Errorf("failed to get folder shares: %w", err)
+		}
+		newUserIds = append(newUserIds, subtreeUserIds...)
+		return syncListShares(ctx, qtx, listIds, oldUserIds, newUserIds)
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to update folder", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&newFolder,
+		oldFolder,
+		logging.ObjectEventSubFolder,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "folder": newFolder})
+}
@@ -0,0 +1,138 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Checks that the list is owned by or shared with the user. Returns false if
+// not, in which case the error is already pushed to gin.Context.
+func (controller *TodoController) hasListAccess(
+	reqUser *db.User,
+	listID string,
+	ctx *gin.Context,
+) bool {
+	listIds, err := controller.db.GetListIdsAccessible(ctx, reqUser.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError(
+			"failed to get list accessible by user",
+			file,
+			line,
+			err,
+			ctx,
+		)
+		return false
+	}
+	if !slices.Contains(listIds, listID) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventForbiddenAction,
+			ctx.FullPath(),
+			listID,
+			reqUser.ID,
+		)
+		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+		return false
+	}
+	return true
+}
+
+// Gets the todo if it is in a list accessible by the user. Returns nil if not,
+// in which case the error is already pushed to gin.Context.
+func (controller *TodoController) getAccessibleTodo(
+	reqUser *db.User,
+	listID string,
+	todoID string,
+	ctx *gin.Context,
+) *db.Todo {
+	if ok := controller.hasListAccess(reqUser, listID, ctx); !ok {
+		return nil
+	}
+
+	args := &db.GetTodoByIdWithListIdParams{
+		ID:     todoID,
+		ListID: listID,
+	}
+	todo, err := controller.db.GetTodoByIdWithListId(ctx, *args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logging.LogSecurityEvent(
+				logging.SecurityScoreLow,
+				logging.SecurityEventForbiddenAction,
+				ctx.FullPath(),
+				fmt.Sprintf("list: %v, todo: %v", listID, todoID),
+				reqUser.ID,
+			)
+			ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+			return nil
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get todo", file, line, err, ctx)
+		return nil
+	}
+	return &todo
+}
+
+// Gets the template if it is owned by the user, shared with everyone or the
+// user may read all lists. Returns nil if not, in which case the error is
+// already pushed to gin.Context.
+func (controller *TodoController) getVisibleTemplate(
+	reqUser *db.User,
+	templateID string,
+	ctx *gin.Context,
+) *db.ListTemplate {
+	template, err := controller.db.GetListTemplate(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return nil
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get template", file, line, err, ctx)
+		return nil
+	}
+	if template.UserID != reqUser.ID && !template.IsShared && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsRead,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("template: %v", templateID),
+		ctx,
+	) {
+		return nil
+	}
+	return &template
+}
+
+// Checks that the list is not archived, as archived lists are read-only.
+// Returns false if it is, in which case the error is already pushed to
+// gin.Context.
+func (controller *TodoController) isListWritable(listID string, ctx *gin.Context) bool {
+	list, err := controller.db.GetList(ctx, listID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return false
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get list", file, line, err, ctx)
+		return false
+	}
+	if list.ArchivedAt.Valid {
+		ctx.Error(gterrors.ErrListArchived).SetType(gin.ErrorTypePublic)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Archives the list, making it read-only and hiding it from ReadLists unless
+// archived lists are asked for. Running timers on its todos are stopped.
+func (controller *TodoController) ArchiveList(ctx *gin.Context) {
+	controller.setListArchived(true, ctx)
+}
+
+func (controller *TodoController) UnarchiveList(ctx *gin.Context) {
+	controller.setListArchived(false, ctx)
+}
+
+func (controller *TodoController) setListArchived(archive bool, ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	listID := ctx.Param("listID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	oldList, err := controller.db.GetList(ctx, listID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get list", file, line, err, ctx)
+		return
+	}
+
+	if oldList.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("listID: %v", listID),
+		ctx,
+	) {
+		return
+	}
+
+	if oldList.ArchivedAt.Valid == archive {
+		ctx.JSON(http.StatusOK, gin.H{"status": "not-modified", "list": oldList})
+		return
+	}
+
+	var newList db.List
+	if archive {
+		newList, err = controller.db.ArchiveList(ctx, listID)
+	} else {
+		newList, err = controller.db.UnarchiveList(ctx, listID)
+	}
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to update list archive state", file, line, err, ctx)
+		return
+	}
+
+	if archive {
+		stopArgs := &db.StopRunningTimeEntriesByListIdParams{
+			ListID:    newList.ID,
+			StoppedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		}
+		if err := controller.db.StopRunningTimeEntriesByListId(ctx, *stopArgs); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			logging.LogError(
+				fmt.Errorf("failed to stop running timers: %w", err),
+				fmt.Sprintf("%v: %d", file, line),
+				err.Error(),
+			)
+		}
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&newList,
+		&oldList,
+		logging.ObjectEventSubList,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "list": newList})
+}
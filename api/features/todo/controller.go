@@ -3,13 +3,16 @@ package todo
 import (
 	"context"
 	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type TodoController struct {
-	db  *db.Queries
-	ctx context.Context
+	db   *db.Queries
+	pool *pgxpool.Pool
+	ctx  context.Context
 }
 
-func NewController(db *db.Queries, ctx context.Context) *TodoController {
-	return &TodoController{db: db, ctx: ctx}
+func NewController(db *db.Queries, pool *pgxpool.Pool, ctx context.Context) *TodoController {
+	return &TodoController{db: db, pool: pool, ctx: ctx}
 }
@@ -0,0 +1,146 @@
+package todo
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Creates a new list owned by the requester from the template. Due offsets of
+// the template todos are turned into due dates counted from the start date.
+func (controller *TodoController) CreateListFromTemplate(ctx *gin.Context) {
+	payload := &schemas.CreateListFromTemplate{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+	templateID := ctx.Param("templateID")
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	template := controller.getVisibleTemplate(reqUser, templateID, ctx)
+	if template == nil {
+		return
+	}
+
+	title := template.Title
+	description := template.Description
+	if payload.Title != nil {
+		title = *payload.Title
+	}
+	if payload.Description != nil {
+		description = pgtype.Text{String: *payload.Description, Valid: true}
+	}
+	if !validate.LengthTitle(title) {
+		ctx.Error(gterrors.NewGtValueError(title, "title too long"))
+		return
+	} else if !validate.LengthDescription(description.String) {
+		ctx.Error(gterrors.NewGtValueError(description.String, "description too long"))
+		return
+	}
+	startDate := payload.StartDate.UTC()
+
+	templateTodos, err := controller.db.GetTemplateTodosByTemplateId(ctx, template.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get template todos", file, line, err, ctx)
+		return
+	}
+	templateTodos = ParentsFirst(
+		templateTodos,
+		func(t db.TemplateTodo) string { return t.ID },
+		func(t db.TemplateTodo) (string, bool) { return t.ParentID.String, t.ParentID.Valid },
+	)
+
+	var list db.List
+	todos := make([]db.Todo, 0, len(templateTodos))
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		listArgs := &db.CreateListParams{
+			ID:          uuid.New().String(),
+			UserID:      reqUser.ID,
+			Title:       title,
+			Description: description,
+		}
+		var err error
+		list, err = qtx.CreateList(ctx, *listArgs)
+		if err != nil {
+			return fmt.Errorf("failed to create list: %w", err)
+		}
+
+		newIds := make(map[string]string, len(templateTodos))
+		for _, templateTodo := range templateTodos {
+			newIds[templateTodo.ID] = uuid.New().String()
+			parentID := pgtype.Text{}
+			if newParentID, ok := newIds[templateTodo.ParentID.String]; ok && templateTodo.ParentID.Valid {
+				parentID = pgtype.Text{String: newParentID, Valid: true}
+			}
+			completeBefore := pgtype.Timestamptz{}
+			if templateTodo.DueOffset.Valid {
+				offset := time.Duration(templateTodo.DueOffset.Int32) * time.Minute
+				completeBefore = pgtype.Timestamptz{Time: startDate.Add(offset), Valid: true}
+			}
+
+			todoArgs := &db.CreateTodoParams{
+				ID:                newIds[templateTodo.ID],
+				ListID:            list.ID,
+				UserID:            reqUser.ID,
+				ParentID:          parentID,
+				Title:             templateTodo.Title,
+				Description:       templateTodo.Description,
+				CompleteBefore:    completeBefore,
+				EstimatedDuration: templateTodo.EstimatedDuration,
+				Tags:              []string{},
+			}
+			todo, err := qtx.CreateTodo(ctx, *todoArgs)
+			if err != nil {
+				return fmt.Errorf("failed to create todo: %w", err)
+			}
+			todos = append(todos, todo)
+		}
+		return nil
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create list from template", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&list,
+		nil,
+		logging.ObjectEventSubList,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "list": list, "todos": todos})
+}
@@ -0,0 +1,162 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Saves the list with its todos as a template. Due dates of the todos are
+// stored as offsets from the start date given in the body.
+func (controller *TodoController) CreateTemplate(ctx *gin.Context) {
+	payload := &schemas.CreateTemplate{}
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+	listID := ctx.Param("listID")
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+	if ok := controller.hasListAccess(reqUser, listID, ctx); !ok {
+		return
+	}
+
+	list, err := controller.db.GetList(ctx, listID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get list", file, line, err, ctx)
+		return
+	}
+
+	title := list.Title
+	description := list.Description
+	if payload.Title != nil {
+		title = *payload.Title
+	}
+	if payload.Description != nil {
+		description = pgtype.Text{String: *payload.Description, Valid: true}
+	}
+	if !validate.LengthTitle(title) {
+		ctx.Error(gterrors.NewGtValueError(title, "title too long"))
+		return
+	} else if !validate.LengthDescription(description.String) {
+		ctx.Error(gterrors.NewGtValueError(description.String, "description too long"))
+		return
+	}
+	startDate := list.CreatedAt.Time
+	if payload.StartDate != nil {
+		startDate = payload.StartDate.UTC()
+	}
+
+	todos, err := controller.db.GetTodosByList(ctx, list.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get todos", file, line, err, ctx)
+		return
+	}
+	todos = ParentsFirst(
+		todos,
+		func(t db.Todo) string { return t.ID },
+		func(t db.Todo) (string, bool) { return t.ParentID.String, t.ParentID.Valid },
+	)
+
+	var template db.ListTemplate
+	templateTodos := make([]db.TemplateTodo, 0, len(todos))
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		templateArgs := &db.CreateListTemplateParams{
+			ID:          uuid.New().String(),
+			UserID:      reqUser.ID,
+			Title:       title,
+			Description: description,
+			IsShared:    payload.IsShared,
+		}
+		var err error
+		template, err = qtx.CreateListTemplate(ctx, *templateArgs)
+		if err != nil {
+			return fmt.Errorf("failed to create template: %w", err)
+		}
+
+		newIds := make(map[string]string, len(todos))
+		for _, todo := range todos {
+			newIds[todo.ID] = uuid.New().String()
+			parentID := pgtype.Text{}
+			if newParentID, ok := newIds[todo.ParentID.String]; ok && todo.ParentID.Valid {
+				parentID = pgtype.Text{String: newParentID, Valid: true}
+			}
+			dueOffset := pgtype.Int4{}
+			if todo.CompleteBefore.Valid {
+				offset := todo.CompleteBefore.Time.Sub(startDate).Minutes()
+				dueOffset = pgtype.Int4{Int32: int32(offset), Valid: true}
+			}
+
+			todoArgs := &db.CreateTemplateTodoParams{
+				ID:                newIds[todo.ID],
+				TemplateID:        template.ID,
+				ParentID:          parentID,
+				Title:             todo.Title,
+				Description:       todo.Description,
+				DueOffset:         dueOffset,
+				EstimatedDuration: todo.EstimatedDuration,
+			}
+			templateTodo, err := qtx.CreateTemplateTodo(ctx, *todoArgs)
+			if err != nil {
+				return fmt.Errorf("failed to create template todo: %w", err)
+			}
+			templateTodos = append(templateTodos, templateTodo)
+		}
+		return nil
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to save list as template", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&template,
+		nil,
+		logging.ObjectEventSubTemplate,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":   "created",
+		"template": template,
+		"todos":    templateTodos,
+	})
+}
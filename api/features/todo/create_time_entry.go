@@ -0,0 +1,97 @@
+package todo
+
+import (
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Adds a manually entered, already finished time entry to the todo.
+func (controller *TodoController) CreateTimeEntry(ctx *gin.Context) {
+	payload := &schemas.CreateTimeEntry{}
+	note := ""
+
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+	listID := ctx.Param("listID")
+	todoID := ctx.Param("todoID")
+
+	if !payload.StoppedAt.After(payload.StartedAt) {
+		ctx.Error(gterrors.NewGtValueError(
+			payload.StoppedAt.String(),
+			"stopped_at must be after started_at",
+		))
+		return
+	}
+	if payload.Note != nil {
+		if ok := validate.LengthDescription(*payload.Note); !ok {
+			ctx.Error(gterrors.NewGtValueError(*payload.Note, "note too long"))
+			return
+		}
+		note = *payload.Note
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	todo := controller.getAccessibleTodo(reqUser, listID, todoID, ctx)
+	if todo == nil {
+		return
+	}
+	if ok := controller.isListWritable(listID, ctx); !ok {
+		return
+	}
+
+	args := &db.CreateTimeEntryParams{
+		ID:        uuid.New().String(),
+		TodoID:    todo.ID,
+		UserID:    reqUser.ID,
+		StartedAt: pgtype.Timestamptz{Time: payload.StartedAt.UTC(), Valid: true},
+		StoppedAt: pgtype.Timestamptz{Time: payload.StoppedAt.UTC(), Valid: true},
+		Note:      pgtype.Text{String: note, Valid: payload.Note != nil},
+	}
+	timeEntry, err := controller.db.CreateTimeEntry(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to create time entry", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&timeEntry,
+		nil,
+		logging.ObjectEventSubTimeEntry,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "time_entry": timeEntry})
+}
@@ -2,6 +2,7 @@ package todo
 
 import (
 	"errors"
+	"fmt"
 	"runtime"
 	"slices"
 	"time"
@@ -17,12 +18,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (controller *TodoController) CreateTodo(ctx *gin.Context) {
 	payload := &schemas.CreateTodo{}
-	description := ""
 
 	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
 		return
@@ -35,30 +36,100 @@ func (controller *TodoController) CreateTodo(ctx *gin.Context) {
 	}
 	listID := ctx.Param("listID")
 
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	todo := controller.CreateTodoAs(reqUser, listID, payload, ctx)
+	if todo == nil {
+		return
+	}
+	ctx.JSON(201, gin.H{"status": "created", "todo": todo})
+}
+
+// Validates the payload, checks the user's right to add to the list and
+// creates the todo. Returns nil on failure, in which case the error is already
+// pushed to gin.Context.
+func (controller *TodoController) CreateTodoAs(
+	reqUser *db.User,
+	listID string,
+	payload *schemas.CreateTodo,
+	ctx *gin.Context,
+) *db.Todo {
+	return controller.CreateTodoWith(reqUser, listID, payload, nil, ctx)
+}
+
+// Like CreateTodoAs, and runs then in the transaction that creates the todo,
+// so that the todo is only kept if then succeeds. then may be nil.
+func (controller *TodoController) CreateTodoWith(
+	reqUser *db.User,
+	listID string,
+	payload *schemas.CreateTodo,
+	then func(qtx *db.Queries, todo *db.Todo) error,
+	ctx *gin.Context,
+) *db.Todo {
+	description := ""
+
 	if ok := validate.LengthTitle(payload.Title); !ok {
 		ctx.Error(gterrors.NewGtValueError(payload.Title, "title too long"))
-		return
+		return nil
 	}
 	if payload.Description != nil {
 		if ok := validate.LengthDescription(*payload.Description); !ok {
 			ctx.Error(gterrors.NewGtValueError(*payload.Description, "description too long"))
-			return
+			return nil
 		}
 		description = *payload.Description
 	}
+	tags := []string{}
+	for _, tag := range payload.Tags {
+		if ok := validate.Tag(tag); !ok {
+			ctx.Error(gterrors.NewGtValueError(tag, "tag must be 1 to 30 chars without spaces, '#' or ','"))
+			return nil
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if payload.CompleteBefore != nil && payload.DueDate != nil {
+		ctx.Error(gterrors.NewGtValueError(*payload.DueDate, "due_date cannot be set with complete_before"))
+		return nil
+	}
+	if payload.Recurrence != nil {
+		if ok := validate.Recurrence(*payload.Recurrence); !ok {
+			ctx.Error(gterrors.NewGtValueError(*payload.Recurrence, "invalid recurrence rule"))
+			return nil
+		}
+	}
 
-	// Check users right to access the list
-	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
-	if err != nil {
+	// The list can differ from the one in the route, like with "@list" in
+	// quick add, so tokens limited to some lists are checked here as well.
+	if _, tokenListIDs := mycontext.GetTokenScopes(ctx); len(tokenListIDs) > 0 && !slices.Contains(tokenListIDs, listID) {
 		logging.LogSecurityEvent(
 			logging.SecurityScoreLow,
-			logging.SecurityEventJwtUserUnknown,
+			logging.SecurityEventInsufficientScope,
 			ctx.FullPath(),
-			requesterUsername,
+			reqUser.ID,
 			ctx.ClientIP(),
 		)
-		return
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonInsufficientScope,
+				fmt.Errorf("token is not allowed to access list %v", listID),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return nil
 	}
+
+	// Check users right to access the list
 	listIds, err := controller.db.GetListIdsAccessible(ctx, reqUser.ID)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		_, file, line, _ := runtime.Caller(0)
@@ -69,7 +140,7 @@ func (controller *TodoController) CreateTodo(ctx *gin.Context) {
 			err,
 			ctx,
 		)
-		return
+		return nil
 	}
 	if !slices.Contains(listIds, listID) {
 		logging.LogSecurityEvent(
@@ -80,32 +151,64 @@ func (controller *TodoController) CreateTodo(ctx *gin.Context) {
 			reqUser.ID,
 		)
 		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
-		return
+		return nil
+	}
+	if ok := controller.isListWritable(listID, ctx); !ok {
+		return nil
 	}
 
 	parentID := ""
 	if payload.ParentID != nil {
 		parentID = *payload.ParentID
 	}
-	var completeBefore time.Time
-	if payload.CompleteBefore != nil {
-		completeBefore = *payload.CompleteBefore
-	} else {
-		completeBefore = time.Date(1970, 0o1, 0o1, 0o0, 0o0, 0o0, 0o0, time.UTC)
-	}
 
 	args := &db.CreateTodoParams{
-		ID:             uuid.New().String(),
-		ListID:         listID,
-		UserID:         reqUser.ID,
-		Title:          payload.Title,
-		Description:    pgtype.Text{String: description, Valid: payload.Description != nil},
-		ParentID:       pgtype.Text{String: parentID, Valid: payload.ParentID != nil},
-		CompleteBefore: pgtype.Timestamp{Time: completeBefore, Valid: payload.CompleteBefore != nil},
+		ID:          uuid.New().String(),
+		ListID:      listID,
+		UserID:      reqUser.ID,
+		Title:       payload.Title,
+		Description: pgtype.Text{String: description, Valid: payload.Description != nil},
+		ParentID:    pgtype.Text{String: parentID, Valid: payload.ParentID != nil},
+		Tags:        tags,
+	}
+	if payload.CompleteBefore != nil {
+		args.CompleteBefore = pgtype.Timestamptz{Time: *payload.CompleteBefore, Valid: true}
+	}
+	if payload.DueDate != nil {
+		dueDate, err := time.Parse(time.DateOnly, *payload.DueDate)
+		if err != nil {
+			ctx.Error(gterrors.NewGtValueError(*payload.DueDate, "invalid due_date"))
+			return nil
+		}
+		args.DueDate = pgtype.Date{Time: dueDate, Valid: true}
+	}
+	if payload.EstimatedDuration != nil {
+		args.EstimatedDuration = pgtype.Int4{Int32: *payload.EstimatedDuration, Valid: true}
+	}
+	if payload.Priority != nil {
+		args.Priority = *payload.Priority
+	}
+	if payload.Recurrence != nil {
+		args.Recurrence = pgtype.Text{String: *payload.Recurrence, Valid: true}
 	}
 
-	todo, err := controller.db.CreateTodo(ctx, *args)
-	if err != nil {
+	var todo db.Todo
+	if then == nil {
+		todo, err = controller.db.CreateTodo(ctx, *args)
+	} else {
+		err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+			var err error
+			if todo, err = qtx.CreateTodo(ctx, *args); err != nil {
+				return err
+			}
+			return then(qtx, &todo)
+		})
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		ctx.Error(gterrors.ErrUniqueViolation).SetType(gin.ErrorTypePublic)
+		return nil
+	} else if err != nil {
 		_, file, line, _ := runtime.Caller(0)
 		mycontext.CtxAddGtInternalError(
 			"failed to create todo",
@@ -114,7 +217,7 @@ func (controller *TodoController) CreateTodo(ctx *gin.Context) {
 			err,
 			ctx,
 		)
-		return
+		return nil
 	}
 
 	logging.LogObjectEvent(
@@ -126,5 +229,5 @@ func (controller *TodoController) CreateTodo(ctx *gin.Context) {
 		nil,
 		logging.ObjectEventSubTodo,
 	)
-	ctx.JSON(201, gin.H{"status": "created", "todo": todo})
+	return &todo
 }
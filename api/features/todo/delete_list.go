@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"runtime"
 
-	"go-todo/gterrors"
 	"go-todo/logging"
+	"go-todo/util/authz"
 	"go-todo/util/database"
 	"go-todo/util/mycontext"
 
@@ -46,15 +46,14 @@ func (controller *TodoController) DeleteList(ctx *gin.Context) {
 		return
 	}
 
-	if listDeleted.UserID != reqUser.ID && !reqUser.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreLow,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			fmt.Sprintf("listID: %v", listID),
-			reqUser.ID,
-		)
-		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+	if listDeleted.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("listID: %v", listID),
+		ctx,
+	) {
 		return
 	}
 
@@ -0,0 +1,71 @@
+package todo
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (controller *TodoController) DeleteTemplate(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	templateID := ctx.Param("templateID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	template := controller.getVisibleTemplate(reqUser, templateID, ctx)
+	if template == nil {
+		return
+	}
+	if template.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("template: %v", templateID),
+		ctx,
+	) {
+		return
+	}
+
+	rows, err := controller.db.DeleteListTemplate(ctx, template.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete template", file, line, err, ctx)
+		return
+	}
+
+	if rows != 0 {
+		logging.LogObjectEvent(
+			ctx.FullPath(),
+			ctx.ClientIP(),
+			logging.ObjectEventDelete,
+			reqUser,
+			"deleted",
+			template.ID,
+			logging.ObjectEventSubTemplate,
+		)
+	}
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
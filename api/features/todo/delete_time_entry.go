@@ -0,0 +1,96 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Deletes a time entry. Only the user who tracked the time or one who may
+// manage all lists can delete it.
+func (controller *TodoController) DeleteTimeEntry(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	listID := ctx.Param("listID")
+	todoID := ctx.Param("todoID")
+	entryID := ctx.Param("entryID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	todo := controller.getAccessibleTodo(reqUser, listID, todoID, ctx)
+	if todo == nil {
+		return
+	}
+	if ok := controller.isListWritable(listID, ctx); !ok {
+		return
+	}
+
+	timeEntry, err := controller.db.GetTimeEntryById(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get time entry", file, line, err, ctx)
+		return
+	}
+	if timeEntry.TodoID != todo.ID {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+	if timeEntry.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("time entry: %v", entryID),
+		ctx,
+	) {
+		return
+	}
+
+	rows, err := controller.db.DeleteTimeEntry(ctx, timeEntry.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to delete time entry", file, line, err, ctx)
+		return
+	}
+
+	if rows != 0 {
+		logging.LogObjectEvent(
+			ctx.FullPath(),
+			ctx.ClientIP(),
+			logging.ObjectEventDelete,
+			reqUser,
+			"deleted",
+			timeEntry.ID,
+			logging.ObjectEventSubTimeEntry,
+		)
+	}
+	ctx.JSON(http.StatusNoContent, gin.H{})
+}
@@ -38,6 +38,20 @@ func (controller *TodoController) DeleteTodo(ctx *gin.Context) {
 		return
 	}
 
+	if ok := controller.DeleteTodoAs(reqUser, listID, todoID, ctx); !ok {
+		return
+	}
+	ctx.JSON(204, gin.H{})
+}
+
+// Checks the user's right to change the list and deletes the todo. Returns
+// false on failure, in which case the error is already pushed to gin.Context.
+func (controller *TodoController) DeleteTodoAs(
+	reqUser *db.User,
+	listID string,
+	todoID string,
+	ctx *gin.Context,
+) bool {
 	listIds, err := controller.db.GetListIdsAccessible(ctx, reqUser.ID)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		_, file, line, _ := runtime.Caller(0)
@@ -48,7 +62,7 @@ func (controller *TodoController) DeleteTodo(ctx *gin.Context) {
 			err,
 			ctx,
 		)
-		return
+		return false
 	}
 	if !slices.Contains(listIds, listID) {
 		logging.LogSecurityEvent(
@@ -59,7 +73,11 @@ func (controller *TodoController) DeleteTodo(ctx *gin.Context) {
 			reqUser.ID,
 		)
 		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
-		return
+		return false
+	}
+
+	if ok := controller.isListWritable(listID, ctx); !ok {
+		return false
 	}
 
 	args := &db.DeleteTodoByIdWithListIdParams{
@@ -76,7 +94,7 @@ func (controller *TodoController) DeleteTodo(ctx *gin.Context) {
 			err,
 			ctx,
 		)
-		return
+		return false
 	} else {
 		logging.LogObjectEvent(
 			ctx.FullPath(),
@@ -89,5 +107,5 @@ func (controller *TodoController) DeleteTodo(ctx *gin.Context) {
 		)
 	}
 
-	ctx.JSON(204, gin.H{})
+	return true
 }
@@ -0,0 +1,31 @@
+package todo
+
+// Orders items so that every parent comes before its children, which is the
+// order they have to be inserted in because of the parent_id foreign key.
+// Items whose parent is not among items are treated as roots.
+func ParentsFirst[T any](items []T, id func(T) string, parentID func(T) (string, bool)) []T {
+	ids := make(map[string]bool, len(items))
+	for _, item := range items {
+		ids[id(item)] = true
+	}
+
+	children := make(map[string][]T)
+	roots := make([]T, 0, len(items))
+	for _, item := range items {
+		if parent, ok := parentID(item); ok && ids[parent] {
+			children[parent] = append(children[parent], item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+
+	ordered := make([]T, 0, len(items))
+	queue := roots
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, item)
+		queue = append(queue, children[id(item)]...)
+	}
+	return ordered
+}
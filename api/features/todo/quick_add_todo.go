@@ -0,0 +1,134 @@
+package todo
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/quickadd"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Creates a todo from a line of text like "Pay rent every month on the 1st
+// #finance !high". The response holds what was parsed so clients can show it
+// for confirmation.
+func (controller *TodoController) QuickAddTodo(ctx *gin.Context) {
+	payload := &schemas.QuickAddTodo{}
+
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	}
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+	listID := ctx.Param("listID")
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	var loc *time.Location
+	if payload.TimeZone != nil {
+		if ok := validate.TimeZone(*payload.TimeZone); !ok {
+			ctx.Error(gterrors.NewGtValueError(*payload.TimeZone, "unknown time zone"))
+			return
+		}
+		loc, _ = time.LoadLocation(*payload.TimeZone)
+	} else {
+		loc, err = database.GetUserLocation(ctx, controller.db, reqUser.ID)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get time zone", file, line, err, ctx)
+			return
+		}
+	}
+
+	parsed, err := quickadd.Parse(payload.Text, time.Now().In(loc))
+	if err != nil {
+		if errors.Is(err, quickadd.ErrNoTitle) {
+			ctx.Error(gterrors.NewGtValueError(payload.Text, "text has no title"))
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to parse text", file, line, err, ctx)
+		return
+	}
+
+	// "@list" moves the todo to another list the user can access.
+	if parsed.List != nil {
+		args := &db.GetListsAccessibleByUserIdParams{UserID: reqUser.ID, IncludeArchived: false}
+		lists, err := controller.db.GetListsAccessibleByUserId(ctx, *args)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get lists", file, line, err, ctx)
+			return
+		}
+		listID = ""
+		for _, list := range lists {
+			if strings.EqualFold(list.Title, *parsed.List) {
+				listID = list.ID
+				break
+			}
+		}
+		if listID == "" {
+			ctx.Error(gterrors.NewGtValueError(*parsed.List, "no list with this title"))
+			return
+		}
+	}
+
+	todoPayload := &schemas.CreateTodo{
+		Title:      parsed.Title,
+		Priority:   parsed.Priority,
+		Tags:       parsed.Tags,
+		Recurrence: parsed.Recurrence,
+	}
+	if parsed.Due != nil && parsed.AllDay {
+		dueDate := parsed.Due.Format(time.DateOnly)
+		todoPayload.DueDate = &dueDate
+	} else if parsed.Due != nil {
+		todoPayload.CompleteBefore = parsed.Due
+	}
+	todo := controller.CreateTodoAs(reqUser, listID, todoPayload, ctx)
+	if todo == nil {
+		return
+	}
+
+	var parsedListID *string
+	if parsed.List != nil {
+		parsedListID = &listID
+	}
+	ctx.JSON(201, gin.H{
+		"status": "created",
+		"todo":   todo,
+		"parsed": gin.H{
+			"title":      parsed.Title,
+			"due":        parsed.Due,
+			"all_day":    parsed.AllDay,
+			"recurrence": parsed.Recurrence,
+			"tags":       parsed.Tags,
+			"priority":   parsed.Priority,
+			"list":       parsed.List,
+			"list_id":    parsedListID,
+		},
+	})
+}
@@ -5,8 +5,8 @@ import (
 	"runtime"
 	"slices"
 
-	"go-todo/gterrors"
 	"go-todo/logging"
+	"go-todo/util/authz"
 	"go-todo/util/database"
 	"go-todo/util/mycontext"
 
@@ -46,15 +46,14 @@ func (controller *TodoController) ReadListWithTodos(ctx *gin.Context) {
 		)
 		return
 	}
-	if !slices.Contains(allowedIds, listID) && !reqUser.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreLow,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			listID,
-			reqUser.ID,
-		)
-		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+	if !slices.Contains(allowedIds, listID) && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsRead,
+		logging.SecurityScoreLow,
+		listID,
+		ctx,
+	) {
 		return
 	}
 
@@ -79,6 +78,8 @@ func (controller *TodoController) ReadListWithTodos(ctx *gin.Context) {
 		"description": list.Description,
 		"created_at":  list.CreatedAt,
 		"updated_at":  list.UpdatedAt,
+		"archived_at": list.ArchivedAt,
+		"folder_id":   list.FolderID,
 		"todos":       todos,
 	}
 
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"runtime"
 	"slices"
+	"strconv"
 
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 	"go-todo/logging"
+	"go-todo/util/authz"
 	"go-todo/util/database"
 	"go-todo/util/mycontext"
 
@@ -42,6 +44,15 @@ func (controller *TodoController) ReadLists(ctx *gin.Context) {
 		return
 	}
 
+	includeArchived, err := strconv.ParseBool(ctx.DefaultQuery("include_archived", "false"))
+	if err != nil {
+		ctx.Error(gterrors.NewGtValueError(
+			ctx.Query("include_archived"),
+			"include_archived must be a boolean",
+		))
+		return
+	}
+
 	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
 	if err != nil {
 		logging.LogSecurityEvent(
@@ -54,15 +65,14 @@ func (controller *TodoController) ReadLists(ctx *gin.Context) {
 		return
 	}
 
-	if show == admin && !reqUser.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreLow,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			"all lists",
-			reqUser.ID,
-		)
-		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+	if show == admin && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsRead,
+		logging.SecurityScoreLow,
+		"all lists",
+		ctx,
+	) {
 		return
 	}
 
@@ -70,11 +80,20 @@ func (controller *TodoController) ReadLists(ctx *gin.Context) {
 	var switchErr error
 	switch show {
 	case owned:
-		*lists, switchErr = controller.db.GetListsByOwnerId(ctx, reqUser.ID)
+		*lists, switchErr = controller.db.GetListsByOwnerId(
+			ctx,
+			db.GetListsByOwnerIdParams{UserID: reqUser.ID, IncludeArchived: includeArchived},
+		)
 	case shared:
-		*lists, switchErr = controller.db.GetListsBySharedUserId(ctx, reqUser.ID)
+		*lists, switchErr = controller.db.GetListsBySharedUserId(
+			ctx,
+			db.GetListsBySharedUserIdParams{UserID: reqUser.ID, IncludeArchived: includeArchived},
+		)
 	case all:
-		*lists, switchErr = controller.db.GetListsAccessibleByUserId(ctx, reqUser.ID)
+		*lists, switchErr = controller.db.GetListsAccessibleByUserId(
+			ctx,
+			db.GetListsAccessibleByUserIdParams{UserID: reqUser.ID, IncludeArchived: includeArchived},
+		)
 	case admin:
 		*lists, switchErr = controller.db.GetLists(ctx)
 	}
@@ -115,6 +134,8 @@ func (controller *TodoController) ReadLists(ctx *gin.Context) {
 			"description": list.Description,
 			"created_at":  list.CreatedAt,
 			"updated_at":  list.UpdatedAt,
+			"archived_at": list.ArchivedAt,
+			"folder_id":   list.FolderID,
 			"todos":       todoMap[list.ID],
 		}
 		response = append(response, item)
@@ -2,6 +2,7 @@ package todo
 
 import (
 	"go-todo/middleware"
+	"go-todo/util/jwt"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,18 +18,40 @@ func NewRoutes(todoController *TodoController) *TodoRoutes {
 func (routes *TodoRoutes) Register(rg *gin.RouterGroup) {
 	router := rg.Group("/list")
 
-	router.Use(middleware.JwtAuthMiddleware())
-
-	router.GET("/", routes.todoController.ReadLists)
-	router.GET("/:listID", routes.todoController.ReadListWithTodos)
-	router.POST("/", routes.todoController.CreateList)
-	router.PATCH("/:listID", routes.todoController.UpdateList)
-	router.DELETE("/:listID", routes.todoController.DeleteList)
+	router.Use(middleware.JwtAuthMiddleware(routes.todoController.db))
+	readLists := middleware.RequireScopes(jwt.ScopeListsRead)
+	writeLists := middleware.RequireScopes(jwt.ScopeListsWrite)
+	writeTodos := middleware.RequireScopes(jwt.ScopeTodosWrite)
+
+	router.GET("/", readLists, routes.todoController.ReadLists)
+	router.GET("/:listID", readLists, routes.todoController.ReadListWithTodos)
+	router.POST("/", writeLists, routes.todoController.CreateList)
+	router.PATCH("/:listID", writeLists, routes.todoController.UpdateList)
+	router.DELETE("/:listID", writeLists, routes.todoController.DeleteList)
+	router.POST("/:listID/archive", writeLists, routes.todoController.ArchiveList)
+	router.POST("/:listID/unarchive", writeLists, routes.todoController.UnarchiveList)
+	router.GET("/:listID/time", readLists, routes.todoController.ReadListTimeReport)
+	router.POST("/:listID/template", writeLists, routes.todoController.CreateTemplate)
+	router.POST("/from-template/:templateID", writeLists, routes.todoController.CreateListFromTemplate)
+
+	templateRouter := router.Group("/template")
+	templateRouter.GET("/", readLists, routes.todoController.ReadTemplates)
+	templateRouter.GET("/:templateID", readLists, routes.todoController.ReadTemplate)
+	templateRouter.PATCH("/:templateID", writeLists, routes.todoController.UpdateTemplate)
+	templateRouter.DELETE("/:templateID", writeLists, routes.todoController.DeleteTemplate)
 
 	todoRouter := router.Group("/:listID/todo")
-	todoRouter.POST("/", routes.todoController.CreateTodo)
-	todoRouter.PATCH("/:todoID", routes.todoController.UpdateTodo)
-	todoRouter.DELETE("/:todoID", routes.todoController.DeleteTodo)
+	todoRouter.POST("/", writeTodos, routes.todoController.CreateTodo)
+	todoRouter.POST("/quick", writeTodos, routes.todoController.QuickAddTodo)
+	todoRouter.PATCH("/:todoID", writeTodos, routes.todoController.UpdateTodo)
+	todoRouter.DELETE("/:todoID", writeTodos, routes.todoController.DeleteTodo)
+
+	timeRouter := todoRouter.Group("/:todoID")
+	timeRouter.POST("/timer/start", writeTodos, routes.todoController.StartTimer)
+	timeRouter.POST("/timer/stop", writeTodos, routes.todoController.StopTimer)
+	timeRouter.GET("/time", readLists, routes.todoController.ReadTimeEntries)
+	timeRouter.POST("/time", writeTodos, routes.todoController.CreateTimeEntry)
+	timeRouter.DELETE("/time/:entryID", writeTodos, routes.todoController.DeleteTimeEntry)
 
 	// TODO Implement create share
 	// TODO Implement delete share
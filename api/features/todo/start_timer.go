@@ -0,0 +1,93 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Starts a timer on the todo for the requester. Only one timer per user can be
+// running at a time.
+func (controller *TodoController) StartTimer(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	listID := ctx.Param("listID")
+	todoID := ctx.Param("todoID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	todo := controller.getAccessibleTodo(reqUser, listID, todoID, ctx)
+	if todo == nil {
+		return
+	}
+	if ok := controller.isListWritable(listID, ctx); !ok {
+		return
+	}
+
+	_, err = controller.db.GetRunningTimeEntryByUserId(ctx, reqUser.ID)
+	if err == nil {
+		ctx.Error(gterrors.ErrTimerRunning).SetType(gin.ErrorTypePublic)
+		return
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get running timer", file, line, err, ctx)
+		return
+	}
+
+	args := &db.CreateTimeEntryParams{
+		ID:        uuid.New().String(),
+		TodoID:    todo.ID,
+		UserID:    reqUser.ID,
+		StartedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}
+	timeEntry, err := controller.db.CreateTimeEntry(ctx, *args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		// Another timer was started between the check and the insert.
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			ctx.Error(gterrors.ErrTimerRunning).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to start timer", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventCreate,
+		reqUser,
+		&timeEntry,
+		nil,
+		logging.ObjectEventSubTimeEntry,
+	)
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "time_entry": timeEntry})
+}
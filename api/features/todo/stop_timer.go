@@ -0,0 +1,89 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Stops the requesters running timer on the todo.
+func (controller *TodoController) StopTimer(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	listID := ctx.Param("listID")
+	todoID := ctx.Param("todoID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	todo := controller.getAccessibleTodo(reqUser, listID, todoID, ctx)
+	if todo == nil {
+		return
+	}
+
+	runningEntry, err := controller.db.GetRunningTimeEntryByUserId(ctx, reqUser.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get running timer", file, line, err, ctx)
+		return
+	}
+	if runningEntry.TodoID != todo.ID {
+		ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	args := &db.StopTimeEntryParams{
+		ID:        runningEntry.ID,
+		StoppedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}
+	stoppedEntry, err := controller.db.StopTimeEntry(ctx, *args)
+	if err != nil {
+		// The timer was stopped between the fetch and the update.
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to stop timer", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&stoppedEntry,
+		&runningEntry,
+		logging.ObjectEventSubTimeEntry,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "time_entry": stoppedEntry})
+}
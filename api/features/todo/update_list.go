@@ -2,12 +2,14 @@ package todo
 
 import (
 	"errors"
+	"fmt"
 	"runtime"
 
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 	"go-todo/logging"
 	"go-todo/schemas"
+	"go-todo/util/authz"
 	"go-todo/util/database"
 	"go-todo/util/mycontext"
 	"go-todo/util/validate"
@@ -58,8 +60,18 @@ func (controller *TodoController) UpdateList(ctx *gin.Context) {
 		return
 	}
 
-	if oldList.UserID != reqUser.ID && !reqUser.IsAdmin {
-		ctx.Error(gterrors.ErrForbidden).SetType(gterrors.GetGinErrorType())
+	if oldList.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("listID: %v", listID),
+		ctx,
+	) {
+		return
+	}
+	if oldList.ArchivedAt.Valid {
+		ctx.Error(gterrors.ErrListArchived).SetType(gin.ErrorTypePublic)
 		return
 	}
 
@@ -0,0 +1,111 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Updates the title, description or sharing of a template. Only the owner or
+// a user who may manage all lists can update it.
+func (controller *TodoController) UpdateTemplate(ctx *gin.Context) {
+	var payload *schemas.UpdateTemplate
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	} else if payload.Title == nil && payload.Description == nil && payload.IsShared == nil {
+		ctx.Error(errors.New("either title, description or is_shared is required")).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	templateID := ctx.Param("templateID")
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	oldTemplate := controller.getVisibleTemplate(reqUser, templateID, ctx)
+	if oldTemplate == nil {
+		return
+	}
+	if oldTemplate.UserID != reqUser.ID && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionListsManage,
+		logging.SecurityScoreLow,
+		fmt.Sprintf("template: %v", templateID),
+		ctx,
+	) {
+		return
+	}
+
+	title := oldTemplate.Title
+	description := oldTemplate.Description
+	isShared := oldTemplate.IsShared
+	if payload.Title != nil {
+		title = *payload.Title
+	}
+	if payload.Description != nil {
+		description = pgtype.Text{String: *payload.Description, Valid: true}
+	}
+	if payload.IsShared != nil {
+		isShared = *payload.IsShared
+	}
+	if !validate.LengthTitle(title) {
+		ctx.Error(gterrors.NewGtValueError(title, "title too long"))
+		return
+	} else if !validate.LengthDescription(description.String) {
+		ctx.Error(gterrors.NewGtValueError(description.String, "description too long"))
+		return
+	}
+
+	args := &db.UpdateListTemplateParams{
+		Title:       title,
+		Description: description,
+		IsShared:    isShared,
+		ID:          oldTemplate.ID,
+	}
+	newTemplate, err := controller.db.UpdateListTemplate(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to update template", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&newTemplate,
+		oldTemplate,
+		logging.ObjectEventSubTemplate,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "template": newTemplate})
+}
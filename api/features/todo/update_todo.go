@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime"
 	"slices"
+	"time"
 
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
@@ -12,6 +13,7 @@ import (
 	"go-todo/schemas"
 	"go-todo/util/database"
 	"go-todo/util/mycontext"
+	"go-todo/util/validate"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -26,7 +28,12 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 	} else if payload.Title == nil &&
 		payload.Description == nil &&
 		payload.CompleteBefore == nil &&
-		payload.Completed == nil {
+		payload.DueDate == nil &&
+		payload.Completed == nil &&
+		payload.EstimatedDuration == nil &&
+		payload.Priority == nil &&
+		payload.Tags == nil &&
+		payload.Recurrence == nil {
 		ctx.JSON(200, gin.H{"status": "not-modified"})
 		return
 	}
@@ -51,6 +58,23 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 		return
 	}
 
+	newTodo := controller.UpdateTodoAs(reqUser, listID, todoID, payload, ctx)
+	if newTodo == nil {
+		return
+	}
+	ctx.JSON(200, gin.H{"status": "ok", "todo": newTodo})
+}
+
+// Validates the payload, checks the user's right to change the todo and
+// updates it. Returns nil on failure, in which case the error is already
+// pushed to gin.Context.
+func (controller *TodoController) UpdateTodoAs(
+	reqUser *db.User,
+	listID string,
+	todoID string,
+	payload *schemas.UpdateTodo,
+	ctx *gin.Context,
+) *db.Todo {
 	listIds, err := controller.db.GetListIdsAccessible(ctx, reqUser.ID)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		_, file, line, _ := runtime.Caller(0)
@@ -61,7 +85,7 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 			err,
 			ctx,
 		)
-		return
+		return nil
 	}
 	if !slices.Contains(listIds, listID) {
 		logging.LogSecurityEvent(
@@ -72,7 +96,11 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 			reqUser.ID,
 		)
 		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
-		return
+		return nil
+	}
+
+	if ok := controller.isListWritable(listID, ctx); !ok {
+		return nil
 	}
 
 	args := &db.GetTodoByIdWithListIdParams{
@@ -89,32 +117,96 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 			reqUser.ID,
 		)
 		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
-		return
+		return nil
 	}
 
 	title := oldTodo.Title
 	description := oldTodo.Description.String
-	completeBefore := &oldTodo.CompleteBefore.Time
+	completeBefore := oldTodo.CompleteBefore
+	dueDate := oldTodo.DueDate
 	completed := oldTodo.Completed
+	estimatedDuration := oldTodo.EstimatedDuration
+	priority := oldTodo.Priority
+	tags := oldTodo.Tags
+	recurrence := oldTodo.Recurrence
 	if payload.Title != nil {
+		if ok := validate.LengthTitle(*payload.Title); !ok {
+			ctx.Error(gterrors.NewGtValueError(*payload.Title, "title too long"))
+			return nil
+		}
 		title = *payload.Title
 	}
 	if payload.Description != nil {
+		if ok := validate.LengthDescription(*payload.Description); !ok {
+			ctx.Error(gterrors.NewGtValueError(*payload.Description, "description too long"))
+			return nil
+		}
 		description = *payload.Description
 	}
-	if payload.CompleteBefore != nil {
-		completeBefore = payload.CompleteBefore
+	if payload.CompleteBefore != nil && payload.CompleteBefore.Year() != 1970 &&
+		payload.DueDate != nil && *payload.DueDate != "" {
+		ctx.Error(gterrors.NewGtValueError(*payload.DueDate, "due_date cannot be set with complete_before"))
+		return nil
+	}
+	if payload.CompleteBefore != nil && payload.CompleteBefore.Year() == 1970 {
+		completeBefore = pgtype.Timestamptz{}
+	} else if payload.CompleteBefore != nil {
+		completeBefore = pgtype.Timestamptz{Time: *payload.CompleteBefore, Valid: true}
+		dueDate = pgtype.Date{}
+	}
+	if payload.DueDate != nil && *payload.DueDate == "" {
+		dueDate = pgtype.Date{}
+	} else if payload.DueDate != nil {
+		date, err := time.Parse(time.DateOnly, *payload.DueDate)
+		if err != nil {
+			ctx.Error(gterrors.NewGtValueError(*payload.DueDate, "invalid due_date"))
+			return nil
+		}
+		dueDate = pgtype.Date{Time: date, Valid: true}
+		completeBefore = pgtype.Timestamptz{}
 	}
 	if payload.Completed != nil {
 		completed = *payload.Completed
 	}
+	if payload.EstimatedDuration != nil {
+		estimatedDuration = pgtype.Int4{Int32: *payload.EstimatedDuration, Valid: true}
+	}
+	if payload.Priority != nil {
+		priority = *payload.Priority
+	}
+	if payload.Tags != nil {
+		tags = []string{}
+		for _, tag := range payload.Tags {
+			if ok := validate.Tag(tag); !ok {
+				ctx.Error(gterrors.NewGtValueError(tag, "tag must be 1 to 30 chars without spaces, '#' or ','"))
+				return nil
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if payload.Recurrence != nil && *payload.Recurrence == "" {
+		recurrence = pgtype.Text{}
+	} else if payload.Recurrence != nil {
+		if ok := validate.Recurrence(*payload.Recurrence); !ok {
+			ctx.Error(gterrors.NewGtValueError(*payload.Recurrence, "invalid recurrence rule"))
+			return nil
+		}
+		recurrence = pgtype.Text{String: *payload.Recurrence, Valid: true}
+	}
 
 	updateArgs := &db.UpdateTodoParams{
-		ID:             todoID,
-		Title:          title,
-		Description:    pgtype.Text{String: description, Valid: true},
-		CompleteBefore: pgtype.Timestamp{Time: *completeBefore, Valid: completeBefore.Year() != 1970},
-		Completed:      completed,
+		ID:                todoID,
+		Title:             title,
+		Description:       pgtype.Text{String: description, Valid: true},
+		CompleteBefore:    completeBefore,
+		DueDate:           dueDate,
+		Completed:         completed,
+		EstimatedDuration: estimatedDuration,
+		Priority:          priority,
+		Tags:              tags,
+		Recurrence:        recurrence,
 	}
 	newTodo, err := controller.db.UpdateTodo(ctx, *updateArgs)
 	if err != nil {
@@ -126,7 +218,23 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 			err,
 			ctx,
 		)
-		return
+		return nil
+	}
+
+	// Completing a todo stops every timer still running on it.
+	if newTodo.Completed && !oldTodo.Completed {
+		stopArgs := &db.StopRunningTimeEntriesByTodoIdParams{
+			TodoID:    newTodo.ID,
+			StoppedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		}
+		if err := controller.db.StopRunningTimeEntriesByTodoId(ctx, *stopArgs); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			logging.LogError(
+				fmt.Errorf("failed to stop running timers: %w", err),
+				fmt.Sprintf("%v: %d", file, line),
+				err.Error(),
+			)
+		}
 	}
 
 	logging.LogObjectEvent(
@@ -138,5 +246,5 @@ func (controller *TodoController) UpdateTodo(ctx *gin.Context) {
 		&oldTodo,
 		logging.ObjectEventSubTodo,
 	)
-	ctx.JSON(200, gin.H{"status": "ok", "todo": newTodo})
+	return &newTodo
 }
@@ -0,0 +1,18 @@
+package transfer
+
+import (
+	"context"
+	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TransferController struct {
+	db   *db.Queries
+	pool *pgxpool.Pool
+	ctx  context.Context
+}
+
+func NewController(db *db.Queries, pool *pgxpool.Pool, ctx context.Context) *TransferController {
+	return &TransferController{db: db, pool: pool, ctx: ctx}
+}
@@ -0,0 +1,159 @@
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"slices"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Writes lists one at a time, so that an export never holds more than one
+// list in memory.
+type exporter interface {
+	writeList(list *dumpList) error
+	close() error
+}
+
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+	new         func(w io.Writer, exportedAt time.Time, loc *time.Location) (exporter, error)
+}{
+	"json": {"application/json; charset=utf-8", "json", newJSONExporter},
+	"csv":  {"text/csv; charset=utf-8", "csv", newCSVExporter},
+	"md":   {"text/markdown; charset=utf-8", "md", newMarkdownExporter},
+}
+
+// Exports every list accessible by the requester, or the ones given as
+// list_id, as a JSON dump, CSV or Markdown. The response is streamed list by
+// list.
+func (controller *TransferController) Export(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	formatName := ctx.DefaultQuery("format", "json")
+	format, ok := exportFormats[formatName]
+	if !ok {
+		ctx.Error(gterrors.NewGtValueError(formatName, "format must be json, csv or md"))
+		return
+	}
+
+	args := &db.GetListsAccessibleByUserIdParams{UserID: reqUser.ID, IncludeArchived: true}
+	lists, err := controller.db.GetListsAccessibleByUserId(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get lists", file, line, err, ctx)
+		return
+	}
+	if listIds := ctx.QueryArray("list_id"); len(listIds) > 0 {
+		for _, listID := range listIds {
+			accessible := slices.ContainsFunc(lists, func(list db.List) bool { return list.ID == listID })
+			if !accessible {
+				logging.LogSecurityEvent(
+					logging.SecurityScoreLow,
+					logging.SecurityEventForbiddenAction,
+					ctx.FullPath(),
+					listID,
+					reqUser.ID,
+				)
+				ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+				return
+			}
+		}
+		lists = slices.DeleteFunc(lists, func(list db.List) bool {
+			return !slices.Contains(listIds, list.ID)
+		})
+	}
+
+	loc, err := database.GetUserLocation(ctx, controller.db, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user time zone", file, line, err, ctx)
+		return
+	}
+
+	exportedAt := time.Now().UTC()
+	ctx.Header("Content-Type", format.contentType)
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="go-todo-export-%v.%v"`, exportedAt.Format("20060102"), format.extension),
+	)
+	ctx.Status(http.StatusOK)
+
+	// From here on the response has started, so errors can only be logged.
+	logError := func(message string, err error) {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(
+			fmt.Errorf("%v: %w", message, err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+	}
+	exp, err := format.new(ctx.Writer, exportedAt, loc)
+	if err != nil {
+		logError("failed to write export", err)
+		return
+	}
+	for _, list := range lists {
+		shares, err := controller.db.GetListShares(ctx, list.ID)
+		if err != nil {
+			logError("failed to get list shares", err)
+			return
+		}
+		todos, err := controller.db.GetTodosByListOrdered(ctx, list.ID)
+		if err != nil {
+			logError("failed to get todos", err)
+			return
+		}
+
+		entry := &dumpList{List: list, Shares: make([]dumpShare, 0, len(shares)), Todos: todos}
+		for _, share := range shares {
+			entry.Shares = append(entry.Shares, dumpShare{UserID: share.UserID, Username: share.Username})
+		}
+		if err := exp.writeList(entry); err != nil {
+			logError("failed to write export", err)
+			return
+		}
+		ctx.Writer.Flush()
+	}
+	if err := exp.close(); err != nil {
+		logError("failed to write export", err)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventRead,
+		reqUser,
+		lists,
+		nil,
+		logging.ObjectEventSubList,
+	)
+}
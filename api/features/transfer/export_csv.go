@@ -0,0 +1,73 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go-todo/util/txtutil"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func newCSVExporter(w io.Writer, _ time.Time, _ *time.Location) (exporter, error) {
+	exp := &csvExporter{w: csv.NewWriter(w)}
+	if err := exp.w.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+func (exp *csvExporter) writeList(list *dumpList) error {
+	for _, todo := range list.Todos {
+		estimatedDuration := ""
+		if todo.EstimatedDuration.Valid {
+			estimatedDuration = fmt.Sprint(todo.EstimatedDuration.Int32)
+		}
+		dueDate := ""
+		if todo.DueDate.Valid {
+			dueDate = todo.DueDate.Time.Format(time.DateOnly)
+		}
+		err := exp.w.Write([]string{
+			list.ID,
+			txtutil.CSVEscape(list.Title),
+			todo.ID,
+			todo.ParentID.String,
+			txtutil.CSVEscape(todo.Title),
+			txtutil.CSVEscape(todo.Description.String),
+			fmt.Sprint(todo.Completed),
+			csvTime(todo.CompletedAt),
+			csvTime(todo.CompleteBefore),
+			dueDate,
+			estimatedDuration,
+			fmt.Sprint(todo.Priority),
+			txtutil.CSVEscape(strings.Join(todo.Tags, " ")),
+			txtutil.CSVEscape(todo.Recurrence.String),
+			csvTime(todo.CreatedAt),
+			csvTime(todo.UpdatedAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	exp.w.Flush()
+	return exp.w.Error()
+}
+
+func (exp *csvExporter) close() error {
+	exp.w.Flush()
+	return exp.w.Error()
+}
+
+func csvTime(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
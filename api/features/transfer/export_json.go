@@ -0,0 +1,50 @@
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Writes the dump by hand around the lists, so that lists can be written as
+// they are read.
+type jsonExporter struct {
+	w     *bufio.Writer
+	first bool
+}
+
+func newJSONExporter(w io.Writer, exportedAt time.Time, _ *time.Location) (exporter, error) {
+	exp := &jsonExporter{w: bufio.NewWriter(w), first: true}
+	at, err := json.Marshal(exportedAt)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintf(exp.w, `{"version":%d,"exported_at":%s,"lists":[`, dumpVersion, at)
+	return exp, err
+}
+
+func (exp *jsonExporter) writeList(list *dumpList) error {
+	if !exp.first {
+		if err := exp.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	exp.first = false
+	entry, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if _, err := exp.w.Write(entry); err != nil {
+		return err
+	}
+	return exp.w.Flush()
+}
+
+func (exp *jsonExporter) close() error {
+	if _, err := exp.w.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return exp.w.Flush()
+}
@@ -0,0 +1,96 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	db "go-todo/db/sqlc"
+)
+
+// Writes every list as a heading with a checklist of its todos. Subtasks are
+// nested under their parent.
+type markdownExporter struct {
+	w   *bufio.Writer
+	loc *time.Location
+}
+
+func newMarkdownExporter(w io.Writer, _ time.Time, loc *time.Location) (exporter, error) {
+	return &markdownExporter{w: bufio.NewWriter(w), loc: loc}, nil
+}
+
+func (exp *markdownExporter) writeList(list *dumpList) error {
+	fmt.Fprintf(exp.w, "# %v\n\n", list.Title)
+	if list.Description.Valid && list.Description.String != "" {
+		fmt.Fprintf(exp.w, "%v\n\n", list.Description.String)
+	}
+
+	ids := make(map[string]bool, len(list.Todos))
+	for _, todo := range list.Todos {
+		ids[todo.ID] = true
+	}
+	children := make(map[string][]*db.Todo)
+	roots := []*db.Todo{}
+	for i := range list.Todos {
+		todo := &list.Todos[i]
+		// Todos whose parent is missing are written at the top level.
+		if todo.ParentID.Valid && ids[todo.ParentID.String] {
+			children[todo.ParentID.String] = append(children[todo.ParentID.String], todo)
+		} else {
+			roots = append(roots, todo)
+		}
+	}
+
+	var write func(todo *db.Todo, depth int)
+	write = func(todo *db.Todo, depth int) {
+		indent := strings.Repeat("  ", depth)
+		check := " "
+		if todo.Completed {
+			check = "x"
+		}
+		fmt.Fprintf(exp.w, "%v- [%v] %v%v\n", indent, check, todo.Title, exp.details(todo))
+		if todo.Description.Valid && todo.Description.String != "" {
+			for _, line := range strings.Split(todo.Description.String, "\n") {
+				fmt.Fprintf(exp.w, "%v  %v\n", indent, line)
+			}
+		}
+		for _, child := range children[todo.ID] {
+			write(child, depth+1)
+		}
+	}
+	for _, todo := range roots {
+		write(todo, 0)
+	}
+	if _, err := exp.w.WriteString("\n"); err != nil {
+		return err
+	}
+	return exp.w.Flush()
+}
+
+// The due date, priority and tags in the notation of the quick-add parser.
+func (exp *markdownExporter) details(todo *db.Todo) string {
+	details := ""
+	if todo.DueDate.Valid {
+		details += " (due " + todo.DueDate.Time.Format(time.DateOnly) + ")"
+	} else if todo.CompleteBefore.Valid {
+		details += " (due " + todo.CompleteBefore.Time.In(exp.loc).Format("2006-01-02 15:04") + ")"
+	}
+	switch todo.Priority {
+	case 1:
+		details += " !low"
+	case 2:
+		details += " !medium"
+	case 3:
+		details += " !high"
+	}
+	for _, tag := range todo.Tags {
+		details += " #" + tag
+	}
+	return details
+}
+
+func (exp *markdownExporter) close() error {
+	return exp.w.Flush()
+}
@@ -0,0 +1,52 @@
+package transfer
+
+import (
+	"time"
+
+	db "go-todo/db/sqlc"
+)
+
+// Version of the JSON dump. Bump it when a change to the format would make
+// older dumps read differently.
+const dumpVersion = 1
+
+// The JSON dump of lists. Holds every field, so that importing a dump gives
+// back the same lists.
+type dump struct {
+	Version    int        `json:"version"`
+	ExportedAt time.Time  `json:"exported_at"`
+	Lists      []dumpList `json:"lists"`
+}
+
+type dumpList struct {
+	db.List
+	Shares []dumpShare `json:"shares"`
+	// Every todo of the list, subtasks point to their parent with parent_id.
+	Todos []db.Todo `json:"todos"`
+}
+
+type dumpShare struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// The columns of the CSV format, one row per todo. Times are RFC 3339, tags
+// are separated by spaces and empty cells are null.
+var csvColumns = []string{
+	"list_id",
+	"list_title",
+	"id",
+	"parent_id",
+	"title",
+	"description",
+	"completed",
+	"completed_at",
+	"complete_before",
+	"due_date",
+	"estimated_duration",
+	"priority",
+	"tags",
+	"recurrence",
+	"created_at",
+	"updated_at",
+}
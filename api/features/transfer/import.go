@@ -0,0 +1,269 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxImportSize = 10 << 20
+
+type importReport struct {
+	Lists []importReportList `json:"lists"`
+	Todos int                `json:"todos"`
+	// Whether the lists are shared with the users in shares, which needs
+	// share=true.
+	Shared bool `json:"shared"`
+	// Entries of the file that are not imported, like closed Trello cards.
+	Skipped  int             `json:"skipped"`
+	Failures []importFailure `json:"failures"`
+}
+
+type importReportList struct {
+	ID     string   `json:"id,omitempty"`
+	Title  string   `json:"title"`
+	Todos  int      `json:"todos"`
+	Shares []string `json:"shares"`
+	// Usernames the list is not shared with, as they do not exist.
+	UnknownShares []string `json:"unknown_shares"`
+}
+
+// Imports the file in the body as new lists of the requester. format is json
+// for dumps made by Export, csv, todoist or trello. With dry_run set nothing
+// is created and the response reports what would be, including every value
+// that fails validation. Otherwise everything is created in one transaction,
+// or nothing if a value fails validation. The lists are only shared with the
+// users the file names if share is set, otherwise they are only reported.
+func (controller *TransferController) Import(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	dryRun := false
+	if value := ctx.Query("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			ctx.Error(gterrors.NewGtValueError(value, "dry_run must be true or false"))
+			return
+		}
+	}
+	share := false
+	if value := ctx.Query("share"); value != "" {
+		if share, err = strconv.ParseBool(value); err != nil {
+			ctx.Error(gterrors.NewGtValueError(value, "share must be true or false"))
+			return
+		}
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize))
+	if err != nil {
+		ctx.Error(gterrors.NewGtValueError("body", err.Error()))
+		return
+	}
+
+	var lists []*importList
+	var skipped int
+	format := ctx.DefaultQuery("format", "json")
+	switch format {
+	case "json":
+		lists, skipped, err = parseDump(body)
+	case "csv":
+		lists, skipped, err = parseCSV(body, ctx.DefaultQuery("list_title", "Imported"))
+	case "todoist":
+		lists, skipped, err = parseTodoist(body, ctx.DefaultQuery("list_title", "Todoist"))
+	case "trello":
+		lists, skipped, err = parseTrello(body)
+	default:
+		ctx.Error(gterrors.NewGtValueError(format, "format must be json, csv, todoist or trello"))
+		return
+	}
+	if err != nil {
+		ctx.Error(gterrors.NewGtValueError(format, err.Error()))
+		return
+	}
+	if len(lists) == 0 {
+		ctx.Error(gterrors.NewGtValueError(format, "file has no lists to import"))
+		return
+	}
+
+	report := &importReport{
+		Lists:    make([]importReportList, 0, len(lists)),
+		Shared:   share,
+		Skipped:  skipped,
+		Failures: validateImport(lists),
+	}
+	// The users to share each list with, by list.
+	shareUsers := make([][]string, len(lists))
+	for i, list := range lists {
+		entry := importReportList{
+			Title:         list.Title,
+			Todos:         len(list.Todos),
+			Shares:        []string{},
+			UnknownShares: []string{},
+		}
+		for _, username := range list.Shares {
+			user, err := controller.db.GetUserByUsername(ctx, username)
+			if errors.Is(err, pgx.ErrNoRows) {
+				entry.UnknownShares = append(entry.UnknownShares, username)
+				continue
+			} else if err != nil {
+				_, file, line, _ := runtime.Caller(0)
+				mycontext.CtxAddGtInternalError("failed to get user", file, line, err, ctx)
+				return
+			}
+			// Owners are not shared their own lists.
+			if user.ID == reqUser.ID {
+				continue
+			}
+			entry.Shares = append(entry.Shares, username)
+			if share {
+				shareUsers[i] = append(shareUsers[i], user.ID)
+			}
+		}
+		report.Lists = append(report.Lists, entry)
+		report.Todos += len(list.Todos)
+	}
+
+	if dryRun {
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok", "dry_run": true, "report": report})
+		return
+	}
+	if len(report.Failures) > 0 {
+		failure := report.Failures[0]
+		ctx.Error(gterrors.NewGtValueError(
+			failure.Field,
+			fmt.Sprintf("%v (%d failures in total, see dry_run)", failure, len(report.Failures)),
+		))
+		return
+	}
+
+	created := make([]db.List, 0, len(lists))
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		for i, list := range lists {
+			args := &db.CreateListParams{
+				ID:          uuid.New().String(),
+				UserID:      reqUser.ID,
+				Title:       list.Title,
+				Description: pgtype.Text{String: list.Description, Valid: list.Description != ""},
+			}
+			newList, err := qtx.CreateList(ctx, *args)
+			if err != nil {
+				return fmt.Errorf("failed to create list: %w", err)
+			}
+			if list.Archived {
+				if newList, err = qtx.ArchiveList(ctx, newList.ID); err != nil {
+					return fmt.Errorf("failed to archive list: %w", err)
+				}
+			}
+			for _, userID := range shareUsers[i] {
+				shareArgs := &db.CreateListShareParams{UserID: userID, ListID: newList.ID}
+				if err := qtx.CreateListShare(ctx, *shareArgs); err != nil {
+					return fmt.Errorf("failed to share list: %w", err)
+				}
+			}
+
+			// Maps the refs of the file to the ids of the created todos.
+			ids := make(map[string]string, len(list.Todos))
+			for _, todo := range orderByParent(list.Todos) {
+				todoArgs := importTodoParams(todo, newList.ID, reqUser.ID)
+				if parentID, ok := ids[todo.ParentRef]; ok {
+					todoArgs.ParentID = pgtype.Text{String: parentID, Valid: true}
+				}
+				newTodo, err := qtx.ImportTodo(ctx, *todoArgs)
+				if err != nil {
+					return fmt.Errorf("failed to create todo: %w", err)
+				}
+				if todo.Ref != "" {
+					ids[todo.Ref] = newTodo.ID
+				}
+			}
+
+			created = append(created, newList)
+			report.Lists[i].ID = newList.ID
+		}
+		return nil
+	})
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to import", file, line, err, ctx)
+		return
+	}
+
+	for _, list := range created {
+		logging.LogObjectEvent(
+			ctx.FullPath(),
+			ctx.ClientIP(),
+			logging.ObjectEventCreate,
+			reqUser,
+			&list,
+			nil,
+			logging.ObjectEventSubList,
+		)
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "report": report})
+}
+
+func importTodoParams(todo *importTodo, listID string, userID string) *db.ImportTodoParams {
+	args := &db.ImportTodoParams{
+		ID:          uuid.New().String(),
+		ListID:      listID,
+		UserID:      userID,
+		Title:       todo.Title,
+		Description: pgtype.Text{String: todo.Description, Valid: true},
+		Completed:   todo.Completed,
+		Priority:    todo.Priority,
+		Tags:        []string{},
+		Recurrence:  pgtype.Text{String: todo.Recurrence, Valid: todo.Recurrence != ""},
+	}
+	for _, tag := range todo.Tags {
+		if !slices.Contains(args.Tags, tag) {
+			args.Tags = append(args.Tags, tag)
+		}
+	}
+	if todo.Completed {
+		completedAt := time.Now().UTC()
+		if todo.CompletedAt != nil {
+			completedAt = *todo.CompletedAt
+		}
+		args.CompletedAt = pgtype.Timestamptz{Time: completedAt, Valid: true}
+	}
+	if todo.CompleteBefore != nil {
+		args.CompleteBefore = pgtype.Timestamptz{Time: *todo.CompleteBefore, Valid: true}
+	}
+	if todo.DueDate != nil {
+		args.DueDate = pgtype.Date{Time: *todo.DueDate, Valid: true}
+	}
+	if todo.EstimatedDuration != nil {
+		args.EstimatedDuration = pgtype.Int4{Int32: *todo.EstimatedDuration, Valid: true}
+	}
+	return args
+}
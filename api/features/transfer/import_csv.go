@@ -0,0 +1,122 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-todo/util/txtutil"
+)
+
+// Reads a CSV with a header row naming its columns, in any order. The columns
+// are the ones of the CSV export:
+//
+//   - title is required, every other column is optional.
+//   - Rows are grouped into lists by list_id, or by list_title without it.
+//     Rows without either go to the list named by defaultList.
+//   - id names the row, so that parent_id of other rows can point to it.
+//   - completed is true, 1 or x. Times are RFC 3339, due_date is a date like
+//     2025-01-31 and tags are separated by spaces.
+//   - A quote before a cell that would be a formula, as the export adds, is
+//     removed.
+//   - Unknown columns are ignored.
+func parseCSV(body []byte, defaultList string) ([]*importList, int, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, 0, fmt.Errorf("csv has no header row")
+	}
+	header := records[0]
+	if !slices.Contains(header, "title") {
+		return nil, 0, fmt.Errorf("csv has no title column")
+	}
+
+	lists := []*importList{}
+	byKey := make(map[string]*importList)
+	for i, record := range records[1:] {
+		row := i + 2
+		get := func(column string) string {
+			if index := slices.Index(header, column); index >= 0 && index < len(record) {
+				return txtutil.CSVUnescape(strings.TrimSpace(record[index]))
+			}
+			return ""
+		}
+
+		key := get("list_id")
+		if key == "" {
+			key = get("list_title")
+		}
+		list, ok := byKey[key]
+		if !ok {
+			title := get("list_title")
+			if title == "" {
+				title = defaultList
+			}
+			list = &importList{Title: title}
+			byKey[key] = list
+			lists = append(lists, list)
+		}
+
+		todo := &importTodo{
+			Ref:         get("id"),
+			ParentRef:   get("parent_id"),
+			Title:       get("title"),
+			Description: get("description"),
+			Recurrence:  get("recurrence"),
+			Tags:        strings.Fields(get("tags")),
+		}
+		switch strings.ToLower(get("completed")) {
+		case "true", "1", "x":
+			todo.Completed = true
+		}
+		if todo.CompletedAt, err = csvParseTime(get("completed_at")); err != nil {
+			return nil, 0, fmt.Errorf("row %d: invalid completed_at: %w", row, err)
+		}
+		if todo.CompleteBefore, err = csvParseTime(get("complete_before")); err != nil {
+			return nil, 0, fmt.Errorf("row %d: invalid complete_before: %w", row, err)
+		}
+		if value := get("due_date"); value != "" {
+			date, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("row %d: invalid due_date: %w", row, err)
+			}
+			todo.DueDate = &date
+		}
+		if value := get("estimated_duration"); value != "" {
+			duration, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, 0, fmt.Errorf("row %d: invalid estimated_duration: %w", row, err)
+			}
+			minutes := int32(duration)
+			todo.EstimatedDuration = &minutes
+		}
+		if value := get("priority"); value != "" {
+			priority, err := strconv.ParseInt(value, 10, 16)
+			if err != nil {
+				return nil, 0, fmt.Errorf("row %d: invalid priority: %w", row, err)
+			}
+			todo.Priority = int16(priority)
+		}
+		list.Todos = append(list.Todos, todo)
+	}
+	return lists, 0, nil
+}
+
+func csvParseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,136 @@
+package transfer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	exp, err := newCSVExporter(&buf, time.Now(), time.UTC)
+	if err != nil {
+		t.Fatalf("newCSVExporter failed: %v", err)
+	}
+	if err := exp.writeList(sampleList()); err != nil {
+		t.Fatalf("writeList failed: %v", err)
+	}
+	if err := exp.close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	// Spreadsheets would run these as formulas.
+	for _, cell := range []string{"\n=Household", ",-Pay rent", `,"+line one`, ",@home"} {
+		if strings.Contains(buf.String(), cell) {
+			t.Errorf("export has unquoted cell %q:\n%s", cell, buf.String())
+		}
+	}
+
+	lists, _, err := parseCSV(buf.Bytes(), "Imported")
+	if err != nil {
+		t.Fatalf("parseCSV failed: %v", err)
+	}
+	// The CSV has no list descriptions and shares.
+	want := sampleImport()
+	want[0].Description = ""
+	want[0].Shares = nil
+	if !reflect.DeepEqual(lists, want) {
+		t.Errorf("got\n%s\nwant\n%s", dumpImport(lists), dumpImport(want))
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	due := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2025, time.January, 30, 9, 0, 0, 0, time.UTC)
+	duration := int32(30)
+	tests := []struct {
+		name  string
+		input string
+		want  []*importList
+		err   string
+	}{
+		{
+			name:  "title only",
+			input: "title\nBuy milk\n",
+			want:  []*importList{{Title: "Imported", Todos: []*importTodo{{Title: "Buy milk", Tags: []string{}}}}},
+		},
+		{
+			name:  "columns in any order with unknown ones",
+			input: "tags,color,title,priority\nhome  work,red,Clean,2\n",
+			want: []*importList{{Title: "Imported", Todos: []*importTodo{
+				{Title: "Clean", Tags: []string{"home", "work"}, Priority: 2},
+			}}},
+		},
+		{
+			name:  "grouped by list title without list id",
+			input: "list_title,title\nA,one\nB,two\nA,three\n",
+			want: []*importList{
+				{Title: "A", Todos: []*importTodo{{Title: "one", Tags: []string{}}, {Title: "three", Tags: []string{}}}},
+				{Title: "B", Todos: []*importTodo{{Title: "two", Tags: []string{}}}},
+			},
+		},
+		{
+			name:  "grouped by list id",
+			input: "list_id,list_title,title\n1,Same,one\n2,Same,two\n",
+			want: []*importList{
+				{Title: "Same", Todos: []*importTodo{{Title: "one", Tags: []string{}}}},
+				{Title: "Same", Todos: []*importTodo{{Title: "two", Tags: []string{}}}},
+			},
+		},
+		{
+			name:  "every field",
+			input: "id,parent_id,title,description,completed,completed_at,due_date,estimated_duration,recurrence\na,,Parent,,x,2025-01-30T09:00:00Z,2025-01-31,30,FREQ=DAILY\nb,a,Child,Some text,false,,,,\n",
+			want: []*importList{{Title: "Imported", Todos: []*importTodo{
+				{
+					Ref:               "a",
+					Title:             "Parent",
+					Completed:         true,
+					CompletedAt:       &completedAt,
+					DueDate:           &due,
+					EstimatedDuration: &duration,
+					Tags:              []string{},
+					Recurrence:        "FREQ=DAILY",
+				},
+				{Ref: "b", ParentRef: "a", Title: "Child", Description: "Some text", Tags: []string{}},
+			}}},
+		},
+		{
+			name:  "quotes before formulas are removed",
+			input: "title,description\n'=SUM(A1),'hello\n''-x,'\n",
+			want: []*importList{{Title: "Imported", Todos: []*importTodo{
+				{Title: "=SUM(A1)", Description: "'hello", Tags: []string{}},
+				{Title: "'-x", Description: "'", Tags: []string{}},
+			}}},
+		},
+		{
+			name:  "short rows",
+			input: "title,description,priority\nOnly title\n",
+			want:  []*importList{{Title: "Imported", Todos: []*importTodo{{Title: "Only title", Tags: []string{}}}}},
+		},
+		{name: "empty", input: "", err: "csv has no header row"},
+		{name: "no title column", input: "name\nBuy milk\n", err: "csv has no title column"},
+		{name: "unclosed quote", input: "title\n\"Buy milk\n", err: "invalid csv"},
+		{name: "invalid time", input: "title,completed_at\nx\n\ny,yesterday\n", err: "row 3: invalid completed_at"},
+		{name: "invalid due date", input: "title,due_date\nx,2025-02-30\n", err: "row 2: invalid due_date"},
+		{name: "invalid duration", input: "title,estimated_duration\nx,99999999999\n", err: "row 2: invalid estimated_duration"},
+		{name: "invalid priority", input: "title,priority\nx,high\n", err: "row 2: invalid priority"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lists, _, err := parseCSV([]byte(test.input), "Imported")
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCSV failed: %v", err)
+			}
+			if !reflect.DeepEqual(lists, test.want) {
+				t.Errorf("got\n%s\nwant\n%s", dumpImport(lists), dumpImport(test.want))
+			}
+		})
+	}
+}
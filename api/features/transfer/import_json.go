@@ -0,0 +1,63 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Reads a JSON dump made by Export.
+func parseDump(body []byte) ([]*importList, int, error) {
+	var d dump
+	if err := json.Unmarshal(body, &d); err != nil {
+		return nil, 0, fmt.Errorf("invalid dump: %w", err)
+	}
+	if d.Version < 1 || d.Version > dumpVersion {
+		return nil, 0, fmt.Errorf("unsupported dump version %v", d.Version)
+	}
+
+	lists := make([]*importList, 0, len(d.Lists))
+	for _, entry := range d.Lists {
+		list := &importList{
+			Title:       entry.Title,
+			Description: entry.Description.String,
+			Archived:    entry.ArchivedAt.Valid,
+		}
+		for _, share := range entry.Shares {
+			list.Shares = append(list.Shares, share.Username)
+		}
+		for _, todo := range entry.Todos {
+			item := &importTodo{
+				Ref:            todo.ID,
+				ParentRef:      todo.ParentID.String,
+				Title:          todo.Title,
+				Description:    todo.Description.String,
+				Completed:      todo.Completed,
+				CompletedAt:    timestamp(todo.CompletedAt),
+				CompleteBefore: timestamp(todo.CompleteBefore),
+				Priority:       todo.Priority,
+				Tags:           todo.Tags,
+				Recurrence:     todo.Recurrence.String,
+			}
+			if todo.DueDate.Valid {
+				date := todo.DueDate.Time
+				item.DueDate = &date
+			}
+			if todo.EstimatedDuration.Valid {
+				item.EstimatedDuration = &todo.EstimatedDuration.Int32
+			}
+			list.Todos = append(list.Todos, item)
+		}
+		lists = append(lists, list)
+	}
+	return lists, 0, nil
+}
+
+func timestamp(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,150 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// A list with a subtask and every field of a todo set, the way the database
+// returns it.
+func sampleList() *dumpList {
+	completedAt := time.Date(2025, time.January, 30, 8, 0, 0, 0, time.UTC)
+	completeBefore := time.Date(2025, time.February, 1, 17, 30, 0, 0, time.UTC)
+	dueDate := time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)
+	return &dumpList{
+		List: db.List{
+			ID:          "list-1",
+			Title:       "=Household, chores",
+			Description: pgtype.Text{String: "Things to do", Valid: true},
+		},
+		Shares: []dumpShare{{UserID: "user-2", Username: "bob"}},
+		Todos: []db.Todo{
+			{
+				ID:             "todo-1",
+				Title:          "-Pay rent",
+				Description:    pgtype.Text{String: "+line one\nline \"two\"", Valid: true},
+				Completed:      true,
+				CompletedAt:    pgtype.Timestamptz{Time: completedAt, Valid: true},
+				CompleteBefore: pgtype.Timestamptz{Time: completeBefore, Valid: true},
+				Priority:       3,
+				Tags:           []string{"@home", "money"},
+				Recurrence:     pgtype.Text{String: "FREQ=MONTHLY;BYMONTHDAY=1", Valid: true},
+			},
+			{
+				ID:                "todo-2",
+				ParentID:          pgtype.Text{String: "todo-1", Valid: true},
+				Title:             "'=not a formula",
+				DueDate:           pgtype.Date{Time: dueDate, Valid: true},
+				EstimatedDuration: pgtype.Int4{Int32: 45, Valid: true},
+				Tags:              []string{},
+			},
+		},
+	}
+}
+
+// The lists sampleList is read back as.
+func sampleImport() []*importList {
+	list := sampleList()
+	completedAt := list.Todos[0].CompletedAt.Time
+	completeBefore := list.Todos[0].CompleteBefore.Time
+	dueDate := list.Todos[1].DueDate.Time
+	duration := int32(45)
+	return []*importList{{
+		Title:       list.Title,
+		Description: list.Description.String,
+		Shares:      []string{"bob"},
+		Todos: []*importTodo{
+			{
+				Ref:            "todo-1",
+				Title:          "-Pay rent",
+				Description:    "+line one\nline \"two\"",
+				Completed:      true,
+				CompletedAt:    &completedAt,
+				CompleteBefore: &completeBefore,
+				Priority:       3,
+				Tags:           []string{"@home", "money"},
+				Recurrence:     "FREQ=MONTHLY;BYMONTHDAY=1",
+			},
+			{
+				Ref:               "todo-2",
+				ParentRef:         "todo-1",
+				Title:             "'=not a formula",
+				DueDate:           &dueDate,
+				EstimatedDuration: &duration,
+				Tags:              []string{},
+			},
+		},
+	}}
+}
+
+func TestParseDumpRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	exp, err := newJSONExporter(&buf, time.Now(), time.UTC)
+	if err != nil {
+		t.Fatalf("newJSONExporter failed: %v", err)
+	}
+	if err := exp.writeList(sampleList()); err != nil {
+		t.Fatalf("writeList failed: %v", err)
+	}
+	if err := exp.close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	lists, skipped, err := parseDump(buf.Bytes())
+	if err != nil {
+		t.Fatalf("parseDump failed: %v", err)
+	}
+	if skipped != 0 {
+		t.Errorf("skipped = %d, want 0", skipped)
+	}
+	if want := sampleImport(); !reflect.DeepEqual(lists, want) {
+		t.Errorf("got\n%s\nwant\n%s", dumpImport(lists), dumpImport(want))
+	}
+}
+
+func TestParseDump(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		lists int
+		err   string
+	}{
+		{name: "empty", input: `{"version":1,"lists":[]}`},
+		{name: "archived", input: `{"version":1,"lists":[{"title":"a","archived_at":"2025-01-01T00:00:00Z","todos":[]}]}`, lists: 1},
+		{name: "no version", input: `{"lists":[]}`, err: "unsupported dump version 0"},
+		{name: "newer version", input: `{"version":2,"lists":[]}`, err: "unsupported dump version 2"},
+		{name: "not json", input: `title,description`, err: "invalid dump"},
+		{name: "wrong type", input: `{"version":1,"lists":{}}`, err: "invalid dump"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lists, _, err := parseDump([]byte(test.input))
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDump failed: %v", err)
+			}
+			if len(lists) != test.lists {
+				t.Errorf("got %d lists, want %d", len(lists), test.lists)
+			}
+		})
+	}
+}
+
+// Formats the lists for failure messages, as they are made of pointers.
+func dumpImport(lists []*importList) string {
+	out, _ := json.MarshalIndent(lists, "", "  ")
+	return string(out)
+}
@@ -0,0 +1,142 @@
+package transfer
+
+import (
+	"fmt"
+	"time"
+
+	"go-todo/features/todo"
+	"go-todo/util/validate"
+)
+
+// What an import creates, read from any of the import formats.
+type importList struct {
+	Title       string
+	Description string
+	Archived    bool
+	// Usernames the list is shared with.
+	Shares []string
+	Todos  []*importTodo
+}
+
+type importTodo struct {
+	// Identifies the todo within the file, so that subtasks can point to it
+	// with ParentRef.
+	Ref            string
+	ParentRef      string
+	Title          string
+	Description    string
+	Completed      bool
+	CompletedAt    *time.Time
+	CompleteBefore *time.Time
+	// A date without time, read as midnight UTC.
+	DueDate           *time.Time
+	EstimatedDuration *int32
+	Priority          int16
+	Tags              []string
+	Recurrence        string
+}
+
+// A value that would be rejected when creating the list or todo by hand.
+type importFailure struct {
+	List   string `json:"list"`
+	Todo   string `json:"todo,omitempty"`
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+func (failure importFailure) String() string {
+	if failure.Todo == "" {
+		return fmt.Sprintf("list '%v': %v", failure.List, failure.Detail)
+	}
+	return fmt.Sprintf("list '%v', todo '%v': %v", failure.List, failure.Todo, failure.Detail)
+}
+
+// Checks the lists against the same rules as the list and todo endpoints.
+func validateImport(lists []*importList) []importFailure {
+	failures := []importFailure{}
+	for _, list := range lists {
+		fail := func(todo *importTodo, field, detail string) {
+			failure := importFailure{List: list.Title, Field: field, Detail: detail}
+			if todo != nil {
+				failure.Todo = todo.Title
+			}
+			failures = append(failures, failure)
+		}
+
+		if list.Title == "" {
+			fail(nil, "title", "title is empty")
+		} else if !validate.LengthTitle(list.Title) {
+			fail(nil, "title", "title too long")
+		}
+		if !validate.LengthDescription(list.Description) {
+			fail(nil, "description", "description too long")
+		}
+
+		refs := make(map[string]*importTodo, len(list.Todos))
+		for _, todo := range list.Todos {
+			if todo.Ref == "" {
+				continue
+			}
+			if refs[todo.Ref] != nil {
+				fail(todo, "id", fmt.Sprintf("id '%v' is used by more than one todo", todo.Ref))
+			}
+			refs[todo.Ref] = todo
+		}
+		for _, todo := range list.Todos {
+			if todo.Title == "" {
+				fail(todo, "title", "title is empty")
+			} else if !validate.LengthTitle(todo.Title) {
+				fail(todo, "title", "title too long")
+			}
+			if !validate.LengthDescription(todo.Description) {
+				fail(todo, "description", "description too long")
+			}
+			for _, tag := range todo.Tags {
+				if !validate.Tag(tag) {
+					fail(todo, "tags", fmt.Sprintf("tag '%v' must be 1 to 30 chars without spaces, '#' or ','", tag))
+				}
+			}
+			if todo.Recurrence != "" && !validate.Recurrence(todo.Recurrence) {
+				fail(todo, "recurrence", "invalid recurrence rule")
+			}
+			if todo.Priority < 0 || todo.Priority > 3 {
+				fail(todo, "priority", "priority must be between 0 and 3")
+			}
+			if todo.EstimatedDuration != nil && *todo.EstimatedDuration < 0 {
+				fail(todo, "estimated_duration", "estimated_duration cannot be negative")
+			}
+			if todo.CompleteBefore != nil && todo.DueDate != nil {
+				fail(todo, "due_date", "due_date cannot be set with complete_before")
+			}
+			if todo.ParentRef != "" && refs[todo.ParentRef] == nil {
+				fail(todo, "parent_id", fmt.Sprintf("parent '%v' is not in the list", todo.ParentRef))
+			}
+		}
+
+		// Todos in a cycle, and their subtasks, are not reached from the
+		// todos without a parent, so they are left out of the order.
+		ordered := orderByParent(list.Todos)
+		if len(ordered) < len(list.Todos) {
+			reached := make(map[*importTodo]bool, len(ordered))
+			for _, todo := range ordered {
+				reached[todo] = true
+			}
+			for _, todo := range list.Todos {
+				if !reached[todo] {
+					fail(todo, "parent_id", "subtasks form a cycle")
+				}
+			}
+		}
+	}
+	return failures
+}
+
+// Orders the todos so that every parent comes before its subtasks. Expects
+// refs to be unique. Todos in a cycle, and their subtasks, are left out.
+func orderByParent(todos []*importTodo) []*importTodo {
+	return todo.ParentsFirst(
+		todos,
+		func(item *importTodo) string { return item.Ref },
+		func(item *importTodo) (string, bool) { return item.ParentRef, item.ParentRef != "" },
+	)
+}
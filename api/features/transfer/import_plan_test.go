@@ -0,0 +1,118 @@
+package transfer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateImport(t *testing.T) {
+	date := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	negative := int32(-1)
+	tests := []struct {
+		name  string
+		lists []*importList
+		want  []importFailure
+	}{
+		{
+			name: "valid",
+			lists: []*importList{{Title: "List", Todos: []*importTodo{
+				{Ref: "1", Title: "Parent", Tags: []string{"home"}, Priority: 3, DueDate: &date},
+				{Ref: "2", ParentRef: "1", Title: "Child", Recurrence: "FREQ=WEEKLY"},
+				{Title: "Without ref"},
+			}}},
+			want: []importFailure{},
+		},
+		{
+			name:  "empty titles",
+			lists: []*importList{{Todos: []*importTodo{{Ref: "1"}}}},
+			want: []importFailure{
+				{Field: "title", Detail: "title is empty"},
+				{Field: "title", Detail: "title is empty"},
+			},
+		},
+		{
+			name:  "title too long",
+			lists: []*importList{{Title: strings.Repeat("x", 1000)}},
+			want:  []importFailure{{List: strings.Repeat("x", 1000), Field: "title", Detail: "title too long"}},
+		},
+		{
+			name: "duplicate refs",
+			lists: []*importList{{Title: "List", Todos: []*importTodo{
+				{Ref: "1", Title: "a"},
+				{Ref: "1", Title: "b"},
+			}}},
+			want: []importFailure{{List: "List", Todo: "b", Field: "id", Detail: "id '1' is used by more than one todo"}},
+		},
+		{
+			name: "missing parent",
+			lists: []*importList{{Title: "List", Todos: []*importTodo{
+				{Ref: "1", ParentRef: "9", Title: "a"},
+			}}},
+			want: []importFailure{
+				{List: "List", Todo: "a", Field: "parent_id", Detail: "parent '9' is not in the list"},
+			},
+		},
+		{
+			name: "parents in other lists",
+			lists: []*importList{
+				{Title: "A", Todos: []*importTodo{{Ref: "1", Title: "a"}}},
+				{Title: "B", Todos: []*importTodo{{Ref: "2", ParentRef: "1", Title: "b"}}},
+			},
+			want: []importFailure{
+				{List: "B", Todo: "b", Field: "parent_id", Detail: "parent '1' is not in the list"},
+			},
+		},
+		{
+			name: "cycle with subtask",
+			lists: []*importList{{Title: "List", Todos: []*importTodo{
+				{Ref: "1", Title: "root"},
+				{Ref: "2", ParentRef: "3", Title: "a"},
+				{Ref: "3", ParentRef: "2", Title: "b"},
+				{Ref: "4", ParentRef: "3", Title: "c"},
+			}}},
+			want: []importFailure{
+				{List: "List", Todo: "a", Field: "parent_id", Detail: "subtasks form a cycle"},
+				{List: "List", Todo: "b", Field: "parent_id", Detail: "subtasks form a cycle"},
+				{List: "List", Todo: "c", Field: "parent_id", Detail: "subtasks form a cycle"},
+			},
+		},
+		{
+			name: "own parent",
+			lists: []*importList{{Title: "List", Todos: []*importTodo{
+				{Ref: "1", ParentRef: "1", Title: "a"},
+			}}},
+			want: []importFailure{{List: "List", Todo: "a", Field: "parent_id", Detail: "subtasks form a cycle"}},
+		},
+		{
+			name: "invalid fields",
+			lists: []*importList{{Title: "List", Todos: []*importTodo{
+				{Title: "priority", Priority: 4},
+				{Title: "negative priority", Priority: -1},
+				{Title: "duration", EstimatedDuration: &negative},
+				{Title: "dates", DueDate: &date, CompleteBefore: &date},
+				{Title: "tags", Tags: []string{"ok", "two words", "#hash", ""}},
+				{Title: "recurrence", Recurrence: "every day"},
+			}}},
+			want: []importFailure{
+				{List: "List", Todo: "priority", Field: "priority", Detail: "priority must be between 0 and 3"},
+				{List: "List", Todo: "negative priority", Field: "priority", Detail: "priority must be between 0 and 3"},
+				{List: "List", Todo: "duration", Field: "estimated_duration", Detail: "estimated_duration cannot be negative"},
+				{List: "List", Todo: "dates", Field: "due_date", Detail: "due_date cannot be set with complete_before"},
+				{List: "List", Todo: "tags", Field: "tags", Detail: "tag 'two words' must be 1 to 30 chars without spaces, '#' or ','"},
+				{List: "List", Todo: "tags", Field: "tags", Detail: "tag '#hash' must be 1 to 30 chars without spaces, '#' or ','"},
+				{List: "List", Todo: "tags", Field: "tags", Detail: "tag '' must be 1 to 30 chars without spaces, '#' or ','"},
+				{List: "List", Todo: "recurrence", Field: "recurrence", Detail: "invalid recurrence rule"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			failures := validateImport(test.lists)
+			if !reflect.DeepEqual(failures, test.want) {
+				t.Errorf("got\n%v\nwant\n%v", failures, test.want)
+			}
+		})
+	}
+}
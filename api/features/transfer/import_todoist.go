@@ -0,0 +1,92 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reads the CSV export of a Todoist project into one list named title. Tasks
+// are nested by their INDENT, @labels in the content become tags and
+// priorities 1 (highest) to 4 map to high to none. Dates are only kept when
+// they are plain dates, not phrases like "every monday". Sections and notes
+// are skipped.
+func parseTodoist(body []byte, title string) ([]*importList, int, error) {
+	// Todoist writes a byte order mark.
+	body = bytes.TrimPrefix(body, []byte("\ufeff"))
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid todoist csv: %w", err)
+	}
+	if len(records) == 0 || !slices.Contains(records[0], "TYPE") || !slices.Contains(records[0], "CONTENT") {
+		return nil, 0, fmt.Errorf("todoist csv has no TYPE and CONTENT columns")
+	}
+	header := records[0]
+
+	list := &importList{Title: title}
+	skipped := 0
+	// The last task seen at every indent, the parents of deeper tasks.
+	parents := []*importTodo{}
+	for i, record := range records[1:] {
+		get := func(column string) string {
+			if index := slices.Index(header, column); index >= 0 && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		if len(record) == 1 && record[0] == "" {
+			continue
+		}
+		if get("TYPE") != "task" {
+			skipped++
+			continue
+		}
+
+		todo := &importTodo{
+			Ref:         strconv.Itoa(i),
+			Description: get("DESCRIPTION"),
+			Tags:        []string{},
+		}
+		words := []string{}
+		for _, word := range strings.Fields(get("CONTENT")) {
+			if label, ok := strings.CutPrefix(word, "@"); ok && label != "" {
+				todo.Tags = append(todo.Tags, label)
+				continue
+			}
+			words = append(words, word)
+		}
+		todo.Title = strings.Join(words, " ")
+		switch get("PRIORITY") {
+		case "1":
+			todo.Priority = 3
+		case "2":
+			todo.Priority = 2
+		case "3":
+			todo.Priority = 1
+		}
+		if date, err := time.Parse(time.DateOnly, get("DATE")); err == nil {
+			todo.DueDate = &date
+		}
+
+		indent, err := strconv.Atoi(get("INDENT"))
+		if err != nil || indent < 1 {
+			indent = 1
+		}
+		if indent > len(parents)+1 {
+			indent = len(parents) + 1
+		}
+		parents = parents[:indent-1]
+		if len(parents) > 0 {
+			todo.ParentRef = parents[len(parents)-1].Ref
+		}
+		parents = append(parents, todo)
+		list.Todos = append(list.Todos, todo)
+	}
+	return []*importList{list}, skipped, nil
+}
@@ -0,0 +1,108 @@
+package transfer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTodoist(t *testing.T) {
+	due := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	header := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n"
+	tests := []struct {
+		name    string
+		input   string
+		want    []*importTodo
+		skipped int
+		err     string
+	}{
+		{
+			name:  "byte order mark",
+			input: "\ufeff" + header + "task,Buy milk,,4,1,,,,en,UTC\n",
+			want:  []*importTodo{{Ref: "0", Title: "Buy milk", Tags: []string{}}},
+		},
+		{
+			name:  "labels become tags",
+			input: header + "task,Call @home mum @phone @,,4,1,,,,en,UTC\n",
+			want:  []*importTodo{{Ref: "0", Title: "Call mum @", Tags: []string{"home", "phone"}}},
+		},
+		{
+			name: "priorities",
+			input: header +
+				"task,p1,,1,1,,,,en,UTC\n" +
+				"task,p2,,2,1,,,,en,UTC\n" +
+				"task,p3,,3,1,,,,en,UTC\n" +
+				"task,p4,,4,1,,,,en,UTC\n",
+			want: []*importTodo{
+				{Ref: "0", Title: "p1", Priority: 3, Tags: []string{}},
+				{Ref: "1", Title: "p2", Priority: 2, Tags: []string{}},
+				{Ref: "2", Title: "p3", Priority: 1, Tags: []string{}},
+				{Ref: "3", Title: "p4", Tags: []string{}},
+			},
+		},
+		{
+			name: "only plain dates",
+			input: header +
+				"task,Pay rent,,4,1,,,2025-03-01,en,UTC\n" +
+				"task,Water plants,,4,1,,,every monday,en,UTC\n",
+			want: []*importTodo{
+				{Ref: "0", Title: "Pay rent", DueDate: &due, Tags: []string{}},
+				{Ref: "1", Title: "Water plants", Tags: []string{}},
+			},
+		},
+		{
+			name: "nested by indent",
+			input: header +
+				"task,a,,4,1,,,,en,UTC\n" +
+				"task,a.1,,4,2,,,,en,UTC\n" +
+				"task,a.1.1,,4,3,,,,en,UTC\n" +
+				"task,a.2,,4,2,,,,en,UTC\n" +
+				"task,b,,4,1,,,,en,UTC\n" +
+				"task,b.1,,4,4,,,,en,UTC\n",
+			want: []*importTodo{
+				{Ref: "0", Title: "a", Tags: []string{}},
+				{Ref: "1", ParentRef: "0", Title: "a.1", Tags: []string{}},
+				{Ref: "2", ParentRef: "1", Title: "a.1.1", Tags: []string{}},
+				{Ref: "3", ParentRef: "0", Title: "a.2", Tags: []string{}},
+				{Ref: "4", Title: "b", Tags: []string{}},
+				// Deeper than one level below the last task.
+				{Ref: "5", ParentRef: "4", Title: "b.1", Tags: []string{}},
+			},
+		},
+		{
+			name: "sections and notes are skipped",
+			input: header +
+				"section,Groceries,,,,,,,,\n" +
+				"task,Buy milk,Two bottles,4,1,,,,en,UTC\n" +
+				"note,Lactose free,,,,,,,,\n" +
+				"\n",
+			want:    []*importTodo{{Ref: "1", Title: "Buy milk", Description: "Two bottles", Tags: []string{}}},
+			skipped: 2,
+		},
+		{name: "empty", input: "", err: "todoist csv has no TYPE and CONTENT columns"},
+		{name: "no content column", input: "TYPE,NAME\ntask,x\n", err: "todoist csv has no TYPE and CONTENT columns"},
+		{name: "unclosed quote", input: header + "task,\"Buy milk\n", err: "invalid todoist csv"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lists, skipped, err := parseTodoist([]byte(test.input), "Inbox")
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTodoist failed: %v", err)
+			}
+			want := []*importList{{Title: "Inbox", Todos: test.want}}
+			if !reflect.DeepEqual(lists, want) {
+				t.Errorf("got\n%s\nwant\n%s", dumpImport(lists), dumpImport(want))
+			}
+			if skipped != test.skipped {
+				t.Errorf("skipped = %v, want %v", skipped, test.skipped)
+			}
+		})
+	}
+}
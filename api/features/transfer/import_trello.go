@@ -0,0 +1,110 @@
+package transfer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type trelloBoard struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string     `json:"id"`
+		Name        string     `json:"name"`
+		Desc        string     `json:"desc"`
+		IDList      string     `json:"idList"`
+		Closed      bool       `json:"closed"`
+		Due         *time.Time `json:"due"`
+		DueComplete bool       `json:"dueComplete"`
+		Labels      []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string            `json:"idCard"`
+		CheckItems []trelloCheckItem `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+type trelloCheckItem struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+// Reads the JSON export of a Trello board. Every Trello list becomes a list,
+// cards become todos and checklist items become their subtasks. Labels become
+// tags, with spaces replaced by dashes. Closed lists and cards are skipped.
+func parseTrello(body []byte) ([]*importList, int, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(body, &board); err != nil {
+		return nil, 0, fmt.Errorf("invalid trello export: %w", err)
+	}
+
+	skipped := 0
+	lists := []*importList{}
+	byID := make(map[string]*importList)
+	for _, trelloList := range board.Lists {
+		if trelloList.Closed {
+			skipped++
+			continue
+		}
+		list := &importList{Title: trelloList.Name}
+		byID[trelloList.ID] = list
+		lists = append(lists, list)
+	}
+
+	cardLists := make(map[string]*importList)
+	for _, card := range board.Cards {
+		list, ok := byID[card.IDList]
+		if card.Closed || !ok {
+			skipped++
+			continue
+		}
+		todo := &importTodo{
+			Ref:            card.ID,
+			Title:          card.Name,
+			Description:    card.Desc,
+			Completed:      card.DueComplete,
+			CompleteBefore: card.Due,
+			Tags:           []string{},
+		}
+		for _, label := range card.Labels {
+			tag := strings.Join(strings.Fields(label.Name), "-")
+			if tag != "" && !slices.Contains(todo.Tags, tag) {
+				todo.Tags = append(todo.Tags, tag)
+			}
+		}
+		list.Todos = append(list.Todos, todo)
+		cardLists[card.ID] = list
+	}
+
+	for _, checklist := range board.Checklists {
+		list, ok := cardLists[checklist.IDCard]
+		if !ok {
+			continue
+		}
+		items := slices.Clone(checklist.CheckItems)
+		slices.SortStableFunc(items, func(a, b trelloCheckItem) int {
+			return cmp.Compare(a.Pos, b.Pos)
+		})
+		for _, item := range items {
+			list.Todos = append(list.Todos, &importTodo{
+				Ref:       item.ID,
+				ParentRef: checklist.IDCard,
+				Title:     item.Name,
+				Completed: item.State == "complete",
+				Tags:      []string{},
+			})
+		}
+	}
+	return lists, skipped, nil
+}
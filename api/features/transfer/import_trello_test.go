@@ -0,0 +1,113 @@
+package transfer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTrello(t *testing.T) {
+	due := time.Date(2025, time.April, 2, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   string
+		want    []*importList
+		skipped int
+		err     string
+	}{
+		{
+			name:  "empty board",
+			input: `{}`,
+			want:  []*importList{},
+		},
+		{
+			name: "lists and cards",
+			input: `{
+				"lists": [{"id": "l1", "name": "To do"}, {"id": "l2", "name": "Done"}],
+				"cards": [
+					{"id": "c1", "name": "Write report", "desc": "Q1", "idList": "l1", "due": "2025-04-02T15:00:00Z"},
+					{"id": "c2", "name": "Send invoice", "idList": "l2", "dueComplete": true}
+				]
+			}`,
+			want: []*importList{
+				{Title: "To do", Todos: []*importTodo{
+					{Ref: "c1", Title: "Write report", Description: "Q1", CompleteBefore: &due, Tags: []string{}},
+				}},
+				{Title: "Done", Todos: []*importTodo{
+					{Ref: "c2", Title: "Send invoice", Completed: true, Tags: []string{}},
+				}},
+			},
+		},
+		{
+			name: "closed and orphaned are skipped",
+			input: `{
+				"lists": [{"id": "l1", "name": "Open"}, {"id": "l2", "name": "Old", "closed": true}],
+				"cards": [
+					{"id": "c1", "name": "Archived", "idList": "l1", "closed": true},
+					{"id": "c2", "name": "In closed list", "idList": "l2"},
+					{"id": "c3", "name": "Unknown list", "idList": "l9"}
+				]
+			}`,
+			want:    []*importList{{Title: "Open"}},
+			skipped: 4,
+		},
+		{
+			name: "labels become dashed tags",
+			input: `{
+				"lists": [{"id": "l1", "name": "Board"}],
+				"cards": [{"id": "c1", "name": "Card", "idList": "l1", "labels": [
+					{"name": "high  priority"}, {"name": ""}, {"name": "high priority"}, {"name": "bug"}
+				]}]
+			}`,
+			want: []*importList{{Title: "Board", Todos: []*importTodo{
+				{Ref: "c1", Title: "Card", Tags: []string{"high-priority", "bug"}},
+			}}},
+		},
+		{
+			name: "checklists become subtasks",
+			input: `{
+				"lists": [{"id": "l1", "name": "Board"}],
+				"cards": [
+					{"id": "c1", "name": "Move", "idList": "l1"},
+					{"id": "c2", "name": "Archived", "idList": "l1", "closed": true}
+				],
+				"checklists": [
+					{"idCard": "c1", "checkItems": [
+						{"id": "i2", "name": "Pack", "state": "incomplete", "pos": 2},
+						{"id": "i1", "name": "Rent van", "state": "complete", "pos": 1}
+					]},
+					{"idCard": "c2", "checkItems": [{"id": "i3", "name": "Lost", "pos": 1}]}
+				]
+			}`,
+			want: []*importList{{Title: "Board", Todos: []*importTodo{
+				{Ref: "c1", Title: "Move", Tags: []string{}},
+				{Ref: "i1", ParentRef: "c1", Title: "Rent van", Completed: true, Tags: []string{}},
+				{Ref: "i2", ParentRef: "c1", Title: "Pack", Tags: []string{}},
+			}}},
+			skipped: 1,
+		},
+		{name: "invalid json", input: `{"lists": [`, err: "invalid trello export"},
+		{name: "not a board", input: `[]`, err: "invalid trello export"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lists, skipped, err := parseTrello([]byte(test.input))
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTrello failed: %v", err)
+			}
+			if !reflect.DeepEqual(lists, test.want) {
+				t.Errorf("got\n%s\nwant\n%s", dumpImport(lists), dumpImport(test.want))
+			}
+			if skipped != test.skipped {
+				t.Errorf("skipped = %v, want %v", skipped, test.skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package transfer
+
+import (
+	"go-todo/middleware"
+	"go-todo/util/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferRoutes struct {
+	transferController *TransferController
+}
+
+func NewRoutes(transferController *TransferController) *TransferRoutes {
+	return &TransferRoutes{transferController}
+}
+
+func (routes *TransferRoutes) Register(rg *gin.RouterGroup) {
+	jwtAuth := middleware.JwtAuthMiddleware(routes.transferController.db)
+	rg.GET("/export", jwtAuth, middleware.RequireScopes(jwt.ScopeListsRead), routes.transferController.Export)
+	rg.POST(
+		"/import",
+		jwtAuth,
+		middleware.RequireScopes(jwt.ScopeListsWrite, jwt.ScopeTodosWrite),
+		routes.transferController.Import,
+	)
+}
@@ -3,13 +3,16 @@ package user
 import (
 	"context"
 	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserController struct {
-	db  *db.Queries
-	ctx context.Context
+	db   *db.Queries
+	pool *pgxpool.Pool
+	ctx  context.Context
 }
 
-func NewController(db *db.Queries, ctx context.Context) *UserController {
-	return &UserController{db: db, ctx: ctx}
+func NewController(db *db.Queries, pool *pgxpool.Pool, ctx context.Context) *UserController {
+	return &UserController{db: db, pool: pool, ctx: ctx}
 }
@@ -2,21 +2,32 @@ package user
 
 import (
 	"errors"
+	"fmt"
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 	"go-todo/logging"
 	"go-todo/schemas"
+	"go-todo/util/database"
 	"go-todo/util/mycontext"
 	"go-todo/util/passwd"
+	"go-todo/util/registration"
+	"go-todo/util/secret"
 	"go-todo/util/validate"
+	"log/slog"
 	"net/http"
 	"runtime"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Creates an account, as far as the registration mode allows. The first user
+// can always be created and becomes admin, so that a new instance can be set
+// up in every mode.
 func (controller *UserController) CreateUser(ctx *gin.Context) {
 	var payload *schemas.CreateUser
 	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
@@ -34,6 +45,21 @@ func (controller *UserController) CreateUser(ctx *gin.Context) {
 		makeAdmin = true
 	}
 
+	settings, err := registration.Get()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get registration settings", file, line, err, ctx)
+		return
+	}
+	useInvite := !makeAdmin && settings.Mode == registration.ModeInviteOnly
+	if !makeAdmin && settings.Mode == registration.ModeClosed {
+		ctx.Error(gterrors.ErrRegistrationClosed).SetType(gin.ErrorTypePublic)
+		return
+	} else if useInvite && payload.InviteCode == "" {
+		ctx.Error(gterrors.ErrInviteCodeInvalid).SetType(gin.ErrorTypePublic)
+		return
+	}
+
 	isPasswdValid, err := validate.Password(payload.Password)
 	if err != nil {
 		_, file, line, _ := runtime.Caller(0)
@@ -54,6 +80,18 @@ func (controller *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
+	if payload.Email != "" && !validate.Email(payload.Email) {
+		ctx.Error(gterrors.NewGtValueError(payload.Email, "invalid email address"))
+		return
+	}
+	// The first user is not held up by verification, mail may not be set up
+	// yet.
+	verifyEmail := !makeAdmin && settings.RequireEmailVerification
+	if verifyEmail && payload.Email == "" {
+		ctx.Error(gterrors.NewGtValueError(payload.Email, "email address required"))
+		return
+	}
+
 	userUUID := uuid.New()
 	password := payload.Password
 	passwdHash, err := passwd.Hash(password)
@@ -63,15 +101,54 @@ func (controller *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
+	emailVerifiedAt := pgtype.Timestamptz{}
+	if makeAdmin && payload.Email != "" {
+		emailVerifiedAt = pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}
+	}
 	args := &db.CreateUserParams{
-		ID:           userUUID.String(),
-		Username:     payload.Username,
-		PasswordHash: passwdHash,
-		IsAdmin:      makeAdmin,
+		ID:              userUUID.String(),
+		Username:        payload.Username,
+		PasswordHash:    passwdHash,
+		IsAdmin:         makeAdmin,
+		Email:           pgtype.Text{String: payload.Email, Valid: payload.Email != ""},
+		EmailVerifiedAt: emailVerifiedAt,
 	}
 
-	user, err := controller.db.CreateUser(ctx, *args)
-	if err != nil {
+	var user db.CreateUserRow
+	var inviteID string
+	var verificationToken string
+	// The invite is only used up if the user is created.
+	err = database.WithTx(ctx, controller.pool, controller.db, func(qtx *db.Queries) error {
+		var err error
+		if useInvite {
+			inviteID, err = qtx.UseInviteCode(ctx, secret.Hash(payload.InviteCode))
+			if errors.Is(err, pgx.ErrNoRows) {
+				return gterrors.ErrInviteCodeInvalid
+			} else if err != nil {
+				return fmt.Errorf("failed to use invite code: %w", err)
+			}
+		}
+		if user, err = qtx.CreateUser(ctx, *args); err != nil {
+			return err
+		}
+		if verifyEmail {
+			if verificationToken, err = registration.CreateVerificationToken(ctx, qtx, user.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, gterrors.ErrInviteCodeInvalid) {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreMedium,
+			logging.SecurityEventInviteCodeInvalid,
+			ctx.FullPath(),
+			payload.Username,
+			ctx.ClientIP(),
+		)
+		ctx.Error(gterrors.ErrInviteCodeInvalid).SetType(gin.ErrorTypePublic)
+		return
+	} else if err != nil {
 		var pgErr *pgconn.PgError
 		errMessage := "failed to create user"
 		if errors.As(err, &pgErr) {
@@ -98,5 +175,22 @@ func (controller *UserController) CreateUser(ctx *gin.Context) {
 		nil,
 		logging.ObjectEventSubUser,
 	)
-	ctx.JSON(http.StatusCreated, gin.H{"status": "created", "user": user})
+	if inviteID != "" {
+		logging.LogAuditEvent(
+			true,
+			ctx.FullPath(),
+			ctx.ClientIP(),
+			"registration:invite",
+			slog.String("invite_id", inviteID),
+			slog.String("user_id", user.ID),
+		)
+	}
+	if verifyEmail {
+		go registration.SendVerificationEmail(payload.Email, user.Username, verificationToken)
+	}
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":                      "created",
+		"user":                        user,
+		"email_verification_required": verifyEmail,
+	})
 }
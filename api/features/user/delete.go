@@ -1,14 +1,18 @@
 package user
 
 import (
+	"errors"
 	"fmt"
 	"go-todo/gterrors"
 	"go-todo/logging"
+	"go-todo/util/authz"
 	"go-todo/util/mycontext"
+	"go-todo/util/revocation"
 	"net/http"
 	"runtime"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 // Controller for deleting users
@@ -39,17 +43,33 @@ func (controller *UserController) DeleteUser(ctx *gin.Context) {
 	}
 
 	userIDToDelete := ctx.Param("id")
-	if userIDToDelete != reqUser.ID && !reqUser.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreMedium,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			userIDToDelete,
-			reqUser.Username,
-		)
-		ctx.Error(gterrors.ErrForbidden).SetType(gterrors.GetGinErrorType())
+	if userIDToDelete != reqUser.ID && !authz.Authorize(
+		controller.db,
+		&reqUser,
+		authz.PermissionUsersManage,
+		logging.SecurityScoreMedium,
+		userIDToDelete,
+		ctx,
+	) {
 		return
 	}
+	if userIDToDelete != reqUser.ID {
+		userToDelete, err := controller.db.GetUserById(ctx, userIDToDelete)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("could not get user from db", file, line, err, ctx)
+			return
+		}
+		if err == nil && !authz.AuthorizeManage(
+			controller.db,
+			&reqUser,
+			&userToDelete,
+			logging.SecurityScoreHigh,
+			ctx,
+		) {
+			return
+		}
+	}
 
 	rows, err := controller.db.DeleteUser(ctx, userIDToDelete)
 	if err != nil {
@@ -61,6 +81,14 @@ func (controller *UserController) DeleteUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"status": "user-not-removed"})
 		return
 	}
+	if _, err := revocation.RevokeUser(ctx, controller.db, userIDToDelete); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to revoke access jwts: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+	}
 
 	logging.LogObjectEvent(
 		ctx.FullPath(),
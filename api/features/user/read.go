@@ -8,6 +8,7 @@ import (
 	"go-todo/gterrors"
 	"go-todo/logging"
 	"go-todo/schemas"
+	"go-todo/util/authz"
 	"go-todo/util/database"
 	"go-todo/util/mycontext"
 
@@ -37,14 +38,14 @@ func (controller *UserController) ReadUser(ctx *gin.Context) {
 		return
 	}
 
-	if reqUser.ID != userIDToGet && !reqUser.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreMedium,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			fmt.Sprintf("userID: %v", userIDToGet),
-			reqUser.ID,
-		)
+	if reqUser.ID != userIDToGet && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionUsersRead,
+		logging.SecurityScoreMedium,
+		fmt.Sprintf("userID: %v", userIDToGet),
+		ctx,
+	) {
 		return
 	}
 
@@ -64,12 +65,28 @@ func (controller *UserController) ReadUser(ctx *gin.Context) {
 		return
 	}
 
+	userRoles, err := controller.db.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get roles of user", file, line, err, ctx)
+		return
+	}
+	roles := make([]string, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		roles = append(roles, userRole.Role)
+	}
+
 	responseUser := &schemas.ResponseUser{
 		Id:        user.ID,
 		Username:  user.Username,
 		IsAdmin:   user.IsAdmin,
+		Email:     user.Email.String,
+		Roles:     roles,
 		CreatedAt: user.CreatedAt.Time,
 	}
+	if user.Email.Valid {
+		responseUser.EmailVerified = &user.EmailVerifiedAt.Valid
+	}
 
 	logging.LogObjectEvent(
 		ctx.FullPath(),
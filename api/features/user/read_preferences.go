@@ -0,0 +1,58 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Returns the preferences of the user, or the defaults if none were set.
+func (controller *UserController) ReadPreferences(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	userIDToGet := ctx.Param("userID")
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	if reqUser.ID != userIDToGet && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionUsersRead,
+		logging.SecurityScoreMedium,
+		fmt.Sprintf("userID: %v", userIDToGet),
+		ctx,
+	) {
+		return
+	}
+
+	preferences, err := database.GetUserPreferences(ctx, controller.db, userIDToGet)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get preferences", file, line, err, ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "preferences": preferences})
+}
@@ -0,0 +1,100 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/timesheet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Returns the time tracked by the user in the range given with 'from' and 'to'
+// query params. With '?format=csv' every entry is returned as a CSV row.
+func (controller *UserController) ReadTimeReport(ctx *gin.Context) {
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	userIDToGet := ctx.Param("userID")
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		ctx.Error(gterrors.NewGtValueError(format, "format must be json or csv"))
+		return
+	}
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+	loc, err := database.GetUserLocation(ctx, controller.db, reqUser.ID)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get user time zone", file, line, err, ctx)
+		return
+	}
+	from, to, ok := mycontext.GetTimeRangeQuery(ctx, loc)
+	if !ok {
+		return
+	}
+
+	if reqUser.ID != userIDToGet && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionUsersRead,
+		logging.SecurityScoreMedium,
+		fmt.Sprintf("userID: %v", userIDToGet),
+		ctx,
+	) {
+		return
+	}
+
+	args := &db.GetTimeEntriesInRangeParams{
+		RangeStart: pgtype.Timestamptz{Time: from, Valid: true},
+		RangeEnd:   pgtype.Timestamptz{Time: to, Valid: true},
+		UserID:     pgtype.Text{String: userIDToGet, Valid: true},
+	}
+	rows, err := controller.db.GetTimeEntriesInRange(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get time entries", file, line, err, ctx)
+		return
+	}
+	entries := timesheet.FromRows(rows)
+
+	if format == "csv" {
+		ctx.Header(
+			"Content-Disposition",
+			fmt.Sprintf("attachment; filename=\"time-%v.csv\"", userIDToGet),
+		)
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		if err := timesheet.WriteCSV(ctx.Writer, entries); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "failed to write csv")
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"report": timesheet.Summarize(from, to, entries),
+	})
+}
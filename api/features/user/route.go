@@ -2,6 +2,7 @@ package user
 
 import (
 	"go-todo/middleware"
+	"go-todo/util/jwt"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,9 +16,15 @@ func NewRoutes(userController *UserController) *UserRoutes {
 }
 
 func (routes *UserRoutes) Register(rg *gin.RouterGroup) {
+	jwtAuth := middleware.JwtAuthMiddleware(routes.userController.db)
+	readLists := middleware.RequireScopes(jwt.ScopeListsRead)
+	usersAdmin := middleware.RequireScopes(jwt.ScopeUsersAdmin)
 	router := rg.Group("/user")
-	router.GET("/:userID", middleware.JwtAuthMiddleware(), routes.userController.ReadUser)
+	router.GET("/:userID", jwtAuth, readLists, routes.userController.ReadUser)
+	router.GET("/:userID/time", jwtAuth, readLists, routes.userController.ReadTimeReport)
+	router.GET("/:userID/preferences", jwtAuth, readLists, routes.userController.ReadPreferences)
+	router.PATCH("/:id/preferences", jwtAuth, usersAdmin, routes.userController.UpdatePreferences)
 	router.POST("/", routes.userController.CreateUser)
-	router.PATCH("/:id", middleware.JwtAuthMiddleware(), routes.userController.UpdateUser)
-	router.DELETE("/:id", middleware.JwtAuthMiddleware(), routes.userController.DeleteUser)
+	router.PATCH("/:id", jwtAuth, usersAdmin, routes.userController.UpdateUser)
+	router.DELETE("/:id", jwtAuth, usersAdmin, routes.userController.DeleteUser)
 }
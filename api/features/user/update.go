@@ -5,17 +5,24 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"time"
 
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 	"go-todo/logging"
 	"go-todo/schemas"
+	"go-todo/util/authz"
+	"go-todo/util/mail"
 	"go-todo/util/mycontext"
+	"go-todo/util/passwd"
+	"go-todo/util/registration"
+	"go-todo/util/revocation"
 	"go-todo/util/validate"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (controller *UserController) UpdateUser(ctx *gin.Context) {
@@ -39,15 +46,14 @@ func (controller *UserController) UpdateUser(ctx *gin.Context) {
 
 	userIDToUpdate := ctx.Param("id")
 
-	if userIDToUpdate != reqUser.ID && !reqUser.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreHigh,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			userIDToUpdate,
-			reqUser.Username,
-		)
-		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+	if userIDToUpdate != reqUser.ID && !authz.Authorize(
+		controller.db,
+		&reqUser,
+		authz.PermissionUsersManage,
+		logging.SecurityScoreHigh,
+		userIDToUpdate,
+		ctx,
+	) {
 		return
 	}
 	var payload *schemas.UpdateUser
@@ -64,15 +70,8 @@ func (controller *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	if !reqUser.IsAdmin && *payload.IsAdmin {
-		logging.LogSecurityEvent(
-			logging.SecurityScoreHigh,
-			logging.SecurityEventForbiddenAction,
-			ctx.FullPath(),
-			userIDToUpdate,
-			reqUser.Username,
-		)
-		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+	if payload.Email != nil && *payload.Email != "" && !validate.Email(*payload.Email) {
+		ctx.Error(gterrors.NewGtValueError(*payload.Email, "invalid email address"))
 		return
 	}
 
@@ -92,7 +91,37 @@ func (controller *UserController) UpdateUser(ctx *gin.Context) {
 	} else {
 		oldUser = &reqUser
 	}
-	if oldUser.Username == payload.Username && oldUser.IsAdmin == *payload.IsAdmin {
+	// Admins have every permission, so only users who may make admins can
+	// change is_admin.
+	if oldUser.IsAdmin != *payload.IsAdmin && !authz.Authorize(
+		controller.db,
+		&reqUser,
+		authz.PermissionRolesManage,
+		logging.SecurityScoreHigh,
+		userIDToUpdate,
+		ctx,
+	) {
+		return
+	}
+	if oldUser.ID != reqUser.ID && !authz.AuthorizeManage(
+		controller.db,
+		&reqUser,
+		oldUser,
+		logging.SecurityScoreHigh,
+		ctx,
+	) {
+		return
+	}
+	email := oldUser.Email
+	if payload.Email != nil {
+		email = pgtype.Text{String: *payload.Email, Valid: *payload.Email != ""}
+	}
+	// Otherwise a stolen session could take the account over for good, by
+	// changing the email address and resetting the password.
+	if oldUser.ID == reqUser.ID && email != oldUser.Email && !controller.reauthenticated(&reqUser, payload.Password, ctx) {
+		return
+	}
+	if oldUser.Username == payload.Username && oldUser.IsAdmin == *payload.IsAdmin && oldUser.Email == email {
 		logging.LogObjectEvent(
 			ctx.FullPath(),
 			ctx.ClientIP(),
@@ -110,6 +139,7 @@ func (controller *UserController) UpdateUser(ctx *gin.Context) {
 		ID:       userIDToUpdate,
 		Username: payload.Username,
 		IsAdmin:  *payload.IsAdmin,
+		Email:    email,
 	}
 
 	updatedUser, err := controller.db.UpdateUser(ctx, *args)
@@ -132,7 +162,24 @@ func (controller *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	if email.Valid && email != oldUser.Email {
+		controller.sendVerification(updatedUser.ID, updatedUser.Username, email.String, ctx)
+	}
+	if oldUser.Email.Valid && email != oldUser.Email {
+		go notifyEmailChange(oldUser.Email.String, updatedUser.Username)
+	}
+
 	if oldUser.IsAdmin != updatedUser.IsAdmin {
+		// Access jwts carry is_admin, so the ones issued before the change
+		// are revoked.
+		if _, err := revocation.RevokeUser(ctx, controller.db, updatedUser.ID); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			logging.LogError(
+				fmt.Errorf("failed to revoke access jwts: %w", err),
+				fmt.Sprintf("%v: %d", file, line),
+				err.Error(),
+			)
+		}
 		if err := controller.db.DeleteJwtTokensByUserId(ctx, updatedUser.ID); err != nil {
 			var pgErr *pgconn.PgError
 			errMessage := "failed to delete old jwts"
@@ -163,3 +210,76 @@ func (controller *UserController) UpdateUser(ctx *gin.Context) {
 		"user":   updatedUser,
 	})
 }
+
+// Sends a link to verify the changed email address, if verification is
+// required. Errors are only logged, the user is updated either way and can
+// ask for a new link.
+func (controller *UserController) sendVerification(userID string, username string, email string, ctx *gin.Context) {
+	logError := func(err error) {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "failed to send verification email")
+	}
+	settings, err := registration.Get()
+	if err != nil {
+		logError(err)
+		return
+	} else if !settings.RequireEmailVerification {
+		return
+	}
+	token, err := registration.CreateVerificationToken(ctx, controller.db, userID)
+	if err != nil {
+		logError(err)
+		return
+	}
+	go registration.SendVerificationEmail(email, username, token)
+}
+
+// How long after logging in a session can change the email address without
+// the password.
+const recentLoginWindow = 10 * time.Minute
+
+// Checks that the requester gave their current password, or that the session
+// of the request logged in within recentLoginWindow. Pushes the error to
+// gin.Context if not.
+func (controller *UserController) reauthenticated(reqUser *db.User, password *string, ctx *gin.Context) bool {
+	if password != nil {
+		if passwd.Compare(*password, reqUser.PasswordHash) {
+			return true
+		}
+		ctx.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonInvalidCredentials,
+				errors.New("provided credentials are incorrect"),
+			),
+		).SetType(gin.ErrorTypePublic)
+		return false
+	}
+
+	if family := mycontext.GetTokenSession(ctx); family != "" {
+		session, err := controller.db.GetSessionByFamily(ctx, family)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to get session", file, line, err, ctx)
+			return false
+		}
+		if err == nil && time.Since(session.CreatedAt.Time) < recentLoginWindow {
+			return true
+		}
+	}
+	ctx.Error(gterrors.NewGtValueError("password", "current password or a recent login is required to change the email address"))
+	return false
+}
+
+// Tells the old email address of the user that it was replaced, so that they
+// notice changes they did not make. Errors are logged, since it is sent after
+// responding.
+func notifyEmailChange(to string, username string) {
+	body := fmt.Sprintf(
+		"Hi %v,\n\nthe email address of your account was changed, so this address no longer receives its emails.\n\nIf it was not you, change your password and contact an admin.\n",
+		username,
+	)
+	if err := mail.Send(to, "Your email address was changed", body); err != nil && !errors.Is(err, mail.ErrDisabled) {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "failed to send email change notification")
+	}
+}
@@ -0,0 +1,135 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/schemas"
+	"go-todo/util/authz"
+	"go-todo/util/database"
+	"go-todo/util/mycontext"
+	"go-todo/util/validate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+func (controller *UserController) UpdatePreferences(ctx *gin.Context) {
+	var payload *schemas.UpdatePreferences
+	if ok := mycontext.ShouldBindBodyWithJSON(&payload, ctx); !ok {
+		return
+	} else if payload.TimeZone == nil &&
+		payload.WeekStart == nil &&
+		payload.Locale == nil &&
+		payload.DateFormat == nil {
+		ctx.JSON(http.StatusOK, gin.H{"status": "not-modified"})
+		return
+	}
+
+	requesterId, requesterUsername, _, err := mycontext.GetTokenVariables(ctx)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get claims from jwt", file, line, err, ctx)
+		return
+	}
+
+	userIDToUpdate := ctx.Param("id")
+
+	reqUser, err := database.GetUserById(controller.db, requesterId, ctx)
+	if err != nil {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventJwtUserUnknown,
+			ctx.FullPath(),
+			requesterUsername,
+			ctx.ClientIP(),
+		)
+		return
+	}
+
+	if reqUser.ID != userIDToUpdate && !authz.Authorize(
+		controller.db,
+		reqUser,
+		authz.PermissionUsersManage,
+		logging.SecurityScoreMedium,
+		fmt.Sprintf("userID: %v", userIDToUpdate),
+		ctx,
+	) {
+		return
+	}
+
+	if payload.TimeZone != nil && !validate.TimeZone(*payload.TimeZone) {
+		ctx.Error(gterrors.NewGtValueError(*payload.TimeZone, "unknown time zone"))
+		return
+	}
+	if payload.Locale != nil && !validate.Locale(*payload.Locale) {
+		ctx.Error(gterrors.NewGtValueError(*payload.Locale, "locale must be a language tag like en-US"))
+		return
+	}
+	if payload.DateFormat != nil && !validate.DateFormat(*payload.DateFormat) {
+		ctx.Error(gterrors.NewGtValueError(
+			*payload.DateFormat,
+			fmt.Sprintf("date_format must be one of %v", validate.DateFormats),
+		))
+		return
+	}
+
+	if _, err := controller.db.GetUserById(ctx, userIDToUpdate); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.Error(gterrors.ErrNotFound).SetType(gin.ErrorTypePublic)
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("could not get user from db", file, line, err, ctx)
+		return
+	}
+	oldPreferences, err := database.GetUserPreferences(ctx, controller.db, userIDToUpdate)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to get preferences", file, line, err, ctx)
+		return
+	}
+
+	args := &db.UpsertUserPreferencesParams{
+		UserID:     userIDToUpdate,
+		TimeZone:   oldPreferences.TimeZone,
+		WeekStart:  oldPreferences.WeekStart,
+		Locale:     oldPreferences.Locale,
+		DateFormat: oldPreferences.DateFormat,
+	}
+	if payload.TimeZone != nil {
+		args.TimeZone = *payload.TimeZone
+	}
+	if payload.WeekStart != nil {
+		args.WeekStart = *payload.WeekStart
+	}
+	if payload.Locale != nil {
+		args.Locale = *payload.Locale
+	}
+	if payload.DateFormat != nil {
+		args.DateFormat = *payload.DateFormat
+	}
+
+	preferences, err := controller.db.UpsertUserPreferences(ctx, *args)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to update preferences", file, line, err, ctx)
+		return
+	}
+
+	logging.LogObjectEvent(
+		ctx.FullPath(),
+		ctx.ClientIP(),
+		logging.ObjectEventUpdate,
+		reqUser,
+		&preferences,
+		oldPreferences,
+		logging.ObjectEventSubPreferences,
+	)
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "preferences": preferences})
+}
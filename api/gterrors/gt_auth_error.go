@@ -18,6 +18,9 @@ const (
 	GtAuthErrorReasonJwtUserNotFound
 	GtAuthErrorReasonTokenReuse
 	GtAuthErrorReasonUsernameInvalid
+	GtAuthErrorReasonInsufficientScope
+	GtAuthErrorReasonTokenRevoked
+	GtAuthErrorReasonTwoFactorInvalid
 )
 
 func (t GtAuthErrorReason) String() string {
@@ -38,6 +41,12 @@ func (t GtAuthErrorReason) String() string {
 		return "jwt-token-reuse"
 	case GtAuthErrorReasonUsernameInvalid:
 		return "username-invalid"
+	case GtAuthErrorReasonInsufficientScope:
+		return "insufficient-scope"
+	case GtAuthErrorReasonTokenRevoked:
+		return "token-revoked"
+	case GtAuthErrorReasonTwoFactorInvalid:
+		return "two-factor-invalid"
 	}
 	return "unknown"
 }
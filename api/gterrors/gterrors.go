@@ -7,12 +7,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+var ErrEmailNotVerified = errors.New("email address not verified")
 var ErrForbidden = errors.New("forbidden")
+var ErrInviteCodeInvalid = errors.New("invite code unknown, expired or used up")
+var ErrIpBlocked = errors.New("requests from this ip are blocked")
+var ErrListArchived = errors.New("list is archived")
 var ErrJwtRefreshReuse = errors.New("refresh jwt reuse")
+var ErrLoginLocked = errors.New("too many failed logins, try again later")
 var ErrNotFound = errors.New("resource not found")
 var ErrPasswordUnsatisfied = errors.New("password criteria not met")
+var ErrPreconditionFailed = errors.New("precondition failed")
+var ErrRegistrationClosed = errors.New("registration is closed")
 var ErrPasswordSame = errors.New("password cannot be the old one")
 var ErrShouldNotHappen = errors.New("this should not happen")
+var ErrTimerRunning = errors.New("timer already running")
+var ErrTwoFactorEnabled = errors.New("two-factor authentication already enabled")
 var ErrUniqueViolation = errors.New("already exists")
 var ErrUsernameUnsatisfied = errors.New("username criteria not met")
 
@@ -1,6 +1,36 @@
 package logging
 
-import "log/slog"
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// An audit or security event as it is passed to the hook.
+type AuditEntry struct {
+	Time       time.Time
+	Sourcetype string
+	EventType  string
+	// The id of the user the event is by, the username for session events or
+	// the violator for security events. Empty if there is none.
+	Actor      string
+	SrcIp      string
+	TargetPath string
+	// The attributes of the log line as a JSON object.
+	Data json.RawMessage
+}
+
+var auditEntryHook atomic.Pointer[func(entry AuditEntry)]
+
+// Sets a function that is called with every audit, session, token, object and
+// security event after it has been logged. It is called on the request, so it
+// should hand the entry off rather than do slow work.
+func SetAuditEntryHook(hook func(entry AuditEntry)) {
+	auditEntryHook.Store(&hook)
+}
 
 func LogAuditEvent(
 	success bool,
@@ -9,21 +39,78 @@ func LogAuditEvent(
 	eventType string,
 	args ...any,
 ) {
+	logAuditEvent(success, targetPath, srcIp, eventType, "", args...)
+}
+
+// Like LogAuditEvent, with the actor for the audit log entry.
+func logAuditEvent(
+	success bool,
+	targetPath string,
+	srcIp string,
+	eventType string,
+	actor string,
+	args ...any,
+) {
+	event := slog.Group(
+		"event",
+		append(
+			[]any{
+				slog.String("eventtype", eventType),
+				slog.Bool("success", success),
+				slog.String("src_ip", srcIp),
+				slog.String("target_path", targetPath),
+			},
+			args...,
+		)...,
+	)
 	log(
 		slog.LevelInfo,
 		"Event audited",
 		"audit",
-		slog.Group(
-			"event",
-			append(
-				[]any{
-					slog.String("eventtype", eventType),
-					slog.Bool("success", success),
-					slog.String("src_ip", srcIp),
-					slog.String("target_path", targetPath),
-				},
-				args...,
-			)...,
-		),
+		event,
 	)
+	callAuditEntryHook("audit", eventType, actor, srcIp, targetPath, event)
+}
+
+func callAuditEntryHook(
+	sourcetype string,
+	eventType string,
+	actor string,
+	srcIp string,
+	targetPath string,
+	attrs ...slog.Attr,
+) {
+	hook := auditEntryHook.Load()
+	if hook == nil {
+		return
+	}
+	(*hook)(AuditEntry{
+		Time:       time.Now(),
+		Sourcetype: sourcetype,
+		EventType:  eventType,
+		Actor:      actor,
+		SrcIp:      srcIp,
+		TargetPath: targetPath,
+		Data:       attrsJSON(attrs...),
+	})
+}
+
+// Renders the attributes the way the JSON log handler does, without the time,
+// level and message.
+func attrsJSON(attrs ...slog.Attr) json.RawMessage {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey || attr.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "", 0)
+	record.AddAttrs(attrs...)
+	if err := handler.Handle(context.Background(), record); err != nil {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(bytes.TrimSpace(buf.Bytes()))
 }
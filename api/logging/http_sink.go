@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	httpSinkBatchSize = 100
+	httpSinkTimeout   = 10 * time.Second
+)
+
+// Posts records to a collector, one per line.
+type httpWriter struct {
+	url         string
+	contentType string
+	client      *http.Client
+	queue       *sinkQueue
+}
+
+func newHttpWriter(sink *Sink) *httpWriter {
+	writer := &httpWriter{
+		url:         sink.Url,
+		contentType: "application/x-ndjson",
+		client:      &http.Client{Timeout: httpSinkTimeout},
+	}
+	if sink.Format == FormatLogfmt {
+		writer.contentType = "text/plain; charset=utf-8"
+	}
+	writer.queue = newSinkQueue("http "+sink.Url, httpSinkBatchSize, writer.send)
+	return writer
+}
+
+// Called by the formatting handler with one record, ending in a line break.
+func (w *httpWriter) Write(record []byte) (int, error) {
+	// The handler reuses its buffer once Write returns.
+	w.queue.push(bytes.Clone(record))
+	return len(record), nil
+}
+
+func (w *httpWriter) send(records [][]byte) error {
+	response, err := w.client.Post(w.url, w.contentType, bytes.NewReader(bytes.Join(records, nil)))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with %v", response.Status)
+	}
+	return nil
+}
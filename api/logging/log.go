@@ -11,7 +11,7 @@ func log(level slog.Leveler, message string, sourcetype string, args ...slog.Att
 		level.Level(),
 		message,
 		append(
-			[]slog.Attr{slog.String("sourcetype", "go-todo:"+sourcetype)},
+			[]slog.Attr{slog.String("sourcetype", sourcetypePrefix+sourcetype)},
 			args...,
 		)...,
 	)
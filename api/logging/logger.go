@@ -1,30 +1,33 @@
 package logging
 
 import (
-	"io"
 	"log/slog"
 
-	"github.com/DeRuina/timberjack"
+	"go-todo/util/config"
 )
 
-func GetLogger() *slog.Logger {
-	logRotator := &timberjack.Logger{
-		Filename:   "./app.log",
-		MaxSize:    10,
-		MaxBackups: 5,
-		MaxAge:     100,
-		Compress:   true,
+// Returns the logger that sends records to the sinks in LOG_SINKS by their
+// sourcetype, and sets the redaction rules from LOG_REDACTION.
+func GetLogger() (*slog.Logger, error) {
+	config, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := ParseSinks(config.LogSinks)
+	if err != nil {
+		return nil, err
+	}
+	redactionRules, err := ParseRedactionRules(config.LogRedaction)
+	if err != nil {
+		return nil, err
+	}
+	SetRedaction(redactionRules, config.LogRedactionKey)
+	handler, err := newRoutingHandler(sinks)
+	if err != nil {
+		return nil, err
 	}
 
-	appMultiWriter := slog.NewJSONHandler(
-		io.MultiWriter(logRotator),
-		&slog.HandlerOptions{
-			AddSource: true,
-			Level:     slog.LevelInfo,
-		},
-	)
-
-	appLogger := slog.New(appMultiWriter).With(slog.String("program_name", "GO-TODO"))
+	appLogger := slog.New(handler).With(slog.String("program_name", "GO-TODO"))
 
-	return appLogger
+	return appLogger, nil
 }
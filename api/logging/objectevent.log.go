@@ -12,6 +12,16 @@ const (
 	ObjectEventSubList ObjectEventSub = iota
 	ObjectEventSubTodo
 	ObjectEventSubUser
+	ObjectEventSubTimeEntry
+	ObjectEventSubTemplate
+	ObjectEventSubFolder
+	ObjectEventSubPreferences
+	ObjectEventSubCalendarFeed
+	ObjectEventSubAppPassword
+	ObjectEventSubPersonalAccessToken
+	ObjectEventSubTotp
+	ObjectEventSubUserRole
+	ObjectEventSubInviteCode
 )
 
 func (e ObjectEventSub) String() string {
@@ -22,6 +32,26 @@ func (e ObjectEventSub) String() string {
 		return "todo"
 	case ObjectEventSubUser:
 		return "user"
+	case ObjectEventSubTimeEntry:
+		return "time_entry"
+	case ObjectEventSubTemplate:
+		return "template"
+	case ObjectEventSubFolder:
+		return "folder"
+	case ObjectEventSubPreferences:
+		return "preferences"
+	case ObjectEventSubCalendarFeed:
+		return "calendar_feed"
+	case ObjectEventSubAppPassword:
+		return "app_password"
+	case ObjectEventSubPersonalAccessToken:
+		return "personal_access_token"
+	case ObjectEventSubTotp:
+		return "totp"
+	case ObjectEventSubUserRole:
+		return "user_role"
+	case ObjectEventSubInviteCode:
+		return "invite_code"
 	}
 	return "unknown"
 }
@@ -121,6 +151,158 @@ func LogObjectEvent(
 				)
 				groupOld = &gOld
 			}
+		case *db.Folder:
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.String("title", sc.Title),
+				slog.String("parent_id", sc.ParentID.String),
+			)
+			groupCurrent = &gCur
+			if subOld != nil {
+				so := subOld.(*db.Folder)
+				gOld := slog.Group(
+					oldKey,
+					slog.String("id", so.ID),
+					slog.String("title", so.Title),
+					slog.String("parent_id", so.ParentID.String),
+				)
+				groupOld = &gOld
+			}
+		case *db.ListTemplate:
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.String("title", sc.Title),
+				slog.String("description", sc.Description.String),
+				slog.Bool("is_shared", sc.IsShared),
+			)
+			groupCurrent = &gCur
+			if subOld != nil {
+				so := subOld.(*db.ListTemplate)
+				gOld := slog.Group(
+					oldKey,
+					slog.String("id", so.ID),
+					slog.String("title", so.Title),
+					slog.String("description", so.Description.String),
+					slog.Bool("is_shared", so.IsShared),
+				)
+				groupOld = &gOld
+			}
+		case *db.TimeEntry:
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.String("todo_id", sc.TodoID),
+				slog.String("user_id", sc.UserID),
+				slog.Time("started_at", sc.StartedAt.Time),
+				slog.Time("stopped_at", sc.StoppedAt.Time),
+			)
+			groupCurrent = &gCur
+			if subOld != nil {
+				so := subOld.(*db.TimeEntry)
+				gOld := slog.Group(
+					oldKey,
+					slog.String("id", so.ID),
+					slog.String("todo_id", so.TodoID),
+					slog.String("user_id", so.UserID),
+					slog.Time("started_at", so.StartedAt.Time),
+					slog.Time("stopped_at", so.StoppedAt.Time),
+				)
+				groupOld = &gOld
+			}
+		case *db.UserPreference:
+			gCur := slog.Group(
+				curKey,
+				slog.String("user_id", sc.UserID),
+				slog.String("time_zone", sc.TimeZone),
+				slog.Int("week_start", int(sc.WeekStart)),
+				slog.String("locale", sc.Locale),
+				slog.String("date_format", sc.DateFormat),
+			)
+			groupCurrent = &gCur
+			if subOld != nil {
+				so := subOld.(*db.UserPreference)
+				gOld := slog.Group(
+					oldKey,
+					slog.String("user_id", so.UserID),
+					slog.String("time_zone", so.TimeZone),
+					slog.Int("week_start", int(so.WeekStart)),
+					slog.String("locale", so.Locale),
+					slog.String("date_format", so.DateFormat),
+				)
+				groupOld = &gOld
+			}
+		case *db.CalendarFeed:
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.String("user_id", sc.UserID),
+				slog.String("list_id", sc.ListID.String),
+				slog.Bool("include_events", sc.IncludeEvents),
+			)
+			groupCurrent = &gCur
+			if subOld != nil {
+				so := subOld.(*db.CalendarFeed)
+				gOld := slog.Group(
+					oldKey,
+					slog.String("id", so.ID),
+					slog.String("user_id", so.UserID),
+					slog.String("list_id", so.ListID.String),
+					slog.Bool("include_events", so.IncludeEvents),
+				)
+				groupOld = &gOld
+			}
+		case *db.AppPassword:
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.String("user_id", sc.UserID),
+				slog.String("name", sc.Name),
+			)
+			groupCurrent = &gCur
+		case *db.PersonalAccessToken:
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.String("user_id", sc.UserID),
+				slog.String("name", sc.Name),
+				slog.Time("expires_at", sc.ExpiresAt.Time),
+			)
+			groupCurrent = &gCur
+		case *db.UserTotp:
+			// The secret is left out.
+			gCur := slog.Group(
+				curKey,
+				slog.String("user_id", sc.UserID),
+				slog.Bool("enabled", sc.Enabled),
+			)
+			groupCurrent = &gCur
+			if subOld != nil {
+				so := subOld.(*db.UserTotp)
+				gOld := slog.Group(
+					oldKey,
+					slog.String("user_id", so.UserID),
+					slog.Bool("enabled", so.Enabled),
+				)
+				groupOld = &gOld
+			}
+		case *db.UserRole:
+			gCur := slog.Group(
+				curKey,
+				slog.String("user_id", sc.UserID),
+				slog.String("role", sc.Role),
+			)
+			groupCurrent = &gCur
+		case *db.InviteCode:
+			// The hash of the code is left out.
+			gCur := slog.Group(
+				curKey,
+				slog.String("id", sc.ID),
+				slog.Int("max_uses", int(sc.MaxUses)),
+				slog.Time("expires_at", sc.ExpiresAt.Time),
+			)
+			groupCurrent = &gCur
 		case []db.List:
 			ids := ""
 			for i, list := range sc {
@@ -158,6 +340,7 @@ func LogObjectEvent(
 			groupCurrent = &group
 		}
 
+		*groupCurrent = redactSubject(subjectType.String(), eventType.String(), *groupCurrent)
 		if groupOld == nil {
 			return slog.Group(
 				"subject",
@@ -168,6 +351,7 @@ func LogObjectEvent(
 				),
 			)
 		} else {
+			*groupOld = redactSubject(subjectType.String(), eventType.String(), *groupOld)
 			return slog.Group(
 				"subject",
 				slog.String("objecttype", subjectType.String()),
@@ -184,16 +368,19 @@ func LogObjectEvent(
 	}
 
 	var actorData slog.Attr
+	actorId := ""
 	if actor != nil {
 		actorData = getActorData(actor.ID, actor.Username, actor.IsAdmin)
+		actorId = actor.ID
 	} else {
 		actorData = getActorData("nil", "nil", false)
 	}
-	LogAuditEvent(
+	logAuditEvent(
 		true,
 		targetPath,
 		srcIp,
 		eventType.String(),
+		actorId,
 		actorData,
 		getSubject(subjectCurrent, subjectOld),
 	)
@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+const (
+	RedactKeep     = "keep"
+	RedactHash     = "hash"
+	RedactTruncate = "truncate"
+	RedactDrop     = "drop"
+
+	// The subject of the rules for security event targets.
+	RedactionSubjectSecurity = "security"
+
+	defaultTruncateLength = 32
+	// Hex characters of the hash that are kept, enough to tell values apart.
+	redactedHashLength = 16
+)
+
+// Says what happens to a field of logged subjects, configured as a JSON list
+// in LOG_REDACTION. The first rule that matches a field applies, fields that
+// no rule matches are logged as they are.
+type RedactionRule struct {
+	// The objecttype of object events, like todo, or security for the
+	// targets of security events. Empty or * for all.
+	Subject string `json:"subject"`
+	// The event, like objectevent:read or failed-login. Empty or * for all.
+	Event string `json:"event"`
+	// The field, like title, or target for security events.
+	Field string `json:"field"`
+	// keep, hash, truncate or drop.
+	Action string `json:"action"`
+	// The characters truncate keeps. Defaults to 32.
+	Length int `json:"length"`
+}
+
+// User content is kept out of the logs by default. Security event targets
+// hold what was sent, like usernames, so they are only shortened.
+var defaultRedactionRules = []RedactionRule{
+	{Field: "title", Action: RedactHash},
+	{Field: "description", Action: RedactDrop},
+	{Field: "name", Action: RedactHash},
+	{Subject: RedactionSubjectSecurity, Field: "target", Action: RedactTruncate, Length: 64},
+}
+
+type redactionPolicy struct {
+	rules []RedactionRule
+	key   []byte
+}
+
+var currentRedactionPolicy atomic.Pointer[redactionPolicy]
+
+// Parses the rules from LOG_REDACTION, or returns the default ones if it is
+// empty.
+func ParseRedactionRules(value string) ([]RedactionRule, error) {
+	if value == "" {
+		return defaultRedactionRules, nil
+	}
+	var rules []RedactionRule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("invalid log redaction rules: %w", err)
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Field == "" {
+			return nil, fmt.Errorf("log redaction rule %d needs a field", i)
+		}
+		switch rule.Action {
+		case RedactKeep, RedactHash, RedactDrop:
+		case RedactTruncate:
+			if rule.Length <= 0 {
+				rule.Length = defaultTruncateLength
+			}
+		default:
+			return nil, fmt.Errorf("log redaction rule %d has unknown action %v", i, rule.Action)
+		}
+	}
+	return rules, nil
+}
+
+// Sets the rules fields are redacted by. Hashes are keyed with key if it is
+// not empty, so that short values cannot be found by hashing guesses.
+func SetRedaction(rules []RedactionRule, key string) {
+	currentRedactionPolicy.Store(&redactionPolicy{rules: rules, key: []byte(key)})
+}
+
+func getRedactionPolicy() *redactionPolicy {
+	if policy := currentRedactionPolicy.Load(); policy != nil {
+		return policy
+	}
+	return &redactionPolicy{rules: defaultRedactionRules}
+}
+
+func matches(pattern string, value string) bool {
+	return pattern == "" || pattern == "*" || pattern == value
+}
+
+func (policy *redactionPolicy) rule(subject string, event string, field string) *RedactionRule {
+	for i := range policy.rules {
+		rule := &policy.rules[i]
+		if rule.Field == field && matches(rule.Subject, subject) && matches(rule.Event, event) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func (policy *redactionPolicy) hash(value string) string {
+	var sum []byte
+	if len(policy.key) != 0 {
+		mac := hmac.New(sha256.New, policy.key)
+		mac.Write([]byte(value))
+		sum = mac.Sum(nil)
+	} else {
+		digest := sha256.Sum256([]byte(value))
+		sum = digest[:]
+	}
+	return "sha256:" + hex.EncodeToString(sum)[:redactedHashLength]
+}
+
+// Applies the policy to the attribute and the ones in it, if it is a group.
+// Returns false if it is dropped.
+func (policy *redactionPolicy) redact(subject string, event string, attr slog.Attr) (slog.Attr, bool) {
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		kept := make([]slog.Attr, 0, len(group))
+		for _, child := range group {
+			if child, ok := policy.redact(subject, event, child); ok {
+				kept = append(kept, child)
+			}
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(kept...)}, true
+	}
+
+	rule := policy.rule(subject, event, attr.Key)
+	if rule == nil || rule.Action == RedactKeep {
+		return attr, true
+	}
+	value := attr.Value.Resolve().String()
+	switch rule.Action {
+	case RedactDrop:
+		return slog.Attr{}, false
+	case RedactHash:
+		// Empty values are left, they tell nothing.
+		if value != "" {
+			attr = slog.String(attr.Key, policy.hash(value))
+		}
+	case RedactTruncate:
+		if utf8.RuneCountInString(value) > rule.Length {
+			runes := []rune(value)
+			attr = slog.String(attr.Key, string(runes[:rule.Length])+"...")
+		}
+	}
+	return attr, true
+}
+
+// Redacts the fields of a logged subject.
+func redactSubject(subject string, event string, attr slog.Attr) slog.Attr {
+	redacted, ok := getRedactionPolicy().redact(subject, event, attr)
+	if !ok {
+		return slog.Group(attr.Key)
+	}
+	return redacted
+}
@@ -1,6 +1,10 @@
 package logging
 
-import "log/slog"
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
 
 type SecurityScore int
 
@@ -21,12 +25,26 @@ const (
 	SecurityEventJwtUserUnknown
 	SecurityEventJwtUnknown
 	SecurityEventLoginToUnknownUsername
+	SecurityEventCalendarTokenUnknown
+	SecurityEventAppPasswordInvalid
+	SecurityEventPersonalAccessTokenUnknown
+	SecurityEventInsufficientScope
+	SecurityEventTwoFactorFailed
+	SecurityEventOidcLoginFailed
+	SecurityEventPasswordResetToUnknownUsername
+	SecurityEventPasswordResetTokenInvalid
+	SecurityEventLoginLockedOut
+	SecurityEventLoginWhileLocked
+	SecurityEventInviteCodeInvalid
+	SecurityEventEmailVerificationTokenInvalid
 )
 
 func (s SecurityEventName) String() string {
 	switch s {
 	case SecurityEventFailedLogin:
 		return "failed-login"
+	case SecurityEventForbiddenAction:
+		return "forbidden-action"
 	case SecurityEventJwtSignatureInvalid:
 		return "jwt-signature-invalid-use"
 	case SecurityEventJwtUserUnknown:
@@ -37,10 +55,53 @@ func (s SecurityEventName) String() string {
 		return "jwt-reuse"
 	case SecurityEventJwtUnknown:
 		return "jwt-unknown"
+	case SecurityEventCalendarTokenUnknown:
+		return "calendar-token-unknown"
+	case SecurityEventAppPasswordInvalid:
+		return "app-password-invalid"
+	case SecurityEventPersonalAccessTokenUnknown:
+		return "personal-access-token-unknown"
+	case SecurityEventInsufficientScope:
+		return "insufficient-scope"
+	case SecurityEventTwoFactorFailed:
+		return "two-factor-failed"
+	case SecurityEventOidcLoginFailed:
+		return "oidc-login-failed"
+	case SecurityEventPasswordResetToUnknownUsername:
+		return "password-reset-to-unknown-username"
+	case SecurityEventPasswordResetTokenInvalid:
+		return "password-reset-token-invalid"
+	case SecurityEventLoginLockedOut:
+		return "login-locked-out"
+	case SecurityEventLoginWhileLocked:
+		return "login-while-locked"
+	case SecurityEventInviteCodeInvalid:
+		return "invite-code-invalid"
+	case SecurityEventEmailVerificationTokenInvalid:
+		return "email-verification-token-invalid"
 	}
 	return "unknown"
 }
 
+// A security event as it is passed to the hook.
+type SecurityEvent struct {
+	Score      SecurityScore
+	Name       SecurityEventName
+	TargetPath string
+	Target     string
+	Violator   string
+	Time       time.Time
+}
+
+var securityEventHook atomic.Pointer[func(event SecurityEvent)]
+
+// Sets a function that is called with every security event after it has been
+// logged. It is called on the request, so it should hand the event off rather
+// than do slow work.
+func SetSecurityEventHook(hook func(event SecurityEvent)) {
+	securityEventHook.Store(&hook)
+}
+
 func LogSecurityEvent(
 	score SecurityScore,
 	eventName SecurityEventName,
@@ -48,17 +109,32 @@ func LogSecurityEvent(
 	target string,
 	violator string,
 ) {
-	log(
-		slog.LevelInfo,
-		"Security event has happened",
-		"security",
+	attrs := []slog.Attr{
 		slog.Int("score", int(score)),
 		slog.String("target_path", targetPath),
 		slog.Group(
 			"event",
 			slog.String("name", eventName.String()),
-			slog.String("target", target),
+			redactSubject(RedactionSubjectSecurity, eventName.String(), slog.String("target", target)),
 			slog.String("violator", violator),
 		),
+	}
+	log(
+		slog.LevelInfo,
+		"Security event has happened",
+		"security",
+		attrs...,
 	)
+	callAuditEntryHook("security", eventName.String(), violator, "", targetPath, attrs...)
+
+	if hook := securityEventHook.Load(); hook != nil {
+		(*hook)(SecurityEvent{
+			Score:      score,
+			Name:       eventName,
+			TargetPath: targetPath,
+			Target:     target,
+			Violator:   violator,
+			Time:       time.Now(),
+		})
+	}
 }
@@ -10,6 +10,7 @@ const (
 	SessionEventTypeLogin SessionEventType = iota
 	SessionEventTypeLogout
 	SessionEventTypeRefresh
+	SessionEventTypeRevoke
 )
 
 func (s SessionEventType) String() string {
@@ -20,6 +21,8 @@ func (s SessionEventType) String() string {
 		return "session:logout"
 	case SessionEventTypeRefresh:
 		return "session:refresh"
+	case SessionEventTypeRevoke:
+		return "session:revoke"
 	}
 	return "unknown"
 }
@@ -32,11 +35,12 @@ func LogSessionEvent(
 	eventType SessionEventType,
 	srcIp string,
 ) {
-	LogAuditEvent(
+	logAuditEvent(
 		success,
 		targetPath,
 		srcIp,
 		eventType.String(),
+		username,
 		slog.Group(
 			"target",
 			slog.String("username", username),
@@ -0,0 +1,301 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DeRuina/timberjack"
+)
+
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+	SinkHttp   = "http"
+
+	FormatJson   = "json"
+	FormatLogfmt = "logfmt"
+
+	sourcetypePrefix = "go-todo:"
+	// The sourcetype of records that are not logged through log(), like the
+	// ones of slog.Info.
+	sourcetypeApp = "app"
+)
+
+// Where log records go, configured as a JSON list in LOG_SINKS.
+type Sink struct {
+	// stdout, file, syslog or http.
+	Type string `json:"type"`
+	// json or logfmt. Defaults to json.
+	Format string `json:"format"`
+	// debug, info, warn or error. Defaults to info.
+	Level string `json:"level"`
+	// The sourcetypes that go to the sink, like security, audit, error or app
+	// for records without one. All of them when empty.
+	Sourcetypes []string `json:"sourcetypes"`
+
+	// The file for file sinks, rotated when it gets large. Defaults to
+	// ./app.log.
+	Path string `json:"path"`
+	// tcp or udp for syslog sinks. Defaults to udp.
+	Network string `json:"network"`
+	// host:port of the syslog server.
+	Address string `json:"address"`
+	// The syslog facility. Defaults to 1, user-level messages.
+	Facility *int `json:"facility"`
+	// The collector that http sinks post batches of records to, one per line.
+	Url string `json:"url"`
+}
+
+var defaultSinks = []Sink{{Type: SinkFile}}
+
+// Parses the sinks from LOG_SINKS, or returns the default one logging
+// everything to ./app.log if it is empty.
+func ParseSinks(value string) ([]Sink, error) {
+	if value == "" {
+		return defaultSinks, nil
+	}
+	var sinks []Sink
+	if err := json.Unmarshal([]byte(value), &sinks); err != nil {
+		return nil, fmt.Errorf("invalid log sinks: %w", err)
+	}
+	for i := range sinks {
+		sink := &sinks[i]
+		if _, err := sink.level(); err != nil {
+			return nil, err
+		}
+		if sink.Format != "" && sink.Format != FormatJson && sink.Format != FormatLogfmt {
+			return nil, fmt.Errorf("log sink %d has unknown format %v", i, sink.Format)
+		}
+		switch sink.Type {
+		case SinkStdout, SinkFile:
+		case SinkSyslog:
+			if sink.Address == "" {
+				return nil, fmt.Errorf("syslog log sink %d needs an address", i)
+			}
+			if sink.Network != "" && sink.Network != "tcp" && sink.Network != "udp" {
+				return nil, fmt.Errorf("syslog log sink %d has unknown network %v", i, sink.Network)
+			}
+			if sink.Facility != nil && (*sink.Facility < 0 || *sink.Facility > 23) {
+				return nil, fmt.Errorf("syslog log sink %d needs a facility from 0 to 23", i)
+			}
+		case SinkHttp:
+			if sink.Url == "" {
+				return nil, fmt.Errorf("http log sink %d needs a url", i)
+			}
+		default:
+			return nil, fmt.Errorf("log sink %d has unknown type %v", i, sink.Type)
+		}
+	}
+	return sinks, nil
+}
+
+func (sink *Sink) level() (slog.Level, error) {
+	var level slog.Level
+	if sink.Level == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(sink.Level)); err != nil {
+		return level, fmt.Errorf("log sink %v has unknown level %v", sink.Type, sink.Level)
+	}
+	return level, nil
+}
+
+// Returns the handler that formats records for the sink and writes them to
+// it.
+func (sink *Sink) handler() (slog.Handler, error) {
+	level, err := sink.level()
+	if err != nil {
+		return nil, err
+	}
+	options := &slog.HandlerOptions{AddSource: true, Level: level}
+	format := func(writer io.Writer) slog.Handler {
+		if sink.Format == FormatLogfmt {
+			return slog.NewTextHandler(writer, options)
+		}
+		return slog.NewJSONHandler(writer, options)
+	}
+
+	switch sink.Type {
+	case SinkStdout:
+		return format(os.Stdout), nil
+	case SinkFile:
+		path := sink.Path
+		if path == "" {
+			path = "./app.log"
+		}
+		return format(&timberjack.Logger{
+			Filename:   path,
+			MaxSize:    10,
+			MaxBackups: 5,
+			MaxAge:     100,
+			Compress:   true,
+		}), nil
+	case SinkSyslog:
+		return newSyslogHandler(sink, format)
+	case SinkHttp:
+		return format(newHttpWriter(sink)), nil
+	}
+	return nil, fmt.Errorf("unknown log sink type %v", sink.Type)
+}
+
+type route struct {
+	handler     slog.Handler
+	sourcetypes []string
+}
+
+// Hands each record to the sinks that take its sourcetype.
+type routingHandler struct {
+	routes []route
+	// The sourcetype given by WithAttrs, if any.
+	sourcetype string
+}
+
+func newRoutingHandler(sinks []Sink) (*routingHandler, error) {
+	routes := make([]route, 0, len(sinks))
+	for _, sink := range sinks {
+		handler, err := sink.handler()
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route{handler: handler, sourcetypes: sink.Sourcetypes})
+	}
+	return &routingHandler{routes: routes}, nil
+}
+
+func (h *routingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, route := range h.routes {
+		if route.handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *routingHandler) Handle(ctx context.Context, record slog.Record) error {
+	sourcetype := h.sourcetype
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "sourcetype" {
+			sourcetype = strings.TrimPrefix(attr.Value.String(), sourcetypePrefix)
+			return false
+		}
+		return true
+	})
+	if sourcetype == "" {
+		sourcetype = sourcetypeApp
+	}
+
+	var errs []error
+	for _, route := range h.routes {
+		if len(route.sourcetypes) != 0 && !slices.Contains(route.sourcetypes, sourcetype) {
+			continue
+		}
+		if !route.handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := route.handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *routingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &routingHandler{routes: make([]route, len(h.routes)), sourcetype: h.sourcetype}
+	for i, route := range h.routes {
+		next.routes[i] = route
+		next.routes[i].handler = route.handler.WithAttrs(attrs)
+	}
+	for _, attr := range attrs {
+		if attr.Key == "sourcetype" {
+			next.sourcetype = strings.TrimPrefix(attr.Value.String(), sourcetypePrefix)
+		}
+	}
+	return next
+}
+
+func (h *routingHandler) WithGroup(name string) slog.Handler {
+	next := &routingHandler{routes: make([]route, len(h.routes)), sourcetype: h.sourcetype}
+	for i, route := range h.routes {
+		next.routes[i] = route
+		next.routes[i].handler = route.handler.WithGroup(name)
+	}
+	return next
+}
+
+const (
+	sinkQueueSize     = 1000
+	sinkFlushInterval = time.Second
+)
+
+// Sends records to a remote sink in the background, in batches of up to
+// batchSize or what came in the last second. Records are dropped while the
+// queue is full, rather than holding up the code that logs them.
+type sinkQueue struct {
+	name      string
+	records   chan []byte
+	batchSize int
+	send      func(records [][]byte) error
+	failing   bool
+}
+
+func newSinkQueue(name string, batchSize int, send func(records [][]byte) error) *sinkQueue {
+	queue := &sinkQueue{
+		name:      name,
+		records:   make(chan []byte, sinkQueueSize),
+		batchSize: batchSize,
+		send:      send,
+	}
+	go queue.run()
+	return queue
+}
+
+func (q *sinkQueue) push(record []byte) {
+	select {
+	case q.records <- record:
+	default:
+	}
+}
+
+func (q *sinkQueue) run() {
+	ticker := time.NewTicker(sinkFlushInterval)
+	defer ticker.Stop()
+	batch := make([][]byte, 0, q.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		q.report(q.send(batch))
+		batch = make([][]byte, 0, q.batchSize)
+	}
+	for {
+		select {
+		case record := <-q.records:
+			batch = append(batch, record)
+			if len(batch) >= q.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Reports when the sink starts and stops failing on stderr, since logging it
+// could end up in the same sink.
+func (q *sinkQueue) report(err error) {
+	if err != nil && !q.failing {
+		fmt.Fprintf(os.Stderr, "log sink %v is failing, records are dropped: %v\n", q.name, err)
+	} else if err == nil && q.failing {
+		fmt.Fprintf(os.Stderr, "log sink %v works again\n", q.name)
+	}
+	q.failing = err != nil
+}
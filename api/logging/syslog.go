@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	syslogAppName = "go-todo"
+	syslogTimeout = 5 * time.Second
+	// Facility 1, user-level messages.
+	defaultSyslogFacility = 1
+)
+
+// Sends each record as an RFC 5424 message, framed by octet counting over
+// tcp as in RFC 6587, or as one datagram over udp.
+type syslogWriter struct {
+	network  string
+	address  string
+	facility int
+	hostname string
+
+	queue *sinkQueue
+	// Only used by the queue.
+	conn net.Conn
+
+	mu sync.Mutex
+	// The severity and msgid of the record being written, set by
+	// syslogHandler while it holds mu.
+	severity int
+	msgid    string
+}
+
+// Wraps the handler that formats records for a syslog sink, to pass the
+// level and sourcetype of each record to the writer.
+type syslogHandler struct {
+	slog.Handler
+	writer *syslogWriter
+}
+
+func newSyslogHandler(sink *Sink, format func(writer io.Writer) slog.Handler) (slog.Handler, error) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	writer := &syslogWriter{
+		network:  sink.Network,
+		address:  sink.Address,
+		facility: defaultSyslogFacility,
+		hostname: hostname,
+	}
+	if writer.network == "" {
+		writer.network = "udp"
+	}
+	if sink.Facility != nil {
+		writer.facility = *sink.Facility
+	}
+	writer.queue = newSinkQueue("syslog "+sink.Address, 1, writer.sendBatch)
+	return &syslogHandler{Handler: format(writer), writer: writer}, nil
+}
+
+func (h *syslogHandler) Handle(ctx context.Context, record slog.Record) error {
+	msgid := sourcetypeApp
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "sourcetype" {
+			msgid = strings.TrimPrefix(attr.Value.String(), sourcetypePrefix)
+			return false
+		}
+		return true
+	})
+
+	h.writer.mu.Lock()
+	defer h.writer.mu.Unlock()
+	h.writer.severity = syslogSeverity(record.Level)
+	h.writer.msgid = msgid
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &syslogHandler{Handler: h.Handler.WithAttrs(attrs), writer: h.writer}
+}
+
+func (h *syslogHandler) WithGroup(name string) slog.Handler {
+	return &syslogHandler{Handler: h.Handler.WithGroup(name), writer: h.writer}
+}
+
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	}
+	return 7
+}
+
+// Called by the formatting handler with one record, while syslogHandler holds
+// mu. The message is sent in the background, so that a slow or unreachable
+// server does not hold up requests.
+func (w *syslogWriter) Write(record []byte) (int, error) {
+	message := fmt.Sprintf(
+		"<%d>1 %v %v %v %d %v - %s",
+		w.facility*8+w.severity,
+		time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		w.hostname,
+		syslogAppName,
+		os.Getpid(),
+		w.msgid,
+		strings.TrimRight(string(record), "\n"),
+	)
+	if w.network == "tcp" {
+		message = fmt.Sprintf("%d %v", len(message), message)
+	}
+	w.queue.push([]byte(message))
+	return len(record), nil
+}
+
+func (w *syslogWriter) sendBatch(messages [][]byte) error {
+	for _, message := range messages {
+		if err := w.send(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sends the message, redialing once if the connection broke since the last
+// one.
+func (w *syslogWriter) send(message []byte) error {
+	for attempt := 0; ; attempt++ {
+		if w.conn == nil {
+			conn, err := net.DialTimeout(w.network, w.address, syslogTimeout)
+			if err != nil {
+				return fmt.Errorf("failed to connect to syslog: %w", err)
+			}
+			w.conn = conn
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+		if _, err := w.conn.Write(message); err != nil {
+			w.conn.Close()
+			w.conn = nil
+			if attempt == 0 {
+				continue
+			}
+			return fmt.Errorf("failed to write to syslog: %w", err)
+		}
+		return nil
+	}
+}
@@ -33,11 +33,12 @@ func LogTokenEvent(
 	token *jwt.GtClaims,
 ) {
 	if token != nil {
-		LogAuditEvent(
+		logAuditEvent(
 			success,
 			targetPath,
 			srcIp,
 			eventType.String(),
+			token.Subject,
 			slog.Group(
 				"token",
 				slog.String("sub", token.Subject),
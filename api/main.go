@@ -5,25 +5,39 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	db "go-todo/db/sqlc"
+	"go-todo/features/admin"
 	"go-todo/features/auth"
+	"go-todo/features/caldav"
+	"go-todo/features/calendar"
+	"go-todo/features/folder"
 	"go-todo/features/todo"
+	"go-todo/features/transfer"
 	"go-todo/features/user"
 	"go-todo/logging"
 	"go-todo/middleware"
+	"go-todo/util/auditlog"
 	"go-todo/util/config"
+	"go-todo/util/jwtkeys"
+	"go-todo/util/risk"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var ctx context.Context
 
 func main() {
-	appLogger := logging.GetLogger()
+	appLogger, err := logging.GetLogger()
+	if err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Failed to set up logging.")
+		return
+	}
 	slog.SetDefault(appLogger)
 
 	config, err := config.Get()
@@ -33,30 +47,73 @@ func main() {
 		return
 	}
 
-	conn, err := pgx.Connect(context.Background(), config.DbUrl)
+	pool, err := pgxpool.New(context.Background(), config.DbUrl)
 	if err != nil {
 		_, file, line, _ := runtime.Caller(1)
 		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Failed to connect to database.")
 		return
+	}
+	if err := pool.Ping(context.Background()); err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Failed to connect to database.")
+		return
 	} else {
 		fmt.Println("Connected to database")
 	}
 
-	defer conn.Close(ctx)
+	defer pool.Close()
+
+	mydb := db.New(pool)
 
-	mydb := db.New(conn)
+	auditlog.Start(context.Background(), pool, mydb)
 
-	authController := auth.NewController(mydb, ctx)
+	if err := jwtkeys.Start(context.Background(), pool, mydb); err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Failed to load jwt signing keys.")
+		return
+	}
+
+	if err := risk.Start(context.Background(), mydb); err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Failed to start security scoring.")
+		return
+	}
+
+	authController := auth.NewController(mydb, pool, ctx)
 	authRoutes := auth.NewRoutes(authController)
-	userController := user.NewController(mydb, ctx)
+	userController := user.NewController(mydb, pool, ctx)
 	userRoutes := user.NewRoutes(userController)
-	listController := todo.NewController(mydb, ctx)
+	listController := todo.NewController(mydb, pool, ctx)
 	listRoutes := todo.NewRoutes(listController)
+	folderController := folder.NewController(mydb, pool, ctx)
+	folderRoutes := folder.NewRoutes(folderController)
+	calendarController := calendar.NewController(mydb, ctx)
+	calendarRoutes := calendar.NewRoutes(calendarController)
+	transferController := transfer.NewController(mydb, pool, ctx)
+	transferRoutes := transfer.NewRoutes(transferController)
+	caldavController := caldav.NewController(mydb, listController, ctx)
+	caldavRoutes := caldav.NewRoutes(caldavController)
+	adminController := admin.NewController(mydb, ctx)
+	adminRoutes := admin.NewRoutes(adminController)
 
 	router := gin.Default()
+	// Login lockouts and ip blocks go by the client ip, so it must not come
+	// from headers anyone can send.
+	var trustedProxies []string
+	for _, proxy := range strings.Split(config.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Invalid trusted proxies.")
+		return
+	}
 
 	router.Use(middleware.Logger())
 	router.Use(middleware.ErrorHandlerMiddleware())
+	router.Use(middleware.IpBlockMiddleware(mydb))
 	router.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		return fmt.Sprintf("%s - [%s] \"%s %s %s %d \"%s\" %s\"\n",
 			param.ClientIP,
@@ -78,7 +135,14 @@ func main() {
 		authRoutes.Register(v1)
 		userRoutes.Register(v1)
 		listRoutes.Register(v1)
+		folderRoutes.Register(v1)
+		calendarRoutes.Register(v1)
+		transferRoutes.Register(v1)
+		adminRoutes.Register(v1)
 	}
+	// CalDAV clients expect the server at the root, next to /.well-known.
+	caldavRoutes.Register(&router.RouterGroup)
+	authRoutes.RegisterWellKnown(&router.RouterGroup)
 
 	slog.Info("Starting server.")
 	router.Run(fmt.Sprintf("%v:8000", config.Host))
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/lockout"
+	"go-todo/util/secret"
+	"math"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Authenticates the request with the username and an app password sent as
+// HTTP basic auth, for clients that cannot refresh jwts. Sets the same keys as
+// JwtAuthMiddleware. Wrong app passwords count as failed logins, and locked
+// usernames and ips are rejected like at login.
+func AppPasswordAuthMiddleware(queries *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reject := func(err error) {
+			c.Header("WWW-Authenticate", `Basic realm="go-todo", charset="UTF-8"`)
+			c.Error(
+				gterrors.NewGtAuthError(gterrors.GtAuthErrorReasonInvalidCredentials, err),
+			).SetType(gterrors.GetGinErrorType())
+			c.Abort()
+		}
+
+		username, password, ok := c.Request.BasicAuth()
+		if !ok {
+			reject(errors.New("basic auth missing"))
+			return
+		}
+
+		lockedUntil, err := lockout.LockedUntil(c, queries, username, c.ClientIP())
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			c.Error(
+				gterrors.NewGtInternalError(
+					fmt.Errorf("failed to check login lock: %w", err),
+					fmt.Sprintf("%v: %d", file, line),
+					500,
+				),
+			).SetType(gterrors.GetGinErrorType())
+			c.Abort()
+			return
+		} else if !lockedUntil.IsZero() {
+			logging.LogSecurityEvent(
+				logging.SecurityScoreLow,
+				logging.SecurityEventLoginWhileLocked,
+				c.FullPath(),
+				username,
+				c.ClientIP(),
+			)
+			retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			c.Error(gterrors.ErrLoginLocked).SetType(gin.ErrorTypePublic)
+			c.Abort()
+			return
+		}
+
+		appPassword, err := queries.GetAppPasswordByHash(c, secret.Hash(password))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			_, file, line, _ := runtime.Caller(0)
+			c.Error(
+				gterrors.NewGtInternalError(
+					fmt.Errorf("failed to get app password: %w", err),
+					fmt.Sprintf("%v: %d", file, line),
+					500,
+				),
+			).SetType(gterrors.GetGinErrorType())
+			c.Abort()
+			return
+		}
+
+		var user db.User
+		if err == nil {
+			user, err = queries.GetUserById(c, appPassword.UserID)
+		}
+		if err != nil || user.Username != username {
+			eventName := logging.SecurityEventAppPasswordInvalid
+			if _, err := queries.GetUserByUsername(c, username); errors.Is(err, pgx.ErrNoRows) {
+				eventName = logging.SecurityEventLoginToUnknownUsername
+			}
+			logging.LogSecurityEvent(
+				logging.SecurityScoreLow,
+				eventName,
+				c.FullPath(),
+				username,
+				c.ClientIP(),
+			)
+			recordAppPasswordFailure(queries, username, c)
+			reject(errors.New("app password verification failed"))
+			return
+		}
+
+		if err := queries.TouchAppPassword(c, appPassword.ID); err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			logging.LogError(
+				fmt.Errorf("failed to update app password last use: %w", err),
+				fmt.Sprintf("%v: %d", file, line),
+				err.Error(),
+			)
+		}
+
+		c.Set("x-token-username", user.Username)
+		c.Set("x-token-user-id", user.ID)
+		c.Set("x-token-is-admin", user.IsAdmin)
+
+		c.Next()
+	}
+}
+
+// Counts the wrong app password as a failed login of the username, see
+// lockout.RecordFailure.
+func recordAppPasswordFailure(queries *db.Queries, username string, c *gin.Context) {
+	lockedOut, err := lockout.RecordFailure(c, queries, username, c.ClientIP())
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to record login failure: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+		return
+	}
+	for _, key := range lockedOut {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreHigh,
+			logging.SecurityEventLoginLockedOut,
+			c.FullPath(),
+			key,
+			c.ClientIP(),
+		)
+	}
+}
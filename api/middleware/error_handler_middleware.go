@@ -20,12 +20,21 @@ type ResponseParams struct {
 type StatusMessage int
 
 const (
-	StatusMessageForbidden StatusMessage = iota
+	StatusMessageEmailNotVerified StatusMessage = iota
+	StatusMessageForbidden
 	StatusMessageInternalServerError
 	StatusMessageInvalidCredentials
+	StatusMessageInviteCodeInvalid
+	StatusMessageIpBlocked
+	StatusMessageListArchived
+	StatusMessageLoginLocked
 	StatusMessageMalformedBody
 	StatusMessageNotFound
 	StatusMessagePasswordUnsatisfied
+	StatusMessagePreconditionFailed
+	StatusMessageRegistrationClosed
+	StatusMessageTimerRunning
+	StatusMessageTwoFactorEnabled
 	StatusMessageUnauthorized
 	StatusMessageUniqueViolation
 	StatusMessageUsernameUnsatisfied
@@ -33,18 +42,36 @@ const (
 
 func (t StatusMessage) String() string {
 	switch t {
+	case StatusMessageEmailNotVerified:
+		return "email-not-verified"
 	case StatusMessageForbidden:
 		return "forbidden"
 	case StatusMessageInternalServerError:
 		return "internal-server-error"
 	case StatusMessageInvalidCredentials:
 		return "invalid-credentials"
+	case StatusMessageInviteCodeInvalid:
+		return "invite-code-invalid"
+	case StatusMessageIpBlocked:
+		return "ip-blocked"
+	case StatusMessageListArchived:
+		return "list-archived"
+	case StatusMessageLoginLocked:
+		return "login-locked"
 	case StatusMessageMalformedBody:
 		return "malformed-body"
 	case StatusMessageNotFound:
 		return "not-found"
 	case StatusMessagePasswordUnsatisfied:
 		return "password-unsatisfied"
+	case StatusMessagePreconditionFailed:
+		return "precondition-failed"
+	case StatusMessageRegistrationClosed:
+		return "registration-closed"
+	case StatusMessageTimerRunning:
+		return "timer-running"
+	case StatusMessageTwoFactorEnabled:
+		return "two-factor-enabled"
 	case StatusMessageUnauthorized:
 		return "unauthorized"
 	case StatusMessageUniqueViolation:
@@ -80,8 +107,26 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 			params = &ResponseParams{400, StatusMessageUsernameUnsatisfied.String(), err.Error()}
 		case errors.Is(err, gterrors.ErrForbidden):
 			params = &ResponseParams{403, StatusMessageForbidden.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrIpBlocked):
+			params = &ResponseParams{403, StatusMessageIpBlocked.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrEmailNotVerified):
+			params = &ResponseParams{403, StatusMessageEmailNotVerified.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrInviteCodeInvalid):
+			params = &ResponseParams{403, StatusMessageInviteCodeInvalid.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrRegistrationClosed):
+			params = &ResponseParams{403, StatusMessageRegistrationClosed.String(), err.Error()}
 		case errors.Is(err, gterrors.ErrUniqueViolation):
 			params = &ResponseParams{409, StatusMessageUniqueViolation.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrListArchived):
+			params = &ResponseParams{409, StatusMessageListArchived.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrTimerRunning):
+			params = &ResponseParams{409, StatusMessageTimerRunning.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrTwoFactorEnabled):
+			params = &ResponseParams{409, StatusMessageTwoFactorEnabled.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrLoginLocked):
+			params = &ResponseParams{429, StatusMessageLoginLocked.String(), err.Error()}
+		case errors.Is(err, gterrors.ErrPreconditionFailed):
+			params = &ResponseParams{412, StatusMessagePreconditionFailed.String(), err.Error()}
 		case errors.Is(err, gterrors.ErrNotFound):
 			params = &ResponseParams{404, StatusMessageNotFound.String(), err.Error()}
 		case errors.As(err, &validationError):
@@ -112,8 +157,15 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 				statusMessage = StatusMessageUnauthorized.String()
 			case gterrors.GtAuthErrorReasonTokenReuse:
 				statusMessage = StatusMessageUnauthorized.String()
+			case gterrors.GtAuthErrorReasonTokenRevoked:
+				statusMessage = StatusMessageUnauthorized.String()
+			case gterrors.GtAuthErrorReasonTwoFactorInvalid:
+				statusMessage = StatusMessageInvalidCredentials.String()
 			case gterrors.GtAuthErrorReasonUsernameInvalid:
 				statusMessage = StatusMessageInvalidCredentials.String()
+			case gterrors.GtAuthErrorReasonInsufficientScope:
+				statusMessage = StatusMessageForbidden.String()
+				status = 403
 			case gterrors.GtAuthErrorReasonInternalError:
 				statusMessage = StatusMessageUnauthorized.String()
 				if isPublic {
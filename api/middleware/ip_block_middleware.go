@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/util/mycontext"
+	"go-todo/util/risk"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Refuses requests from ips that a security rule has blocked.
+func IpBlockMiddleware(queries *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		blocked, err := risk.IsBlocked(c, queries, c.ClientIP())
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			mycontext.CtxAddGtInternalError("failed to check ip block", file, line, err, c)
+			c.Abort()
+			return
+		} else if blocked {
+			c.Error(gterrors.ErrIpBlocked).SetType(gin.ErrorTypePublic)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -3,18 +3,33 @@ package middleware
 import (
 	"errors"
 	"fmt"
+	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 	"go-todo/logging"
 	jwtUtil "go-todo/util/jwt"
+	"go-todo/util/lockout"
+	"go-todo/util/revocation"
+	"go-todo/util/secret"
+	"math"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
-// Tries to extract the JWT from Authorization header. Returns an error status
-// to the client if it fails.
-func JwtAuthMiddleware() gin.HandlerFunc {
+// Tries to extract the JWT, or a personal access token, from Authorization
+// header. Returns an error status to the client if it fails or the JWT has
+// been revoked.
+func JwtAuthMiddleware(queries *db.Queries) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if strings.HasPrefix(jwtUtil.GetTokenFromHeader(c), jwtUtil.PersonalAccessTokenPrefix) {
+			personalAccessTokenAuth(queries, c)
+			return
+		}
+
 		token, err := jwtUtil.DecodeTokenFromHeader(c)
 		if err != nil {
 			ginType := gterrors.GetGinErrorType()
@@ -30,6 +45,8 @@ func JwtAuthMiddleware() gin.HandlerFunc {
 					reason = gterrors.GtAuthErrorReasonExpired
 				case jwtUtil.JwtErrorReasonInvalidSignature:
 					reason = gterrors.GtAuthErrorReasonInvalidSignature
+				case jwtUtil.JwtErrorReasonTokenMalformed:
+					reason = gterrors.GtAuthErrorReasonTokenInvalid
 				case jwtUtil.JwtErrorReasonUnhandled:
 					reason = gterrors.GtAuthErrorReasonInternalError
 				default:
@@ -54,12 +71,144 @@ func JwtAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		logging.LogTokenEvent(true, c.FullPath(), logging.TokenEventTypeAccess, c.RemoteIP(), token)
+		revoked, err := revocation.IsRevoked(c, queries, token)
+		if err != nil {
+			_, file, line, _ := runtime.Caller(0)
+			c.Error(
+				gterrors.NewGtInternalError(err, fmt.Sprintf("%v: %d", file, line), 500),
+			).SetType(gterrors.GetGinErrorType())
+			c.Abort()
+			return
+		} else if revoked {
+			logging.LogTokenEvent(false, c.FullPath(), logging.TokenEventTypeAccess, c.RemoteIP(), token)
+			c.Error(
+				gterrors.NewGtAuthError(
+					gterrors.GtAuthErrorReasonTokenRevoked,
+					errors.New("access token revoked"),
+				),
+			).SetType(gterrors.GetGinErrorType())
+			c.Abort()
+			return
+		}
 
-		c.Set("x-token-username", token.Username)
-		c.Set("x-token-user-id", token.Subject)
-		c.Set("x-token-is-admin", token.IsAdmin)
+		logging.LogTokenEvent(true, c.FullPath(), logging.TokenEventTypeAccess, c.RemoteIP(), token)
 
+		setTokenKeys(token, c)
 		c.Next()
 	}
 }
+
+// Authenticates the request with the personal access token in the
+// Authorization header, unless the username of its user is locked. Sets the
+// same keys as a jwt would.
+func personalAccessTokenAuth(queries *db.Queries, c *gin.Context) {
+	row, err := queries.GetPersonalAccessTokenByHash(c, secret.Hash(jwtUtil.GetTokenFromHeader(c)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logging.LogSecurityEvent(
+				logging.SecurityScoreMedium,
+				logging.SecurityEventPersonalAccessTokenUnknown,
+				c.FullPath(),
+				"personal access token",
+				c.ClientIP(),
+			)
+			c.Error(
+				gterrors.NewGtAuthError(
+					gterrors.GtAuthErrorReasonTokenInvalid,
+					errors.New("personal access token not found"),
+				),
+			).SetType(gterrors.GetGinErrorType())
+			c.Abort()
+			return
+		}
+		_, file, line, _ := runtime.Caller(0)
+		c.Error(
+			gterrors.NewGtInternalError(
+				fmt.Errorf("failed to get personal access token: %w", err),
+				fmt.Sprintf("%v: %d", file, line),
+				500,
+			),
+		).SetType(gterrors.GetGinErrorType())
+		c.Abort()
+		return
+	}
+
+	pat := row.PersonalAccessToken
+	var expiresAt *time.Time
+	if pat.ExpiresAt.Valid {
+		expiresAt = &pat.ExpiresAt.Time
+	}
+	token := jwtUtil.PersonalAccessTokenClaims(
+		pat.ID,
+		row.Username,
+		pat.UserID,
+		row.IsAdmin,
+		pat.Scopes,
+		pat.ListIds,
+		pat.CreatedAt.Time,
+		expiresAt,
+	)
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		logging.LogTokenEvent(false, c.FullPath(), logging.TokenEventTypeAccess, c.RemoteIP(), token)
+		c.Error(
+			gterrors.NewGtAuthError(
+				gterrors.GtAuthErrorReasonExpired,
+				errors.New("personal access token expired"),
+			),
+		).SetType(gterrors.GetGinErrorType())
+		c.Abort()
+		return
+	}
+
+	lockedUntil, err := lockout.UsernameLockedUntil(c, queries, row.Username)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		c.Error(
+			gterrors.NewGtInternalError(
+				fmt.Errorf("failed to check login lock: %w", err),
+				fmt.Sprintf("%v: %d", file, line),
+				500,
+			),
+		).SetType(gterrors.GetGinErrorType())
+		c.Abort()
+		return
+	} else if !lockedUntil.IsZero() {
+		logging.LogSecurityEvent(
+			logging.SecurityScoreLow,
+			logging.SecurityEventLoginWhileLocked,
+			c.FullPath(),
+			row.Username,
+			c.ClientIP(),
+		)
+		retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		c.Error(gterrors.ErrLoginLocked).SetType(gin.ErrorTypePublic)
+		c.Abort()
+		return
+	}
+
+	if err := queries.TouchPersonalAccessToken(c, pat.ID); err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		logging.LogError(
+			fmt.Errorf("failed to update personal access token last use: %w", err),
+			fmt.Sprintf("%v: %d", file, line),
+			err.Error(),
+		)
+	}
+
+	logging.LogTokenEvent(true, c.FullPath(), logging.TokenEventTypeAccess, c.RemoteIP(), token)
+
+	setTokenKeys(token, c)
+	c.Next()
+}
+
+// Sets the claims read by mycontext.GetTokenVariables, mycontext.GetTokenScopes
+// and mycontext.GetTokenSession.
+func setTokenKeys(token *jwtUtil.GtClaims, c *gin.Context) {
+	c.Set("x-token-username", token.Username)
+	c.Set("x-token-user-id", token.Subject)
+	c.Set("x-token-is-admin", token.IsAdmin)
+	c.Set("x-token-scopes", token.Scopes)
+	c.Set("x-token-list-ids", token.ListIDs)
+	c.Set("x-token-session", token.Session)
+}
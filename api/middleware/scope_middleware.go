@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	jwtUtil "go-todo/util/jwt"
+	"go-todo/util/mycontext"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lets the request through only if the token has every one of scopes. Tokens
+// limited to some lists only reach routes of those lists, which are the ones
+// with a listID param. Must come after JwtAuthMiddleware.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenScopes, listIDs := mycontext.GetTokenScopes(c)
+
+		var err error
+		if missing := jwtUtil.MissingScope(tokenScopes, scopes...); missing != "" {
+			err = fmt.Errorf("token is missing scope %v", missing)
+		} else if len(listIDs) > 0 {
+			if listID := c.Param("listID"); listID == "" {
+				err = fmt.Errorf("token is limited to lists %v", listIDs)
+			} else if !slices.Contains(listIDs, listID) {
+				err = fmt.Errorf("token is not allowed to access list %v", listID)
+			}
+		}
+		if err != nil {
+			logging.LogSecurityEvent(
+				logging.SecurityScoreLow,
+				logging.SecurityEventInsufficientScope,
+				c.FullPath(),
+				c.GetString("x-token-user-id"),
+				c.ClientIP(),
+			)
+			c.Error(
+				gterrors.NewGtAuthError(gterrors.GtAuthErrorReasonInsufficientScope, err),
+			).SetType(gin.ErrorTypePublic)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
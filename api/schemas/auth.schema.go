@@ -1,5 +1,7 @@
 package schemas
 
+import "time"
+
 type Login struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
@@ -12,4 +14,63 @@ type Refresh struct {
 type UpdatePassword struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required"`
-}
\ No newline at end of file
+}
+
+type CreateAppPassword struct {
+	// Shown to tell the passwords apart, like the client that uses it.
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type CreatePersonalAccessToken struct {
+	// Shown to tell the tokens apart, like the script that uses it.
+	Name string `json:"name" binding:"required,max=100"`
+	// The token never expires without it.
+	ExpiresAt *time.Time `json:"expires_at"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=lists:read lists:write todos:write users:admin"`
+	// Limits the token to these lists, it can access every list without it.
+	ListIDs []string `json:"list_ids"`
+}
+
+type EnableTotp struct {
+	// A code from the authenticator, proving it was set up.
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTotp struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type CreateRecoveryCodes struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type VerifyLogin struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// A code from the authenticator or a recovery code.
+	Code string `json:"code" binding:"required"`
+}
+
+type OidcCallback struct {
+	// The code and state the provider sent back to the redirect url.
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+type ForgotPassword struct {
+	Username string `json:"username" binding:"required"`
+}
+
+type ResetPassword struct {
+	// The token from the emailed reset link.
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type VerifyEmail struct {
+	// The token from the emailed verification link.
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerification struct {
+	Username string `json:"username" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package schemas
+
+type CreateCalendarFeed struct {
+	// Limits the feed to one list.
+	ListID        *string `json:"list_id"`
+	IncludeEvents bool    `json:"include_events"`
+}
@@ -0,0 +1,16 @@
+package schemas
+
+type CreateFolder struct {
+	Title    string  `json:"title" binding:"required"`
+	ParentID *string `json:"parent_id"`
+}
+
+type UpdateFolder struct {
+	Title *string `json:"title"`
+	// An empty string moves the folder to the root.
+	ParentID *string `json:"parent_id"`
+}
+
+type CreateFolderShare struct {
+	UserID string `json:"user_id" binding:"required"`
+}
@@ -0,0 +1,10 @@
+package schemas
+
+import "time"
+
+type CreateInviteCode struct {
+	// How many accounts can be created with the code. Defaults to 1.
+	MaxUses *int32 `json:"max_uses" binding:"omitempty,min=1"`
+	// Defaults to 7 days from now.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,7 @@
+package schemas
+
+type ResponseRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,24 @@
+package schemas
+
+import "time"
+
+type CreateTemplate struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	IsShared    bool    `json:"is_shared"`
+	// Due offsets of the todos are counted from this. Defaults to the creation
+	// time of the list.
+	StartDate *time.Time `json:"start_date"`
+}
+
+type UpdateTemplate struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	IsShared    *bool   `json:"is_shared"`
+}
+
+type CreateListFromTemplate struct {
+	StartDate   time.Time `json:"start_date" binding:"required"`
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+}
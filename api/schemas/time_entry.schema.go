@@ -0,0 +1,9 @@
+package schemas
+
+import "time"
+
+type CreateTimeEntry struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	StoppedAt time.Time `json:"stopped_at" binding:"required"`
+	Note      *string   `json:"note"`
+}
@@ -3,15 +3,37 @@ package schemas
 import "time"
 
 type CreateTodo struct {
-	Title          string     `json:"title" binding:"required"`
-	Description    *string    `json:"description"`
-	CompleteBefore *time.Time `json:"complete_before"`
-	ParentID       *string    `json:"parent_id"`
+	Title             string     `json:"title" binding:"required"`
+	Description       *string    `json:"description"`
+	CompleteBefore    *time.Time `json:"complete_before"`
+	ParentID          *string    `json:"parent_id"`
+	EstimatedDuration *int32     `json:"estimated_duration" binding:"omitempty,min=0"`
+	// An all-day due date like 2025-01-31, used instead of complete_before.
+	DueDate *string `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	// From 0 (none) to 3 (high).
+	Priority *int16   `json:"priority" binding:"omitempty,min=0,max=3"`
+	Tags     []string `json:"tags"`
+	// RFC 5545 recurrence rule without the "RRULE:" prefix.
+	Recurrence *string `json:"recurrence"`
 }
 
 type UpdateTodo struct {
-	Title          *string    `json:"title"`
-	Description    *string    `json:"description"`
+	Title             *string  `json:"title"`
+	Description       *string  `json:"description"`
+	Completed         *bool    `json:"completed"`
+	EstimatedDuration *int32   `json:"estimated_duration" binding:"omitempty,min=0"`
+	Priority          *int16   `json:"priority" binding:"omitempty,min=0,max=3"`
+	Tags              []string `json:"tags"`
+	// Setting one of complete_before and due_date removes the other. The Unix
+	// epoch and an empty string remove them.
 	CompleteBefore *time.Time `json:"complete_before"`
-	Completed      *bool      `json:"completed"`
+	DueDate        *string    `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	// An empty string removes the recurrence.
+	Recurrence *string `json:"recurrence"`
+}
+
+type QuickAddTodo struct {
+	Text string `json:"text" binding:"required"`
+	// IANA time zone the text is read in, the user's time zone by default.
+	TimeZone *string `json:"time_zone"`
 }
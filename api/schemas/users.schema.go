@@ -6,16 +6,38 @@ type CreateUser struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 	IsAdmin  bool   `json:"is_admin"`
+	// Optional, password reset links are sent to it. Required when email
+	// verification is.
+	Email string `json:"email"`
+	// Required when registration is invite-only.
+	InviteCode string `json:"invite_code"`
 }
 
 type UpdateUser struct {
 	Username string `json:"username" binding:"required"`
 	IsAdmin  *bool  `json:"is_admin" binding:"required"`
+	// Left as is when missing, an empty string removes it.
+	Email *string `json:"email"`
+	// Current password of the requester, needed to change their own email
+	// address unless they logged in recently.
+	Password *string `json:"password"`
+}
+
+type UpdatePreferences struct {
+	// IANA time zone such as Europe/Berlin.
+	TimeZone *string `json:"time_zone"`
+	// 0 is Sunday, 1 is Monday and so on.
+	WeekStart  *int16  `json:"week_start" binding:"omitempty,min=0,max=6"`
+	Locale     *string `json:"locale"`
+	DateFormat *string `json:"date_format"`
 }
 
 type ResponseUser struct {
-	Id        string    `json:"id"`
-	Username  string    `json:"username"`
-	IsAdmin   bool      `json:"is_admin"`
-	CreatedAt time.Time `json:"created_at"`
+	Id            string    `json:"id"`
+	Username      string    `json:"username"`
+	IsAdmin       bool      `json:"is_admin"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified *bool     `json:"email_verified,omitempty"`
+	Roles         []string  `json:"roles"`
+	CreatedAt     time.Time `json:"created_at"`
 }
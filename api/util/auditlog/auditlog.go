@@ -0,0 +1,186 @@
+// Package auditlog stores audit, session, token, object and security events in
+// the audit_log table, next to the log files that rotate them away.
+//
+// Entries form a chain: each stores the hash of the one before and its own
+// hash covers that, so that changing, removing or inserting an entry is
+// detected by Verify. The table refuses updates and deletes, the chain catches
+// what gets past that.
+package auditlog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Entries waiting to be stored. Further ones are dropped until there is
+	// room again.
+	entryBuffer = 1000
+	// Entries appended in one transaction at most.
+	batchSize      = 100
+	verifyPageSize = 1000
+)
+
+// The prev_hash of the first entry.
+var genesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Starts storing the events logged from now on, until ctx is done.
+func Start(ctx context.Context, pool *pgxpool.Pool, queries *db.Queries) {
+	entries := make(chan logging.AuditEntry, entryBuffer)
+	logging.SetAuditEntryHook(func(entry logging.AuditEntry) {
+		select {
+		case entries <- entry:
+		default:
+			logError(errors.New("audit log buffer is full, entry not stored"))
+		}
+	})
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry := <-entries:
+				batch := []logging.AuditEntry{entry}
+			drain:
+				for len(batch) < batchSize {
+					select {
+					case entry := <-entries:
+						batch = append(batch, entry)
+					default:
+						break drain
+					}
+				}
+				if err := appendEntries(ctx, pool, queries, batch); err != nil {
+					logError(fmt.Errorf("failed to store %d audit log entries: %w", len(batch), err))
+				}
+			}
+		}
+	}()
+}
+
+func logError(err error) {
+	_, file, line, _ := runtime.Caller(1)
+	logging.LogError(err, fmt.Sprintf("%v: %d", file, line), err.Error())
+}
+
+// Returns the hash of the entry, which covers every column but hash itself.
+func Hash(entry *db.AuditLog) string {
+	// Marshalling a slice of strings cannot fail.
+	fields, _ := json.Marshal([]string{
+		fmt.Sprint(entry.Seq),
+		entry.CreatedAt.Time.UTC().Format(time.RFC3339Nano),
+		entry.Sourcetype,
+		entry.EventType,
+		entry.Actor,
+		entry.SrcIp,
+		entry.TargetPath,
+		string(entry.Data),
+		entry.PrevHash,
+	})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
+
+func appendEntries(ctx context.Context, pool *pgxpool.Pool, queries *db.Queries, entries []logging.AuditEntry) error {
+	return database.WithTx(ctx, pool, queries, func(qtx *db.Queries) error {
+		if err := qtx.LockAuditLog(ctx); err != nil {
+			return err
+		}
+		seq, prevHash := int64(0), genesisHash
+		last, err := qtx.GetLastAuditLogEntry(ctx)
+		if err == nil {
+			seq, prevHash = last.Seq, last.Hash
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		for _, entry := range entries {
+			seq++
+			row := db.AuditLog{
+				Seq: seq,
+				// Postgres keeps microseconds, so the hash is made from
+				// what is read back.
+				CreatedAt:  pgtype.Timestamptz{Time: entry.Time.UTC().Truncate(time.Microsecond), Valid: true},
+				Sourcetype: entry.Sourcetype,
+				EventType:  entry.EventType,
+				Actor:      entry.Actor,
+				SrcIp:      entry.SrcIp,
+				TargetPath: entry.TargetPath,
+				Data:       entry.Data,
+				PrevHash:   prevHash,
+			}
+			row.Hash = Hash(&row)
+			if err := qtx.CreateAuditLogEntry(ctx, db.CreateAuditLogEntryParams(row)); err != nil {
+				return err
+			}
+			prevHash = row.Hash
+		}
+		return nil
+	})
+}
+
+// The result of checking the chain.
+type Verification struct {
+	Valid bool `json:"valid"`
+	// Entries checked, up to the first broken one.
+	Entries int64 `json:"entries"`
+	// The hash of the last valid entry. Entries removed from the end of the
+	// chain leave it intact, so this is worth comparing with one noted down
+	// earlier.
+	LastHash string `json:"last_hash"`
+	// The first entry that does not fit the chain and why, if any.
+	BrokenSeq int64  `json:"broken_seq,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Checks every entry from the first one against the chain.
+func Verify(ctx context.Context, queries *db.Queries) (*Verification, error) {
+	verification := &Verification{Valid: true, LastHash: genesisHash}
+	seq := int64(0)
+	for {
+		args := &db.GetAuditLogEntriesAfterParams{Seq: seq, Limit: verifyPageSize}
+		page, err := queries.GetAuditLogEntriesAfter(ctx, *args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audit log entries: %w", err)
+		}
+		for _, entry := range page {
+			reason := ""
+			switch {
+			case entry.Seq != seq+1:
+				reason = fmt.Sprintf("entries %d to %d are missing", seq+1, entry.Seq-1)
+			case entry.PrevHash != verification.LastHash:
+				reason = "prev_hash does not match the hash of the entry before"
+			case Hash(&entry) != entry.Hash:
+				reason = "hash does not match the entry"
+			}
+			if reason != "" {
+				verification.Valid = false
+				verification.BrokenSeq = entry.Seq
+				verification.Reason = reason
+				return verification, nil
+			}
+			seq = entry.Seq
+			verification.Entries++
+			verification.LastHash = entry.Hash
+		}
+		if len(page) < verifyPageSize {
+			return verification, nil
+		}
+	}
+}
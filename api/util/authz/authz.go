@@ -0,0 +1,121 @@
+// Package authz decides what users may do beyond their own data. Admins may do
+// everything, other users what the roles assigned to them permit. Roles are
+// read from the database on every check, so changes apply to jwts that have
+// already been issued.
+package authz
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	db "go-todo/db/sqlc"
+	"go-todo/gterrors"
+	"go-todo/logging"
+	"go-todo/util/mycontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The permissions in the permissions table.
+const (
+	PermissionUsersRead      = "users:read"
+	PermissionUsersManage    = "users:manage"
+	PermissionSessionsRead   = "sessions:read"
+	PermissionSessionsManage = "sessions:manage"
+	PermissionListsRead      = "lists:read"
+	PermissionListsManage    = "lists:manage"
+	PermissionAuditRead      = "audit:read"
+	PermissionSecurityManage = "security:manage"
+	// Includes making users admin, as admins have every permission.
+	PermissionRolesManage = "roles:manage"
+)
+
+// Returns whether the user has the permission, as an admin or through one of
+// their roles.
+func Can(ctx context.Context, queries *db.Queries, user *db.User, permission string) (bool, error) {
+	if user.IsAdmin {
+		return true, nil
+	}
+	args := &db.UserHasPermissionParams{UserID: user.ID, Permission: permission}
+	return queries.UserHasPermission(ctx, *args)
+}
+
+// Checks that the user has the permission. Returns false if not, in which
+// case the attempt on target is logged with score and the error is already
+// pushed to gin.Context.
+func Authorize(
+	queries *db.Queries,
+	user *db.User,
+	permission string,
+	score logging.SecurityScore,
+	target string,
+	ctx *gin.Context,
+) bool {
+	allowed, err := Can(ctx, queries, user, permission)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to check permission", file, line, err, ctx)
+		return false
+	}
+	if !allowed {
+		logging.LogSecurityEvent(
+			score,
+			logging.SecurityEventForbiddenAction,
+			ctx.FullPath(),
+			target,
+			user.ID,
+		)
+		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+		return false
+	}
+	return true
+}
+
+// Returns whether the user may manage the target, which needs every
+// permission the target has. Otherwise managing the target, like setting
+// their email address and resetting their password, would gain the user the
+// target's permissions. Admins have every permission, so only users who may
+// make admins manage them.
+func CanManage(ctx context.Context, queries *db.Queries, user *db.User, target *db.User) (bool, error) {
+	if user.IsAdmin {
+		return true, nil
+	}
+	if target.IsAdmin {
+		return Can(ctx, queries, user, PermissionRolesManage)
+	}
+	args := &db.GetMissingPermissionsParams{TargetID: target.ID, UserID: user.ID}
+	missing, err := queries.GetMissingPermissions(ctx, *args)
+	if err != nil {
+		return false, fmt.Errorf("failed to get missing permissions: %w", err)
+	}
+	return len(missing) == 0, nil
+}
+
+// Checks that the user may manage the target, like Authorize.
+func AuthorizeManage(
+	queries *db.Queries,
+	user *db.User,
+	target *db.User,
+	score logging.SecurityScore,
+	ctx *gin.Context,
+) bool {
+	allowed, err := CanManage(ctx, queries, user, target)
+	if err != nil {
+		_, file, line, _ := runtime.Caller(0)
+		mycontext.CtxAddGtInternalError("failed to check permission", file, line, err, ctx)
+		return false
+	}
+	if !allowed {
+		logging.LogSecurityEvent(
+			score,
+			logging.SecurityEventForbiddenAction,
+			ctx.FullPath(),
+			target.ID,
+			user.ID,
+		)
+		ctx.Error(gterrors.ErrForbidden).SetType(gin.ErrorTypePublic)
+		return false
+	}
+	return true
+}
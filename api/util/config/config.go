@@ -16,6 +16,78 @@ type Config struct {
 	RefreshTokenLifeSpan int    `mapstructure:"REFRESH_TOKEN_LIFE_SPAN"`
 	JwtAccessSecret      string `mapstructure:"JWT_ACCESS_SECRET"`
 	JwtRefreshSecret     string `mapstructure:"JWT_REFRESH_SECRET"`
+	// HS512 signs with the secrets above, EdDSA and RS256 with keys stored in
+	// the database. Defaults to HS512.
+	JwtSigningAlgorithm string `mapstructure:"JWT_SIGNING_ALGORITHM"`
+	// Days between rotations of the EdDSA and RS256 signing keys. Defaults to
+	// 30.
+	JwtKeyRotationDays int `mapstructure:"JWT_KEY_ROTATION_DAYS"`
+	// OpenID Connect login is enabled when an issuer is set. The provider
+	// redirects back to OIDC_REDIRECT_URL with the code, which the client
+	// posts to /auth/oidc/callback.
+	OidcIssuer       string `mapstructure:"OIDC_ISSUER"`
+	OidcClientID     string `mapstructure:"OIDC_CLIENT_ID"`
+	OidcClientSecret string `mapstructure:"OIDC_CLIENT_SECRET"`
+	OidcRedirectURL  string `mapstructure:"OIDC_REDIRECT_URL"`
+	// Space separated, defaults to "openid profile email".
+	OidcScopes string `mapstructure:"OIDC_SCOPES"`
+	// The claim of the id token that lists the groups of the user. Defaults
+	// to groups.
+	OidcGroupsClaim string `mapstructure:"OIDC_GROUPS_CLAIM"`
+	// When set, is_admin of users logging in with OpenID Connect follows
+	// membership of this group.
+	OidcAdminGroup string `mapstructure:"OIDC_ADMIN_GROUP"`
+	// Emails are sent through this SMTP server, and not at all without a host.
+	SmtpHost     string `mapstructure:"SMTP_HOST"`
+	SmtpPort     int    `mapstructure:"SMTP_PORT"`
+	SmtpUsername string `mapstructure:"SMTP_USERNAME"`
+	SmtpPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	// The page of the client that resets passwords. Reset links are this url
+	// with the token as query param.
+	PasswordResetURL string `mapstructure:"PASSWORD_RESET_URL"`
+	// The page of the client that verifies email addresses, with the token as
+	// query param like PASSWORD_RESET_URL.
+	EmailVerificationURL string `mapstructure:"EMAIL_VERIFICATION_URL"`
+	// Who can create an account with POST /user: open, invite-only or closed.
+	// Defaults to open. The first user can always be created.
+	RegistrationMode string `mapstructure:"REGISTRATION_MODE"`
+	// New users need an email address and cannot log in before following the
+	// link sent to it.
+	RequireEmailVerification bool `mapstructure:"REQUIRE_EMAIL_VERIFICATION"`
+	// Failed logins of a username before each further one delays the next
+	// attempt, doubling from a second. Defaults to 3.
+	LoginBackoffThreshold int `mapstructure:"LOGIN_BACKOFF_THRESHOLD"`
+	// Failed logins of a username that lock it for LOGIN_LOCKOUT_MINUTES.
+	// Defaults to 10.
+	LoginLockoutThreshold int `mapstructure:"LOGIN_LOCKOUT_THRESHOLD"`
+	// The same for failed logins from an ip, to any username. Default to 20
+	// and 100.
+	LoginIpBackoffThreshold int `mapstructure:"LOGIN_IP_BACKOFF_THRESHOLD"`
+	LoginIpLockoutThreshold int `mapstructure:"LOGIN_IP_LOCKOUT_THRESHOLD"`
+	// Defaults to 15.
+	LoginLockoutMinutes int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+	// Failures are counted from zero again after this long without one.
+	// Defaults to 60.
+	LoginFailureWindowMinutes int `mapstructure:"LOGIN_FAILURE_WINDOW_MINUTES"`
+	// Comma separated ips or cidrs of reverse proxies whose X-Forwarded-For
+	// is trusted for the client ip. None are when empty, the client ip is
+	// then the address of the connection.
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+	// Security event scores are summed per violator over this many minutes.
+	// Defaults to 60.
+	SecurityScoreWindowMinutes int `mapstructure:"SECURITY_SCORE_WINDOW_MINUTES"`
+	// JSON list of rules that fire when a violator's score reaches their
+	// threshold, see util/risk. Uses the default rules when empty.
+	SecurityRules string `mapstructure:"SECURITY_RULES"`
+	// JSON list of log sinks and the sourcetypes they get, see logging.Sink.
+	// Logs everything to ./app.log when empty.
+	LogSinks string `mapstructure:"LOG_SINKS"`
+	// JSON list of rules that hash, truncate or drop fields of logged
+	// subjects, see logging.RedactionRule. Uses the default rules when empty.
+	LogRedaction string `mapstructure:"LOG_REDACTION"`
+	// Keys the hashes of redacted fields. Plain sha256 when empty.
+	LogRedactionKey string `mapstructure:"LOG_REDACTION_KEY"`
 }
 
 var globalConfig *Config
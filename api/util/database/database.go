@@ -1,12 +1,17 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	db "go-todo/db/sqlc"
 	"go-todo/gterrors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Gets the user for id from db and handles any errors with gin. Return user or
@@ -24,3 +29,58 @@ func GetUserById(db *db.Queries, id string, ctx *gin.Context) (*db.User, error)
 	}
 	return &user, nil
 }
+
+// Runs fn in a transaction with queries bound to it. The transaction is
+// committed if fn returns nil and rolled back otherwise.
+func WithTx(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	queries *db.Queries,
+	fn func(qtx *db.Queries) error,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Gets the preferences of the user, or the defaults if the user has not set
+// any.
+func GetUserPreferences(ctx context.Context, queries *db.Queries, userID string) (*db.UserPreference, error) {
+	preferences, err := queries.GetUserPreferences(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &db.UserPreference{
+			UserID:     userID,
+			TimeZone:   "UTC",
+			WeekStart:  1,
+			Locale:     "en-US",
+			DateFormat: "YYYY-MM-DD",
+		}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+	return &preferences, nil
+}
+
+// Gets the time zone the user has set, or UTC if there is none or it cannot
+// be loaded.
+func GetUserLocation(ctx context.Context, queries *db.Queries, userID string) (*time.Location, error) {
+	preferences, err := GetUserPreferences(ctx, queries, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(preferences.TimeZone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
@@ -0,0 +1,249 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	db "go-todo/db/sqlc"
+)
+
+const (
+	prodID         = "-//go-todo//go-todo//EN"
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	// RFC 5545 lines are folded after 75 octets.
+	maxLineLength = 75
+)
+
+// A todo as it is written to a calendar.
+type Todo struct {
+	UID         string
+	Summary     string
+	Description string
+	// The due time, or the due day if AllDay is set.
+	Due          *time.Time
+	AllDay       bool
+	Completed    bool
+	CompletedAt  *time.Time
+	Priority     int16
+	Categories   []string
+	RRule        string
+	RelatedTo    string
+	Duration     time.Duration
+	Created      time.Time
+	LastModified time.Time
+}
+
+func FromTodo(todo *db.Todo) *Todo {
+	t := &Todo{
+		UID:          todo.ID,
+		Summary:      todo.Title,
+		Description:  todo.Description.String,
+		Completed:    todo.Completed,
+		Priority:     todo.Priority,
+		Categories:   todo.Tags,
+		RRule:        todo.Recurrence.String,
+		RelatedTo:    todo.ParentID.String,
+		Created:      todo.CreatedAt.Time,
+		LastModified: todo.UpdatedAt.Time,
+	}
+	// Todos created by CalDAV clients keep the UID the client gave them.
+	if todo.IcalUid.Valid {
+		t.UID = todo.IcalUid.String
+	}
+	if todo.DueDate.Valid {
+		due := todo.DueDate.Time
+		t.Due = &due
+		t.AllDay = true
+	} else if todo.CompleteBefore.Valid {
+		due := todo.CompleteBefore.Time
+		t.Due = &due
+	}
+	if todo.CompletedAt.Valid {
+		completedAt := todo.CompletedAt.Time
+		t.CompletedAt = &completedAt
+	}
+	if todo.EstimatedDuration.Valid {
+		t.Duration = time.Duration(todo.EstimatedDuration.Int32) * time.Minute
+	}
+	return t
+}
+
+// Maps the priority of a todo, 0 (none) to 3 (high), to the iCalendar
+// priority, 0 (none) and 1 (high) to 9 (low).
+func icalPriority(priority int16) int {
+	switch priority {
+	case 1:
+		return 9
+	case 2:
+		return 5
+	case 3:
+		return 1
+	}
+	return 0
+}
+
+// Writes a VCALENDAR with a VTODO for every todo. With events set, todos with
+// a due time also get a VEVENT that ends when the todo is due.
+func WriteCalendar(w io.Writer, name string, todos []*Todo, events bool) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.begin()
+	e.line("X-WR-CALNAME", escape(name))
+	for _, todo := range todos {
+		e.todo(todo)
+		if events && todo.Due != nil {
+			e.event(todo)
+		}
+	}
+	e.line("END", "VCALENDAR")
+	return e.flush()
+}
+
+// Writes a VCALENDAR with only the VTODO of the todo, the way CalDAV serves a
+// single resource.
+func WriteTodo(w io.Writer, todo *Todo) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.begin()
+	e.todo(todo)
+	e.line("END", "VCALENDAR")
+	return e.flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) begin() {
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", prodID)
+	e.line("CALSCALE", "GREGORIAN")
+}
+
+func (e *encoder) todo(todo *Todo) {
+	e.line("BEGIN", "VTODO")
+	e.line("UID", escape(todo.UID))
+	e.line("DTSTAMP", todo.LastModified.UTC().Format(dateTimeFormat))
+	e.line("CREATED", todo.Created.UTC().Format(dateTimeFormat))
+	e.line("LAST-MODIFIED", todo.LastModified.UTC().Format(dateTimeFormat))
+	e.line("SUMMARY", escape(todo.Summary))
+	if todo.Description != "" {
+		e.line("DESCRIPTION", escape(todo.Description))
+	}
+	if todo.Due != nil {
+		// A recurrence needs a start to count from.
+		if todo.RRule != "" {
+			e.dateLine("DTSTART", *todo.Due, todo.AllDay)
+		}
+		e.dateLine("DUE", *todo.Due, todo.AllDay)
+	}
+	if todo.RRule != "" {
+		e.line("RRULE", todo.RRule)
+	}
+	if todo.Completed {
+		e.line("STATUS", "COMPLETED")
+		if todo.CompletedAt != nil {
+			e.line("COMPLETED", todo.CompletedAt.UTC().Format(dateTimeFormat))
+		}
+	} else {
+		e.line("STATUS", "NEEDS-ACTION")
+	}
+	if priority := icalPriority(todo.Priority); priority != 0 {
+		e.line("PRIORITY", fmt.Sprint(priority))
+	}
+	if len(todo.Categories) > 0 {
+		categories := make([]string, 0, len(todo.Categories))
+		for _, category := range todo.Categories {
+			categories = append(categories, escape(category))
+		}
+		e.line("CATEGORIES", strings.Join(categories, ","))
+	}
+	if todo.RelatedTo != "" {
+		e.line("RELATED-TO", escape(todo.RelatedTo))
+	}
+	e.line("END", "VTODO")
+}
+
+func (e *encoder) event(todo *Todo) {
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", escape(todo.UID+"-due"))
+	e.line("DTSTAMP", todo.LastModified.UTC().Format(dateTimeFormat))
+	e.line("SUMMARY", escape(todo.Summary))
+	if todo.Description != "" {
+		e.line("DESCRIPTION", escape(todo.Description))
+	}
+	if todo.AllDay {
+		e.dateLine("DTSTART", *todo.Due, true)
+		e.dateLine("DTEND", todo.Due.AddDate(0, 0, 1), true)
+	} else {
+		e.dateLine("DTSTART", todo.Due.Add(-todo.Duration), false)
+		e.dateLine("DTEND", *todo.Due, false)
+	}
+	if todo.RRule != "" {
+		e.line("RRULE", todo.RRule)
+	}
+	e.line("TRANSP", "TRANSPARENT")
+	e.line("RELATED-TO", escape(todo.UID))
+	e.line("END", "VEVENT")
+}
+
+func (e *encoder) dateLine(name string, t time.Time, allDay bool) {
+	if allDay {
+		e.line(name+";VALUE=DATE", t.Format(dateFormat))
+		return
+	}
+	e.line(name, t.UTC().Format(dateTimeFormat))
+}
+
+// Writes a content line, folded after every 75 octets without splitting a
+// character.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	line := name + ":" + value
+	var b strings.Builder
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the next line.
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	_, e.err = e.w.WriteString(b.String())
+}
+
+func (e *encoder) flush() error {
+	if e.err != nil {
+		return fmt.Errorf("failed to write calendar: %w", e.err)
+	}
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// Escapes a TEXT value.
+func escape(text string) string {
+	return escaper.Replace(text)
+}
@@ -0,0 +1,242 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		text    string
+		escaped string
+		// What unescaping gives back, the text if empty.
+		unescaped string
+	}{
+		{text: "plain", escaped: "plain"},
+		{text: "a;b,c", escaped: `a\;b\,c`},
+		{text: `back\slash`, escaped: `back\\slash`},
+		{text: "two\nlines", escaped: `two\nlines`},
+		{text: "windows\r\nlines", escaped: `windows\nlines`, unescaped: "windows\nlines"},
+		{text: `\n is no newline`, escaped: `\\n is no newline`},
+		{text: "ünïcödé ✓", escaped: "ünïcödé ✓"},
+		{text: "", escaped: ""},
+	}
+	for _, test := range tests {
+		if escaped := escape(test.text); escaped != test.escaped {
+			t.Errorf("escape(%q) = %q, want %q", test.text, escaped, test.escaped)
+		}
+		want := test.unescaped
+		if want == "" {
+			want = test.text
+		}
+		if unescaped := unescape(test.escaped); unescaped != want {
+			t.Errorf("unescape(%q) = %q, want %q", test.escaped, unescaped, want)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "one", want: []string{"one"}},
+		{value: "one,two", want: []string{"one", "two"}},
+		{value: `a\,b,c`, want: []string{"a,b", "c"}},
+		{value: `ends with\\,x`, want: []string{`ends with\`, "x"}},
+		{value: "", want: []string{""}},
+	}
+	for _, test := range tests {
+		if got := splitList(test.value); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitList(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestFoldRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+	}{
+		{name: "short", summary: "Short"},
+		{name: "exactly one line", summary: strings.Repeat("x", maxLineLength-len("SUMMARY:"))},
+		{name: "one octet over", summary: strings.Repeat("x", maxLineLength-len("SUMMARY:")+1)},
+		{name: "long ascii", summary: strings.Repeat("abcdefghij", 30)},
+		{name: "two byte runes", summary: strings.Repeat("ü", 100)},
+		{name: "four byte runes", summary: strings.Repeat("🙂", 60)},
+		{name: "escapes at fold", summary: strings.Repeat("a,b;c\\d\n", 20)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteTodo(&buf, &Todo{UID: "uid", Summary: test.summary}); err != nil {
+				t.Fatalf("WriteTodo failed: %v", err)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+				if len(line) > maxLineLength {
+					t.Errorf("line of %d octets: %q", len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line splits a character: %q", line)
+				}
+			}
+			todo, err := ParseTodo(&buf, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseTodo failed: %v", err)
+			}
+			if todo.Summary != test.summary {
+				t.Errorf("summary = %q, want %q", todo.Summary, test.summary)
+			}
+		})
+	}
+}
+
+func TestTodoRoundTrip(t *testing.T) {
+	due := time.Date(2025, time.January, 31, 16, 30, 0, 0, time.UTC)
+	completedAt := time.Date(2025, time.January, 30, 8, 0, 0, 0, time.UTC)
+	day := time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		todo Todo
+	}{
+		{
+			name: "every field",
+			todo: Todo{
+				UID:         "8c1f@example.com",
+				Summary:     "Pay rent; then relax, maybe",
+				Description: "Line one\nLine two with \\ backslash",
+				Due:         &due,
+				Completed:   true,
+				CompletedAt: &completedAt,
+				Priority:    3,
+				Categories:  []string{"home", "money, bills"},
+				RRule:       "FREQ=MONTHLY;BYMONTHDAY=1",
+				RelatedTo:   "parent-uid",
+			},
+		},
+		{name: "all day", todo: Todo{UID: "day", Summary: "Day", Due: &day, AllDay: true, Priority: 1}},
+		{name: "minimal", todo: Todo{UID: "min", Summary: "Only a title", Priority: 2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteTodo(&buf, &test.todo); err != nil {
+				t.Fatalf("WriteTodo failed: %v", err)
+			}
+			got, err := ParseTodo(&buf, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseTodo failed: %v", err)
+			}
+			if !reflect.DeepEqual(*got, test.todo) {
+				t.Errorf("round trip gave\n%+v\nwant\n%+v", *got, test.todo)
+			}
+		})
+	}
+}
+
+func TestParseTodo(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+	calendar := func(lines ...string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  *Todo
+		err   string
+	}{
+		{
+			name: "folded lines",
+			input: calendar(
+				"BEGIN:VTODO", "UID:1", "SUMMARY:Folded acr", " oss lines", "DESCRIPTION:tab\t", "\tfolded", "END:VTODO",
+			),
+			want: &Todo{UID: "1", Summary: "Folded across lines", Description: "tab\tfolded"},
+		},
+		{
+			name:  "bare line feeds",
+			input: "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:1\nSUMMARY:Unix\nEND:VTODO\nEND:VCALENDAR\n",
+			want:  &Todo{UID: "1", Summary: "Unix"},
+		},
+		{
+			name:  "utc due",
+			input: calendar("BEGIN:VTODO", "UID:1", "DUE:20250131T090000Z", "END:VTODO"),
+			want:  &Todo{UID: "1", Due: ptr(time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC))},
+		},
+		{
+			name:  "floating due is in loc",
+			input: calendar("BEGIN:VTODO", "UID:1", "DUE:20250131T090000", "END:VTODO"),
+			want:  &Todo{UID: "1", Due: ptr(time.Date(2025, time.January, 31, 8, 0, 0, 0, time.UTC))},
+		},
+		{
+			name:  "unknown tzid is in loc",
+			input: calendar("BEGIN:VTODO", "UID:1", `DUE;TZID="Custom: Zone":20250131T090000`, "END:VTODO"),
+			want:  &Todo{UID: "1", Due: ptr(time.Date(2025, time.January, 31, 8, 0, 0, 0, time.UTC))},
+		},
+		{
+			name:  "date",
+			input: calendar("BEGIN:VTODO", "UID:1", "DUE;VALUE=DATE:20250228", "END:VTODO"),
+			want:  &Todo{UID: "1", Due: ptr(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)), AllDay: true},
+		},
+		{
+			name: "status, priority and categories",
+			input: calendar(
+				"BEGIN:VTODO", "UID:1", "STATUS:completed", "PRIORITY:7", "CATEGORIES:a, b,,c", "CATEGORIES:d", "END:VTODO",
+			),
+			want: &Todo{UID: "1", Completed: true, Priority: 1, Categories: []string{"a", "b", "c", "d"}},
+		},
+		{
+			name: "only the parent is related",
+			input: calendar(
+				"BEGIN:VTODO", "UID:1", "RELATED-TO;RELTYPE=CHILD:child", "RELATED-TO;RELTYPE=PARENT:parent", "END:VTODO",
+			),
+			want: &Todo{UID: "1", RelatedTo: "parent"},
+		},
+		{
+			name: "nested components are skipped",
+			input: calendar(
+				"BEGIN:VTODO", "UID:1", "BEGIN:VALARM", "DESCRIPTION:alarm", "END:VALARM", "SUMMARY:Todo", "END:VTODO",
+			),
+			want: &Todo{UID: "1", Summary: "Todo"},
+		},
+		{
+			name:  "first todo only",
+			input: calendar("BEGIN:VTODO", "UID:1", "END:VTODO", "BEGIN:VTODO", "UID:2", "END:VTODO"),
+			want:  &Todo{UID: "1"},
+		},
+		{name: "no todo", input: calendar("BEGIN:VEVENT", "UID:1", "END:VEVENT"), err: ErrNoTodo.Error()},
+		{name: "no uid", input: calendar("BEGIN:VTODO", "SUMMARY:x", "END:VTODO"), err: "VTODO has no UID"},
+		{name: "not closed", input: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1\r\n", err: "VTODO is not closed"},
+		{name: "no colon", input: calendar("BEGIN:VTODO", "UID", "END:VTODO"), err: "invalid content line"},
+		{name: "invalid due", input: calendar("BEGIN:VTODO", "UID:1", "DUE:tomorrow", "END:VTODO"), err: "invalid DUE"},
+		{name: "invalid priority", input: calendar("BEGIN:VTODO", "UID:1", "PRIORITY:high", "END:VTODO"), err: "invalid PRIORITY"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseTodo(strings.NewReader(test.input), berlin)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTodo failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, test.want)
+			}
+		})
+	}
+	if _, err := ParseTodo(strings.NewReader(""), time.UTC); !errors.Is(err, ErrNoTodo) {
+		t.Errorf("empty calendar error = %v, want ErrNoTodo", err)
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
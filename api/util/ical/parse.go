@@ -0,0 +1,240 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoTodo = errors.New("calendar has no VTODO")
+
+// A content line split into its name, parameters and raw value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Reads the first VTODO of a VCALENDAR. Times without a time zone, or with a
+// TZID that is not an IANA name, are read in loc. VTIMEZONE definitions are
+// not interpreted. Properties of nested components like VALARM are skipped.
+func ParseTodo(r io.Reader, loc *time.Location) (*Todo, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var todo *Todo
+	// Depth of components nested in the VTODO.
+	depth := 0
+	for _, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if todo == nil {
+			if prop.name == "BEGIN" && strings.EqualFold(prop.value, "VTODO") {
+				todo = &Todo{}
+			}
+			continue
+		}
+		switch {
+		case prop.name == "BEGIN":
+			depth++
+			continue
+		case prop.name == "END" && depth > 0:
+			depth--
+			continue
+		case prop.name == "END":
+			if todo.UID == "" {
+				return nil, errors.New("VTODO has no UID")
+			}
+			return todo, nil
+		case depth > 0:
+			continue
+		}
+
+		if err := todo.setProperty(prop, loc); err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", prop.name, err)
+		}
+	}
+	if todo != nil {
+		return nil, errors.New("VTODO is not closed")
+	}
+	return nil, ErrNoTodo
+}
+
+func (todo *Todo) setProperty(prop *property, loc *time.Location) error {
+	switch prop.name {
+	case "UID":
+		todo.UID = unescape(prop.value)
+	case "SUMMARY":
+		todo.Summary = unescape(prop.value)
+	case "DESCRIPTION":
+		todo.Description = unescape(prop.value)
+	case "DUE":
+		due, allDay, err := parseDate(prop, loc)
+		if err != nil {
+			return err
+		}
+		todo.Due = &due
+		todo.AllDay = allDay
+	case "STATUS":
+		todo.Completed = strings.EqualFold(prop.value, "COMPLETED")
+	case "COMPLETED":
+		completedAt, _, err := parseDate(prop, loc)
+		if err != nil {
+			return err
+		}
+		todo.CompletedAt = &completedAt
+	case "PRIORITY":
+		priority, err := strconv.Atoi(prop.value)
+		if err != nil {
+			return err
+		}
+		todo.Priority = todoPriority(priority)
+	case "CATEGORIES":
+		for _, category := range splitList(prop.value) {
+			if category = strings.TrimSpace(category); category != "" {
+				todo.Categories = append(todo.Categories, category)
+			}
+		}
+	case "RRULE":
+		todo.RRule = prop.value
+	case "RELATED-TO":
+		// Only the parent is kept, like todos only know their parent.
+		if relType, ok := prop.params["RELTYPE"]; !ok || strings.EqualFold(relType, "PARENT") {
+			todo.RelatedTo = unescape(prop.value)
+		}
+	}
+	return nil
+}
+
+// Maps the iCalendar priority, 0 (none) and 1 (high) to 9 (low), to the
+// priority of a todo, 0 (none) to 3 (high).
+func todoPriority(priority int) int16 {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return 3
+	case priority == 5:
+		return 2
+	case priority >= 6 && priority <= 9:
+		return 1
+	}
+	return 0
+}
+
+// Reads a DATE or DATE-TIME value. Dates are returned as midnight UTC.
+func parseDate(prop *property, loc *time.Location) (time.Time, bool, error) {
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(prop.value) == len(dateFormat) {
+		date, err := time.Parse(dateFormat, prop.value)
+		return date, true, err
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		t, err := time.Parse(dateTimeFormat, prop.value)
+		return t, false, err
+	}
+	if tzid, ok := prop.params["TZID"]; ok {
+		if tz, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation(strings.TrimSuffix(dateTimeFormat, "Z"), prop.value, loc)
+	return t.UTC(), false, err
+}
+
+// Reads the content lines and joins the folded ones.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// Splits a content line like DUE;TZID=Europe/Berlin:20250131T090000. Colons
+// and semicolons in quoted parameter values do not count.
+func parseLine(line string) (*property, error) {
+	prop := &property{params: map[string]string{}}
+	quoted := false
+	start := 0
+	var param string
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == ';' || c == ':':
+			part := line[start:i]
+			if prop.name == "" {
+				prop.name = strings.ToUpper(part)
+			} else if param != "" {
+				prop.params[param] = strings.Trim(part, `"`)
+			}
+			param = ""
+			start = i + 1
+			if c == ':' {
+				prop.value = line[start:]
+				return prop, nil
+			}
+		case c == '=' && param == "" && prop.name != "":
+			param = strings.ToUpper(line[start:i])
+			start = i + 1
+		}
+	}
+	return nil, fmt.Errorf("invalid content line: %q", line)
+}
+
+// Splits a list of TEXT values on the commas that are not escaped and
+// unescapes the values.
+func splitList(value string) []string {
+	values := []string{}
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescape(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescape(value[start:]))
+}
+
+// Reverses escape.
+func unescape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"go-todo/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS512 = "HS512"
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+var ErrNoSigningKey = errors.New("no active jwt signing key")
+
+// A key for signing jwts asymmetrically, identified by the kid header.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	Private    crypto.Signer
+	Public     crypto.PublicKey
+	ActiveFrom time.Time
+}
+
+// The keys in use, newest first. Replaced as a whole by SetKeys.
+var keys struct {
+	sync.RWMutex
+	list []*SigningKey
+}
+
+// Replaces the keys jwts are signed and verified with. Expects them ordered
+// newest first.
+func SetKeys(signingKeys []*SigningKey) {
+	keys.Lock()
+	defer keys.Unlock()
+	keys.list = signingKeys
+}
+
+// Returns the configured signing algorithm, HS512 if none is.
+func SigningAlgorithm() (string, error) {
+	config, err := config.Get()
+	if err != nil {
+		return "", err
+	}
+	switch config.JwtSigningAlgorithm {
+	case "", AlgorithmHS512:
+		return AlgorithmHS512, nil
+	case AlgorithmEdDSA, AlgorithmRS256:
+		return config.JwtSigningAlgorithm, nil
+	}
+	return "", fmt.Errorf("unsupported jwt signing algorithm %v", config.JwtSigningAlgorithm)
+}
+
+func signingMethod(algorithm string) jwt.SigningMethod {
+	if algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// Returns the newest key that is already active.
+func currentSigningKey() (*SigningKey, error) {
+	keys.RLock()
+	defer keys.RUnlock()
+	now := time.Now()
+	for _, key := range keys.list {
+		if !key.ActiveFrom.After(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+func verificationKey(kid string) *SigningKey {
+	keys.RLock()
+	defer keys.RUnlock()
+	for _, key := range keys.list {
+		if key.ID == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// Returns the public keys as a JSON Web Key Set, including keys that are not
+// active yet, so that verifiers know them before they sign anything.
+func JWKS() map[string]any {
+	keys.RLock()
+	defer keys.RUnlock()
+	jwks := make([]map[string]any, 0, len(keys.list))
+	for _, key := range keys.list {
+		jwk := map[string]any{"kid": key.ID, "alg": key.Algorithm, "use": "sig"}
+		switch public := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+	return map[string]any{"keys": jwks}
+}
@@ -0,0 +1,29 @@
+package jwt
+
+import "slices"
+
+// Scopes limit what a token is allowed to do. Tokens from login have every
+// scope, personal access tokens the ones chosen when creating them.
+const (
+	// Reading lists, todos, templates, time entries and the own account.
+	ScopeListsRead = "lists:read"
+	// Creating, updating, archiving and deleting lists, templates and folders.
+	ScopeListsWrite = "lists:write"
+	// Creating, updating and deleting todos and tracking time.
+	ScopeTodosWrite = "todos:write"
+	// Changing and deleting user accounts and their credentials, like
+	// passwords, app passwords, personal access tokens and calendar feeds.
+	ScopeUsersAdmin = "users:admin"
+)
+
+var AllScopes = []string{ScopeListsRead, ScopeListsWrite, ScopeTodosWrite, ScopeUsersAdmin}
+
+// Returns the first scope missing from scopes, or "" if none is.
+func MissingScope(scopes []string, required ...string) string {
+	for _, scope := range required {
+		if !slices.Contains(scopes, scope) {
+			return scope
+		}
+	}
+	return ""
+}
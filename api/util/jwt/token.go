@@ -13,9 +13,18 @@ import (
 )
 
 type GtClaims struct {
-	IsAdmin  bool   `json:"is_admin"`
-	Username string `json:"username"`
-	Family   string `json:"family"`
+	IsAdmin  bool     `json:"is_admin"`
+	Username string   `json:"username"`
+	Family   string   `json:"family"`
+	Scopes   []string `json:"scopes"`
+	// Family of the refresh tokens an access token was issued with, which
+	// identifies the session of the user.
+	Session string `json:"sid,omitempty"`
+	// Lists the token is limited to, every list the user can access if empty.
+	ListIDs []string `json:"list_ids,omitempty"`
+	// Token epoch of the user an access token was issued in, see package
+	// revocation.
+	Epoch int32 `json:"epoch,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -61,7 +70,15 @@ func NewJwtDecodeError(claims *GtClaims, reason JwtErrorReason, err error) *JwtD
 	}
 }
 
-func generateJwt(username string, userID string, isAdmin bool, isRefreshToken bool, family string) (string, *GtClaims, error) {
+func generateJwt(
+	username string,
+	userID string,
+	isAdmin bool,
+	isRefreshToken bool,
+	family string,
+	session string,
+	epoch int32,
+) (string, *GtClaims, error) {
 	generateError := func(err error) error {
 		return fmt.Errorf("GenerateJwtError: %w", err)
 	}
@@ -84,10 +101,13 @@ func generateJwt(username string, userID string, isAdmin bool, isRefreshToken bo
 		family = "access"
 	}
 	claims := GtClaims{
-		isAdmin,
-		username,
-		family,
-		jwt.RegisteredClaims{
+		IsAdmin:  isAdmin,
+		Username: username,
+		Family:   family,
+		Scopes:   AllScopes,
+		Session:  session,
+		Epoch:    epoch,
+		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.New().String(),
 			Subject:   userID,
 			ExpiresAt: expiry,
@@ -95,25 +115,47 @@ func generateJwt(username string, userID string, isAdmin bool, isRefreshToken bo
 			IssuedAt:  jwt.NewNumericDate(timeNow),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	algorithm, err := SigningAlgorithm()
+	if err != nil {
+		return "", nil, generateError(err)
+	}
 
-	secret := config.JwtAccessSecret
-	if isRefreshToken {
-		secret = config.JwtRefreshSecret
+	var encodedToken string
+	if algorithm == AlgorithmHS512 {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+		secret := config.JwtAccessSecret
+		if isRefreshToken {
+			secret = config.JwtRefreshSecret
+		}
+		encodedToken, err = token.SignedString([]byte(secret))
+	} else {
+		key, keyErr := currentSigningKey()
+		if keyErr != nil {
+			return "", nil, generateError(keyErr)
+		}
+		token := jwt.NewWithClaims(signingMethod(key.Algorithm), claims)
+		token.Header["kid"] = key.ID
+		// Tells access jwts apart for services verifying them with the JWKS,
+		// as both kinds are signed with the same key (RFC 9068).
+		if !isRefreshToken {
+			token.Header["typ"] = "at+jwt"
+		}
+		encodedToken, err = token.SignedString(key.Private)
 	}
-	encodedToken, err := token.SignedString([]byte(secret))
 	if err != nil {
 		return "", nil, generateError(err)
 	}
 	return encodedToken, &claims, nil
 }
 
-func GenerateAccessJwt(username string, userID string, isAdmin bool) (string, *GtClaims, error) {
-	return generateJwt(username, userID, isAdmin, false, "")
+// Generates an access jwt for the session, which is the family of the refresh
+// jwt it is issued with, in the current token epoch of the user.
+func GenerateAccessJwt(username string, userID string, isAdmin bool, session string, epoch int32) (string, *GtClaims, error) {
+	return generateJwt(username, userID, isAdmin, false, "", session, epoch)
 }
 
 func GenerateRefreshJwt(username string, userID string, isAdmin bool, tokenFamily string) (string, *GtClaims, error) {
-	return generateJwt(username, userID, isAdmin, true, tokenFamily)
+	return generateJwt(username, userID, isAdmin, true, tokenFamily, "", 0)
 }
 
 // Takes a jwt as a string and boolean isRefreshToken telling should it be
@@ -129,9 +171,27 @@ func decodeJwt(tokenString string, isRefreshToken bool) (*GtClaims, error) {
 	if isRefreshToken {
 		secret = config.JwtRefreshSecret
 	}
-	decodedToken, err := jwt.ParseWithClaims(tokenString, &GtClaims{}, func(token *jwt.Token) (any, error) {
-		return []byte(secret), nil
-	})
+	decodedToken, err := jwt.ParseWithClaims(
+		tokenString,
+		&GtClaims{},
+		func(token *jwt.Token) (any, error) {
+			kid, ok := token.Header["kid"].(string)
+			if !ok {
+				// Jwts signed with the secrets, which stay valid after
+				// switching to keys until they expire.
+				if token.Method != jwt.SigningMethodHS512 || secret == "" {
+					return nil, jwt.ErrSignatureInvalid
+				}
+				return []byte(secret), nil
+			}
+			key := verificationKey(kid)
+			if key == nil || token.Method != signingMethod(key.Algorithm) {
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return key.Public, nil
+		},
+		jwt.WithValidMethods([]string{AlgorithmHS512, AlgorithmEdDSA, AlgorithmRS256}),
+	)
 	if err != nil {
 		if decodedToken == nil {
 			reason := JwtErrorReasonUnhandled
@@ -155,12 +215,48 @@ func decodeJwt(tokenString string, isRefreshToken bool) (*GtClaims, error) {
 		}
 		return nil, NewJwtDecodeError(nil, JwtErrorReasonUnhandled, err)
 	} else if claims, ok := decodedToken.Claims.(*GtClaims); ok {
+		// Access and refresh jwts signed with the same key are only told apart
+		// by their family.
+		if (claims.Family == "access") == isRefreshToken {
+			return nil, NewJwtDecodeError(claims, JwtErrorReasonTokenMalformed, errors.New("wrong kind of jwt"))
+		}
 		return claims, nil
 	} else {
 		return nil, NewJwtDecodeError(nil, JwtErrorReasonUnhandled, err)
 	}
 }
 
+// Claims standing in for a personal access token, so that its uses are logged
+// like those of jwts. The family is "personal".
+func PersonalAccessTokenClaims(
+	tokenID string,
+	username string,
+	userID string,
+	isAdmin bool,
+	scopes []string,
+	listIDs []string,
+	issuedAt time.Time,
+	expiresAt *time.Time,
+) *GtClaims {
+	claims := &GtClaims{
+		IsAdmin:  isAdmin,
+		Username: username,
+		Family:   "personal",
+		Scopes:   scopes,
+		ListIDs:  listIDs,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       tokenID,
+			Subject:  userID,
+			Issuer:   "GO-TODO",
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+	if expiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*expiresAt)
+	}
+	return claims
+}
+
 func DecodeAccessToken(tokenString string) (*GtClaims, error) {
 	return decodeJwt(tokenString, false)
 }
@@ -169,7 +265,11 @@ func DecodeRefreshToken(tokenString string) (*GtClaims, error) {
 	return decodeJwt(tokenString, true)
 }
 
-func getTokenFromHeader(c *gin.Context) string {
+// Personal access tokens start with this prefix, which tells them apart from
+// jwts in the Authorization header.
+const PersonalAccessTokenPrefix = "gtp_"
+
+func GetTokenFromHeader(c *gin.Context) string {
 	bearerToken := c.Request.Header.Get("Authorization")
 
 	splitToken := strings.Split(bearerToken, " ")
@@ -180,6 +280,6 @@ func getTokenFromHeader(c *gin.Context) string {
 }
 
 func DecodeTokenFromHeader(c *gin.Context) (*GtClaims, error) {
-	tokenString := getTokenFromHeader(c)
+	tokenString := GetTokenFromHeader(c)
 	return DecodeAccessToken(tokenString)
 }
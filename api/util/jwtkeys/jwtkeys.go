@@ -0,0 +1,192 @@
+// Package jwtkeys manages the keys jwts are signed with when the configured
+// algorithm is EdDSA or RS256. Keys are stored in the database, so that every
+// instance of the api signs with the same key, and rotated on a schedule. A
+// rotated key keeps verifying until every jwt signed with it has expired.
+package jwtkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/config"
+	"go-todo/util/database"
+	"go-todo/util/jwt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// How often the keys are reloaded from the database.
+	reloadInterval = time.Minute
+	// How long a new key is only published before it signs. Longer than the
+	// reload interval, so that every instance verifies with it by then.
+	activationDelay     = 3 * reloadInterval
+	defaultRotationDays = 30
+)
+
+// Loads the keys and keeps them rotated and reloaded until ctx is done. Does
+// nothing when jwts are signed with HS512.
+func Start(ctx context.Context, pool *pgxpool.Pool, queries *db.Queries) error {
+	algorithm, err := jwt.SigningAlgorithm()
+	if err != nil {
+		return err
+	} else if algorithm == jwt.AlgorithmHS512 {
+		return nil
+	}
+	if err := Rotate(ctx, pool, queries, algorithm); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := Rotate(ctx, pool, queries, algorithm); err != nil {
+					_, file, line, _ := runtime.Caller(0)
+					logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "Failed to rotate jwt signing keys.")
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Creates a new key when there is none for the algorithm or the newest one is
+// due for rotation, then hands every key still verifying to the jwt package.
+func Rotate(ctx context.Context, pool *pgxpool.Pool, queries *db.Queries, algorithm string) error {
+	config, err := config.Get()
+	if err != nil {
+		return err
+	}
+	rotationDays := config.JwtKeyRotationDays
+	if rotationDays <= 0 {
+		rotationDays = defaultRotationDays
+	}
+	rotation := time.Hour * 24 * time.Duration(rotationDays)
+	refreshLifeSpan := time.Minute * time.Duration(config.RefreshTokenLifeSpan)
+
+	var rows []db.JwtSigningKey
+	err = database.WithTx(ctx, pool, queries, func(qtx *db.Queries) error {
+		if err := qtx.LockJwtSigningKeys(ctx); err != nil {
+			return fmt.Errorf("failed to lock jwt signing keys: %w", err)
+		}
+		if err := qtx.DeleteExpiredJwtSigningKeys(ctx); err != nil {
+			return fmt.Errorf("failed to delete expired jwt signing keys: %w", err)
+		}
+		if rows, err = qtx.GetJwtSigningKeys(ctx); err != nil {
+			return fmt.Errorf("failed to get jwt signing keys: %w", err)
+		}
+
+		now := time.Now().UTC()
+		activeFrom := now.Add(activationDelay)
+		if len(rows) == 0 {
+			// Nothing can have been signed yet, so the first key is used at once.
+			activeFrom = now
+		} else if newest := rows[0]; newest.Algorithm == algorithm && newest.ActiveFrom.Time.Add(rotation).After(now) {
+			return nil
+		}
+
+		key, err := create(ctx, qtx, algorithm, activeFrom)
+		if err != nil {
+			return err
+		}
+		// The older keys sign until the new one is active, and verify until the
+		// jwts they signed have expired.
+		args := &db.RetireJwtSigningKeysParams{
+			VerifyUntil: pgtype.Timestamptz{Time: activeFrom.Add(refreshLifeSpan), Valid: true},
+			Kid:         key.Kid,
+		}
+		if err := qtx.RetireJwtSigningKeys(ctx, *args); err != nil {
+			return fmt.Errorf("failed to retire jwt signing keys: %w", err)
+		}
+		slog.Info("Created jwt signing key.", "kid", key.Kid, "active_from", activeFrom)
+		rows = append([]db.JwtSigningKey{*key}, rows...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*jwt.SigningKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := parse(&row)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	jwt.SetKeys(keys)
+	return nil
+}
+
+func create(ctx context.Context, queries *db.Queries, algorithm string, activeFrom time.Time) (*db.JwtSigningKey, error) {
+	var private crypto.Signer
+	var err error
+	if algorithm == jwt.AlgorithmRS256 {
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	} else {
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate jwt signing key: %w", err)
+	}
+	privateDer, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal jwt signing key: %w", err)
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal jwt signing key: %w", err)
+	}
+
+	args := &db.CreateJwtSigningKeyParams{
+		Kid:        uuid.New().String(),
+		Algorithm:  algorithm,
+		PrivateKey: privateDer,
+		PublicKey:  publicDer,
+		ActiveFrom: pgtype.Timestamptz{Time: activeFrom, Valid: true},
+	}
+	key, err := queries.CreateJwtSigningKey(ctx, *args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save jwt signing key: %w", err)
+	}
+	return &key, nil
+}
+
+func parse(row *db.JwtSigningKey) (*jwt.SigningKey, error) {
+	private, err := x509.ParsePKCS8PrivateKey(row.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt signing key %v: %w", row.Kid, err)
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwt signing key %v cannot sign", row.Kid)
+	}
+	public, err := x509.ParsePKIXPublicKey(row.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt signing key %v: %w", row.Kid, err)
+	}
+	return &jwt.SigningKey{
+		ID:         row.Kid,
+		Algorithm:  row.Algorithm,
+		Private:    signer,
+		Public:     public,
+		ActiveFrom: row.ActiveFrom.Time,
+	}, nil
+}
@@ -0,0 +1,174 @@
+// Package lockout slows down and then locks logins after failed attempts, per
+// username and per ip. The counters are kept in the database, so that every
+// instance of the api shares them.
+//
+// After the backoff threshold each failure locks for twice as long as the one
+// before, starting at a second. At the lockout threshold the lock lasts for
+// the lockout duration, until it expires or an admin unlocks it.
+//
+// A locked username also rejects the app passwords and personal access tokens
+// of the user, so that locking an account keeps it from being used.
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/util/config"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultBackoffThreshold   = 3
+	defaultLockoutThreshold   = 10
+	defaultIpBackoffThreshold = 20
+	defaultIpLockoutThreshold = 100
+	defaultLockoutMinutes     = 15
+	defaultWindowMinutes      = 60
+	backoffBase               = time.Second
+)
+
+type thresholds struct {
+	backoff int
+	lockout int
+}
+
+type settings struct {
+	username thresholds
+	ip       thresholds
+	lockout  time.Duration
+	window   time.Duration
+}
+
+// Returns the key the failures of the username are counted under.
+func UsernameKey(username string) string {
+	return "username:" + username
+}
+
+// Returns the key the failures from the ip are counted under.
+func IpKey(ip string) string {
+	return "ip:" + ip
+}
+
+func orDefault(value int, defaultValue int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func loadSettings() (*settings, error) {
+	config, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &settings{
+		username: thresholds{
+			backoff: orDefault(config.LoginBackoffThreshold, defaultBackoffThreshold),
+			lockout: orDefault(config.LoginLockoutThreshold, defaultLockoutThreshold),
+		},
+		ip: thresholds{
+			backoff: orDefault(config.LoginIpBackoffThreshold, defaultIpBackoffThreshold),
+			lockout: orDefault(config.LoginIpLockoutThreshold, defaultIpLockoutThreshold),
+		},
+		lockout: time.Duration(orDefault(config.LoginLockoutMinutes, defaultLockoutMinutes)) * time.Minute,
+		window:  time.Duration(orDefault(config.LoginFailureWindowMinutes, defaultWindowMinutes)) * time.Minute,
+	}, nil
+}
+
+// Returns how long the key is locked after the failure.
+func (s *settings) lockFor(failures int, limits thresholds) time.Duration {
+	if failures >= limits.lockout {
+		return s.lockout
+	}
+	if failures < limits.backoff {
+		return 0
+	}
+	delay := backoffBase
+	for range failures - limits.backoff {
+		delay *= 2
+		if delay >= s.lockout {
+			return s.lockout
+		}
+	}
+	return delay
+}
+
+// Returns until when logging in as the username or from the ip is locked, or
+// the zero time if it is not.
+func LockedUntil(ctx context.Context, queries *db.Queries, username string, ip string) (time.Time, error) {
+	return lockedUntil(ctx, queries, UsernameKey(username), IpKey(ip))
+}
+
+// Returns until when the username is locked, or the zero time if it is not.
+// For tokens, which are not guessed like passwords, so locks of ips are left
+// out.
+func UsernameLockedUntil(ctx context.Context, queries *db.Queries, username string) (time.Time, error) {
+	return lockedUntil(ctx, queries, UsernameKey(username))
+}
+
+func lockedUntil(ctx context.Context, queries *db.Queries, keys ...string) (time.Time, error) {
+	locks, err := queries.GetLoginLocks(ctx, keys)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get login locks: %w", err)
+	}
+	var until time.Time
+	for _, lock := range locks {
+		if lock.LockedUntil.Time.After(until) {
+			until = lock.LockedUntil.Time
+		}
+	}
+	return until, nil
+}
+
+// Counts a failed login as the username from the ip and locks them when they
+// pass the thresholds. Returns the keys that reached the lockout threshold
+// with this failure.
+func RecordFailure(ctx context.Context, queries *db.Queries, username string, ip string) ([]string, error) {
+	settings, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	resetBefore := pgtype.Timestamptz{Time: now.Add(-settings.window), Valid: true}
+	if err := queries.DeleteStaleLoginFailures(ctx, resetBefore); err != nil {
+		return nil, fmt.Errorf("failed to delete stale login failures: %w", err)
+	}
+
+	lockedOut := []string{}
+	for key, limits := range map[string]thresholds{UsernameKey(username): settings.username, IpKey(ip): settings.ip} {
+		args := &db.RecordLoginFailureParams{Key: key, ResetBefore: resetBefore}
+		failure, err := queries.RecordLoginFailure(ctx, *args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record login failure: %w", err)
+		}
+		lockFor := settings.lockFor(int(failure.Failures), limits)
+		if lockFor == 0 {
+			continue
+		}
+		lockArgs := &db.LockLoginKeyParams{
+			Key:         key,
+			LockedUntil: pgtype.Timestamptz{Time: now.Add(lockFor), Valid: true},
+		}
+		if err := queries.LockLoginKey(ctx, *lockArgs); err != nil {
+			return nil, fmt.Errorf("failed to lock login: %w", err)
+		}
+		if int(failure.Failures) == limits.lockout {
+			lockedOut = append(lockedOut, key)
+		}
+	}
+	return lockedOut, nil
+}
+
+// Clears the failures of the username after a successful login. Failures
+// from the ip are kept, so that logging into one account does not reset the
+// count for guessing others.
+func Reset(ctx context.Context, queries *db.Queries, username string) error {
+	if _, err := queries.DeleteLoginFailures(ctx, []string{UsernameKey(username)}); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Package mail sends plain text emails through the configured SMTP server.
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-todo/util/config"
+)
+
+const defaultPort = 587
+
+// Returned by Send when no SMTP host is configured.
+var ErrDisabled = errors.New("sending email is not configured")
+
+// Sends the email. net/smtp upgrades to TLS when the server supports it.
+func Send(to string, subject string, body string) error {
+	config, err := config.Get()
+	if err != nil {
+		return err
+	}
+	if config.SmtpHost == "" {
+		return ErrDisabled
+	}
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return errors.New("email header contains a line break")
+	}
+	port := config.SmtpPort
+	if port == 0 {
+		port = defaultPort
+	}
+
+	var auth smtp.Auth
+	if config.SmtpUsername != "" {
+		auth = smtp.PlainAuth("", config.SmtpUsername, config.SmtpPassword, config.SmtpHost)
+	}
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %v\r\n", config.MailFrom)
+	fmt.Fprintf(&message, "To: %v\r\n", to)
+	fmt.Fprintf(&message, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(config.SmtpHost, strconv.Itoa(port))
+	if err := smtp.SendMail(addr, auth, config.MailFrom, []string{to}, []byte(message.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
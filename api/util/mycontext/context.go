@@ -3,6 +3,7 @@ package mycontext
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"go-todo/gterrors"
 	"go-todo/util/txtutil"
@@ -35,6 +36,20 @@ func GetTokenVariables(ctx *gin.Context) (userID string, username string, isAdmi
 	return userID, username, isAdmin, nil
 }
 
+// Returns the scopes of the token and the lists it is limited to. listIDs is
+// empty if the token can access every list of the user.
+func GetTokenScopes(ctx *gin.Context) (scopes []string, listIDs []string) {
+	scopes = ctx.GetStringSlice("x-token-scopes")
+	listIDs = ctx.GetStringSlice("x-token-list-ids")
+	return scopes, listIDs
+}
+
+// Returns the session the access jwt belongs to, or "" if the request is not
+// authenticated with one, like with a personal access token.
+func GetTokenSession(ctx *gin.Context) string {
+	return ctx.GetString("x-token-session")
+}
+
 func CtxAddGtInternalError(message, file string, line int, err error, c *gin.Context) {
 	errToAdd := err
 	if message != "" {
@@ -58,3 +73,41 @@ func ShouldBindBodyWithJSON(payload any, c *gin.Context) bool {
 	}
 	return true
 }
+
+// Parses the optional 'from' and 'to' query params as a time range. Both accept
+// either a date (2006-01-02) or RFC3339 timestamp. Dates are days in loc, the
+// time zone of the requester, and a date given as 'to' covers the whole day.
+// Defaults to the Unix epoch and the current time. Returns false if parsing
+// fails, in which case the error is already pushed to gin.Context.
+func GetTimeRangeQuery(c *gin.Context, loc *time.Location) (from time.Time, to time.Time, ok bool) {
+	parse := func(key string, isEnd bool, fallback time.Time) (time.Time, bool) {
+		value := c.Query(key)
+		if value == "" {
+			return fallback, true
+		}
+		if date, err := time.ParseInLocation(time.DateOnly, value, loc); err == nil {
+			if isEnd {
+				date = date.AddDate(0, 0, 1)
+			}
+			return date.UTC(), true
+		}
+		timestamp, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.Error(gterrors.NewGtValueError(value, fmt.Sprintf("'%v' is not a date", key)))
+			return time.Time{}, false
+		}
+		return timestamp.UTC(), true
+	}
+
+	if from, ok = parse("from", false, time.Unix(0, 0).UTC()); !ok {
+		return
+	}
+	if to, ok = parse("to", true, time.Now().UTC()); !ok {
+		return
+	}
+	if !from.Before(to) {
+		c.Error(gterrors.NewGtValueError(c.Query("to"), "'to' must be after 'from'"))
+		return from, to, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,309 @@
+// Package oidc logs users in with an OpenID Connect provider, using the
+// authorization code flow with PKCE. The provider is discovered from the
+// configured issuer on first use, so that the api starts while it is down.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-todo/util/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultScopes      = "openid profile email"
+	defaultGroupsClaim = "groups"
+	// How long a failed discovery is remembered before it is tried again.
+	discoveryRetryInterval = 30 * time.Second
+	maxResponseSize        = 1 << 20
+)
+
+// Returned by Get when no issuer is configured.
+var ErrDisabled = errors.New("oidc login is not configured")
+
+// The provider refused the code or sent an id token that does not verify.
+// Anything else that fails is an error on our or the provider's side.
+var ErrRejected = errors.New("oidc login rejected")
+
+// The user as the provider knows them.
+type Identity struct {
+	Issuer            string
+	Subject           string
+	PreferredUsername string
+	Email             string
+	Groups            []string
+}
+
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       string
+	groupsClaim  string
+	adminGroup   string
+	client       *http.Client
+
+	mu           sync.Mutex
+	metadata     *metadata
+	discoveredAt time.Time
+	keys         *keySet
+}
+
+// The part of the provider's discovery document that is used.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+var provider struct {
+	sync.Mutex
+	instance *Provider
+}
+
+// Returns the provider from the config, or ErrDisabled if there is none.
+func Get() (*Provider, error) {
+	provider.Lock()
+	defer provider.Unlock()
+	if provider.instance != nil {
+		return provider.instance, nil
+	}
+
+	config, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+	if config.OidcIssuer == "" {
+		return nil, ErrDisabled
+	}
+	scopes := config.OidcScopes
+	if scopes == "" {
+		scopes = defaultScopes
+	}
+	groupsClaim := config.OidcGroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultGroupsClaim
+	}
+	provider.instance = &Provider{
+		issuer:       strings.TrimSuffix(config.OidcIssuer, "/"),
+		clientID:     config.OidcClientID,
+		clientSecret: config.OidcClientSecret,
+		redirectURL:  config.OidcRedirectURL,
+		scopes:       scopes,
+		groupsClaim:  groupsClaim,
+		adminGroup:   config.OidcAdminGroup,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+	return provider.instance, nil
+}
+
+// Returns a random code verifier for PKCE.
+func GenerateVerifier() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// Returns the S256 code challenge of the verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Returns the url of the provider's login page. The provider sends the user
+// back to the redirect url with a code and the state.
+func (p *Provider) AuthorizationURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.clientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", p.scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchanges the code for an id token and verifies it. nonce and verifier are
+// the ones the authorization url was made with.
+func (p *Provider) Login(ctx context.Context, code string, nonce string, verifier string) (*Identity, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := p.exchange(ctx, metadata, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.verify(ctx, metadata, idToken, nonce)
+}
+
+// Returns whether the identity is in the admin group, and false for ok when
+// no admin group is configured.
+func (p *Provider) IsAdmin(identity *Identity) (isAdmin bool, ok bool) {
+	if p.adminGroup == "" {
+		return false, false
+	}
+	return slices.Contains(identity.Groups, p.adminGroup), true
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+	if time.Since(p.discoveredAt) < discoveryRetryInterval {
+		return nil, errors.New("oidc discovery failed recently")
+	}
+	p.discoveredAt = time.Now()
+
+	var discovered metadata
+	if err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", &discovered); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(discovered.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("oidc discovery returned issuer %v, expected %v", discovered.Issuer, p.issuer)
+	}
+	if discovered.AuthorizationEndpoint == "" || discovered.TokenEndpoint == "" || discovered.JwksURI == "" {
+		return nil, errors.New("oidc discovery is missing endpoints")
+	}
+	// Keeps the issuer as the provider writes it, as id tokens are checked
+	// against it.
+	p.metadata = &discovered
+	p.keys = newKeySet(discovered.JwksURI, p.getJSON)
+	return p.metadata, nil
+}
+
+func (p *Provider) exchange(ctx context.Context, metadata *metadata, code string, verifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", p.clientID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oidc token request failed: %w", err)
+	}
+	defer res.Body.Close()
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("oidc token response is invalid: %w", err)
+	}
+	// The code was wrong, expired or used already, or the verifier does not
+	// match it.
+	if body.Error == "invalid_grant" {
+		return "", fmt.Errorf("%w: %v %v", ErrRejected, body.Error, body.ErrorDescription)
+	}
+	if res.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("oidc token request failed with %v: %v %v", res.Status, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("oidc token response has no id token")
+	}
+	return body.IDToken, nil
+}
+
+func (p *Provider) verify(ctx context.Context, metadata *metadata, idToken string, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(
+		idToken,
+		claims,
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.keys.get(ctx, kid)
+		},
+		jwt.WithValidMethods(supportedAlgorithms),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: id token: %w", ErrRejected, err)
+	}
+	if claimed, _ := claims["nonce"].(string); claimed != nonce {
+		return nil, fmt.Errorf("%w: id token nonce does not match", ErrRejected)
+	}
+
+	identity := &Identity{Issuer: p.issuer}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.PreferredUsername, _ = claims["preferred_username"].(string)
+	identity.Email, _ = claims["email"].(string)
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: id token has no subject", ErrRejected)
+	}
+	// Providers send a list of groups, or a single one as a string.
+	switch groups := claims[p.groupsClaim].(type) {
+	case []any:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, name)
+			}
+		}
+	case string:
+		identity.Groups = []string{groups}
+	}
+	return identity, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %v returned %v", url, res.Status)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(v)
+}
@@ -0,0 +1,630 @@
+package quickadd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoTitle = errors.New("nothing left for the title")
+
+// The todo described by a quick-add line. Fields not given in the line are nil
+// or empty.
+type Result struct {
+	Title string     `json:"title"`
+	Due   *time.Time `json:"due"`
+	// Due is the start of a day and the todo is due on that whole day.
+	AllDay     bool     `json:"all_day"`
+	Recurrence *string  `json:"recurrence"`
+	Tags       []string `json:"tags"`
+	Priority   *int16   `json:"priority"`
+	List       *string  `json:"list"`
+}
+
+var priorities = map[string]int16{
+	"low":    1,
+	"medium": 2,
+	"med":    2,
+	"high":   3,
+	"urgent": 3,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+var rruleDays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Largest count of "in 3 days" and "every 2 weeks", so that offsets cannot
+// overflow. Larger ones are left in the title.
+const maxCount = 100000
+
+// Latest year a date can have, as later ones cannot be formatted as dates.
+const maxYear = 9999
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var counts = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+var (
+	ordinalRegex = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+	clockRegex   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+)
+
+type parser struct {
+	words []string
+	now   time.Time
+
+	// The day the todo is due at midnight, and the time of day if given.
+	date   *time.Time
+	hour   int
+	minute int
+	clock  bool
+	// An exact due time, as given by "in 2 hours".
+	exact *time.Time
+
+	freq       string
+	interval   int
+	byDay      []time.Weekday
+	byMonthDay int
+
+	result *Result
+}
+
+// Parses a quick-add line such as "Pay rent every month on the 1st #finance
+// !high". Dates and times are relative to now and in its location. Dates
+// without a time of day are all-day.
+//
+// Understood are #tags, !priority (low, medium, high), @list, recurrences
+// ("daily", "every 2 weeks", "every monday and friday", "every weekday"),
+// days of the month ("on the 1st"), dates ("today", "tomorrow", "friday",
+// "next week", "in 3 days", "jan 5", "2025-01-05") and times ("at 5pm",
+// "17:30", "noon"). Every other word is part of the title.
+func Parse(line string, now time.Time) (*Result, error) {
+	p := &parser{
+		words:  strings.Fields(line),
+		now:    now,
+		result: &Result{Tags: []string{}},
+	}
+
+	title := make([]string, 0, len(p.words))
+	for i := 0; i < len(p.words); {
+		if n := p.match(i); n > 0 {
+			i += n
+			continue
+		}
+		title = append(title, p.words[i])
+		i++
+	}
+	p.result.Title = strings.Join(title, " ")
+	if p.result.Title == "" {
+		return nil, ErrNoTitle
+	}
+
+	p.resolve()
+	return p.result, nil
+}
+
+// Returns the word at i in lower case without trailing punctuation, or an
+// empty string if i is out of range.
+func (p *parser) word(i int) string {
+	if i < 0 || i >= len(p.words) {
+		return ""
+	}
+	return strings.ToLower(strings.TrimRight(p.words[i], ",.;"))
+}
+
+// Tries every matcher at i and returns the number of words consumed.
+func (p *parser) match(i int) int {
+	matchers := []func(int) int{
+		p.matchTag,
+		p.matchPriority,
+		p.matchList,
+		p.matchRecurrence,
+		p.matchMonthDay,
+		p.matchDate,
+		p.matchClock,
+	}
+	for _, matcher := range matchers {
+		if n := matcher(i); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+func (p *parser) matchTag(i int) int {
+	word := strings.TrimRight(p.words[i], ",.;")
+	if len(word) < 2 || word[0] != '#' {
+		return 0
+	}
+	tag := strings.ToLower(word[1:])
+	for _, existing := range p.result.Tags {
+		if existing == tag {
+			return 1
+		}
+	}
+	p.result.Tags = append(p.result.Tags, tag)
+	return 1
+}
+
+func (p *parser) matchPriority(i int) int {
+	word := p.word(i)
+	if p.result.Priority != nil || !strings.HasPrefix(word, "!") {
+		return 0
+	}
+	priority, ok := priorities[word[1:]]
+	if !ok {
+		return 0
+	}
+	p.result.Priority = &priority
+	return 1
+}
+
+func (p *parser) matchList(i int) int {
+	word := strings.TrimRight(p.words[i], ",.;")
+	if p.result.List != nil || len(word) < 2 || word[0] != '@' {
+		return 0
+	}
+	list := strings.ReplaceAll(word[1:], "_", " ")
+	p.result.List = &list
+	return 1
+}
+
+func (p *parser) matchRecurrence(i int) int {
+	if p.freq != "" {
+		return 0
+	}
+	switch p.word(i) {
+	case "daily":
+		p.freq = "DAILY"
+		return 1
+	case "weekly":
+		p.freq = "WEEKLY"
+		return 1
+	case "monthly":
+		p.freq = "MONTHLY"
+		return 1
+	case "yearly", "annually":
+		p.freq = "YEARLY"
+		return 1
+	case "every":
+	default:
+		return 0
+	}
+
+	next := p.word(i + 1)
+	switch next {
+	case "weekday":
+		p.freq = "WEEKLY"
+		p.byDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return 2
+	case "weekend":
+		p.freq = "WEEKLY"
+		p.byDay = []time.Weekday{time.Saturday, time.Sunday}
+		return 2
+	}
+	if freq := frequency(next); freq != "" {
+		p.freq = freq
+		return 2
+	}
+
+	interval := 0
+	if next == "other" {
+		interval = 2
+	} else if n, err := strconv.Atoi(next); err == nil && n > 0 && n <= maxCount {
+		interval = n
+	} else if n, ok := counts[next]; ok && n > 1 {
+		interval = n
+	}
+	if interval > 0 {
+		freq := frequency(p.word(i + 2))
+		if freq == "" {
+			return 0
+		}
+		p.freq = freq
+		p.interval = interval
+		return 3
+	}
+
+	// A list of weekdays such as "monday, wednesday and friday"
+	n := 1
+	for {
+		day, ok := weekdays[strings.TrimSuffix(p.word(i+n), "s")]
+		if !ok {
+			break
+		}
+		p.byDay = append(p.byDay, day)
+		n++
+		if p.word(i+n) == "and" {
+			if _, ok := weekdays[strings.TrimSuffix(p.word(i+n+1), "s")]; ok {
+				n++
+			}
+		}
+	}
+	if len(p.byDay) == 0 {
+		return 0
+	}
+	p.freq = "WEEKLY"
+	return n
+}
+
+// Returns the recurrence frequency of a unit like "day" or "weeks".
+func frequency(unit string) string {
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return "DAILY"
+	case "week":
+		return "WEEKLY"
+	case "month":
+		return "MONTHLY"
+	case "year":
+		return "YEARLY"
+	}
+	return ""
+}
+
+// Matches "on the 1st", "the 15th" and "on 3rd".
+func (p *parser) matchMonthDay(i int) int {
+	if p.byMonthDay != 0 {
+		return 0
+	}
+	n := 0
+	if p.word(i) == "on" {
+		n++
+	}
+	hasThe := p.word(i+n) == "the"
+	if hasThe {
+		n++
+	}
+	match := ordinalRegex.FindStringSubmatch(p.word(i + n))
+	if n == 0 || match == nil || match[2] == "" {
+		return 0
+	}
+	// "on the 5th of january" is a date.
+	if _, ok := months[p.word(i+n+1)]; ok || p.word(i+n+1) == "of" {
+		return 0
+	}
+	day, _ := strconv.Atoi(match[1])
+	if day < 1 || day > 31 {
+		return 0
+	}
+	p.byMonthDay = day
+	return n + 1
+}
+
+func (p *parser) matchDate(i int) int {
+	if p.date != nil || p.exact != nil {
+		return 0
+	}
+	n := 0
+	switch p.word(i) {
+	case "on", "by", "due":
+		n++
+		if p.word(i+n) == "the" {
+			n++
+		}
+	}
+
+	today := p.day(p.now)
+	word := p.word(i + n)
+	switch word {
+	case "today":
+		p.date = &today
+		return n + 1
+	case "tomorrow":
+		date := today.AddDate(0, 0, 1)
+		p.date = &date
+		return n + 1
+	case "next":
+		switch p.word(i + n + 1) {
+		case "week":
+			date := nextWeekday(today, time.Monday)
+			p.date = &date
+			return n + 2
+		case "month":
+			date := time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location())
+			p.date = &date
+			return n + 2
+		case "year":
+			date := time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location())
+			p.date = &date
+			return n + 2
+		}
+		if day, ok := weekdays[p.word(i+n+1)]; ok {
+			date := nextWeekday(today, day)
+			p.date = &date
+			return n + 2
+		}
+		return 0
+	case "in":
+		return p.matchOffset(i + n)
+	}
+
+	if day, ok := weekdays[word]; ok {
+		date := nextWeekday(today, day)
+		p.date = &date
+		return n + 1
+	}
+	if date, err := time.ParseInLocation("2006-01-02", word, p.now.Location()); err == nil {
+		p.date = &date
+		return n + 1
+	}
+	if m := p.matchMonthAndDay(i + n); m > 0 {
+		return n + m
+	}
+	return 0
+}
+
+// Matches "in 3 days", "in a week" and "in 2 hours".
+func (p *parser) matchOffset(i int) int {
+	count, err := strconv.Atoi(p.word(i + 1))
+	if err != nil {
+		var ok bool
+		if count, ok = counts[p.word(i+1)]; !ok {
+			return 0
+		}
+	}
+	if count < 1 || count > maxCount {
+		return 0
+	}
+
+	today := p.day(p.now)
+	var date time.Time
+	switch strings.TrimSuffix(p.word(i+2), "s") {
+	case "minute":
+		exact := p.now.Add(time.Duration(count) * time.Minute)
+		p.exact = &exact
+		return 3
+	case "hour":
+		exact := p.now.Add(time.Duration(count) * time.Hour)
+		p.exact = &exact
+		return 3
+	case "day":
+		date = today.AddDate(0, 0, count)
+	case "week":
+		date = today.AddDate(0, 0, 7*count)
+	case "month":
+		date = addMonths(today, count)
+	case "year":
+		date = addMonths(today, 12*count)
+	default:
+		return 0
+	}
+	if date.Year() > maxYear {
+		return 0
+	}
+	p.date = &date
+	return 3
+}
+
+// Matches "jan 5", "january 5th", "5 jan" and "5th of january", with an
+// optional year. Dates without a year are the next such date from today.
+func (p *parser) matchMonthAndDay(i int) int {
+	var month time.Month
+	var day, n int
+	if m, ok := months[p.word(i)]; ok {
+		match := ordinalRegex.FindStringSubmatch(p.word(i + 1))
+		if match == nil {
+			return 0
+		}
+		month = m
+		day, _ = strconv.Atoi(match[1])
+		n = 2
+	} else if match := ordinalRegex.FindStringSubmatch(p.word(i)); match != nil {
+		n = 1
+		if p.word(i+n) == "of" {
+			n++
+		}
+		m, ok := months[p.word(i+n)]
+		if !ok {
+			return 0
+		}
+		month = m
+		day, _ = strconv.Atoi(match[1])
+		n++
+	} else {
+		return 0
+	}
+
+	today := p.day(p.now)
+	year := today.Year()
+	hasYear := false
+	if y, err := strconv.Atoi(p.word(i + n)); err == nil && y >= 1970 && y <= maxYear {
+		year = y
+		hasYear = true
+		n++
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Month() != month {
+		return 0
+	}
+	if !hasYear && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	p.date = &date
+	return n
+}
+
+// Matches "at 5pm", "5:30pm", "17:30", "at 9", "noon" and "midnight".
+func (p *parser) matchClock(i int) int {
+	if p.clock || p.exact != nil {
+		return 0
+	}
+	n := 0
+	hasAt := p.word(i) == "at"
+	if hasAt {
+		n++
+	}
+
+	word := p.word(i + n)
+	switch word {
+	case "noon":
+		p.hour, p.minute, p.clock = 12, 0, true
+		return n + 1
+	case "midnight":
+		p.hour, p.minute, p.clock = 0, 0, true
+		return n + 1
+	}
+	// "5 pm" is written as two words
+	if suffix := p.word(i + n + 1); (suffix == "am" || suffix == "pm") && !strings.ContainsAny(word, "apm") {
+		word += suffix
+		n++
+	}
+
+	match := clockRegex.FindStringSubmatch(word)
+	if match == nil {
+		return 0
+	}
+	// A bare number is only a time after "at".
+	if match[2] == "" && match[3] == "" && !hasAt {
+		return 0
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	switch match[3] {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour %= 12
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour = hour%12 + 12
+	}
+	if hour > 23 || minute > 59 {
+		return 0
+	}
+	p.hour, p.minute, p.clock = hour, minute, true
+	return n + 1
+}
+
+// Builds the recurrence rule and the due time from what was matched.
+func (p *parser) resolve() {
+	today := p.day(p.now)
+
+	if p.freq != "" {
+		rule := "FREQ=" + p.freq
+		if p.interval > 1 {
+			rule += fmt.Sprintf(";INTERVAL=%d", p.interval)
+		}
+		if len(p.byDay) > 0 {
+			days := make([]string, 0, len(p.byDay))
+			for _, day := range p.byDay {
+				days = append(days, rruleDays[day])
+			}
+			rule += ";BYDAY=" + strings.Join(days, ",")
+		}
+		if p.byMonthDay != 0 && (p.freq == "MONTHLY" || p.freq == "YEARLY") {
+			rule += fmt.Sprintf(";BYMONTHDAY=%d", p.byMonthDay)
+		}
+		p.result.Recurrence = &rule
+	}
+
+	if p.exact != nil {
+		p.result.Due = p.exact
+		return
+	}
+
+	// Without a date, the todo is due on the first day that fits the other
+	// parts.
+	if p.date == nil && p.byMonthDay != 0 {
+		date := nextMonthDay(today, p.byMonthDay)
+		p.date = &date
+	}
+	if p.date == nil && len(p.byDay) > 0 {
+		date := today
+		for !containsWeekday(p.byDay, date.Weekday()) {
+			date = date.AddDate(0, 0, 1)
+		}
+		p.date = &date
+	}
+	if p.date == nil && (p.freq != "" || p.clock) {
+		p.date = &today
+	}
+	if p.date == nil {
+		return
+	}
+
+	var due time.Time
+	if p.clock {
+		due = time.Date(p.date.Year(), p.date.Month(), p.date.Day(), p.hour, p.minute, 0, 0, p.date.Location())
+		// A time that has already passed today means tomorrow.
+		if due.Before(p.now) && p.date.Equal(today) && p.freq == "" {
+			due = time.Date(p.date.Year(), p.date.Month(), p.date.Day()+1, p.hour, p.minute, 0, 0, p.date.Location())
+		}
+	} else {
+		due = *p.date
+		p.result.AllDay = true
+	}
+	p.result.Due = &due
+}
+
+// Returns the start of the day of t.
+func (p *parser) day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Returns the first day after today that is the weekday.
+func nextWeekday(today time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// Adds the months to the date. Days the month does not have become its last
+// day, so that a month after January 31st is the end of February.
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
+// Returns the first day from today on that is the day of the month, skipping
+// months that are too short.
+func nextMonthDay(today time.Time, monthDay int) time.Time {
+	for months := 0; ; months++ {
+		date := time.Date(today.Year(), today.Month()+time.Month(months), monthDay, 0, 0, 0, 0, today.Location())
+		if date.Day() == monthDay && !date.Before(today) {
+			return date
+		}
+	}
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
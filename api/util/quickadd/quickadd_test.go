@@ -0,0 +1,186 @@
+package quickadd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	loc := time.FixedZone("CET", 60*60)
+	// A Friday.
+	friday := time.Date(2025, time.January, 31, 10, 0, 0, 0, loc)
+	day := func(year int, month time.Month, day int) *time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		return &date
+	}
+	at := func(year int, month time.Month, day int, hour int, minute int) *time.Time {
+		date := time.Date(year, month, day, hour, minute, 0, 0, loc)
+		return &date
+	}
+
+	tests := []struct {
+		name       string
+		line       string
+		now        time.Time
+		title      string
+		due        *time.Time
+		allDay     bool
+		recurrence string
+		tags       []string
+		priority   int16
+		list       string
+	}{
+		{name: "title only", line: "Buy milk", title: "Buy milk"},
+		{name: "tomorrow", line: "Pay rent tomorrow", title: "Pay rent", due: day(2025, time.February, 1), allDay: true},
+		{name: "days across month end", line: "Report in 3 days", title: "Report", due: day(2025, time.February, 3), allDay: true},
+		{name: "weeks", line: "Review in 2 weeks", title: "Review", due: day(2025, time.February, 14), allDay: true},
+		{name: "month from 31st", line: "Call in a month", title: "Call", due: day(2025, time.February, 28), allDay: true},
+		{
+			name:   "month from 31st to 30 day month",
+			line:   "Call in one month",
+			now:    time.Date(2025, time.March, 31, 10, 0, 0, 0, loc),
+			title:  "Call",
+			due:    day(2025, time.April, 30),
+			allDay: true,
+		},
+		{
+			name:   "year from leap day",
+			line:   "Renew in 1 year",
+			now:    time.Date(2024, time.February, 29, 10, 0, 0, 0, loc),
+			title:  "Renew",
+			due:    day(2025, time.February, 28),
+			allDay: true,
+		},
+		{name: "hours", line: "Standup in 2 hours", title: "Standup", due: at(2025, time.January, 31, 12, 0)},
+		{name: "next week", line: "Plan next week", title: "Plan", due: day(2025, time.February, 3), allDay: true},
+		{name: "weekday", line: "Meet on friday", title: "Meet", due: day(2025, time.February, 7), allDay: true},
+		{name: "month and day", line: "Party jan 5", title: "Party", due: day(2026, time.January, 5), allDay: true},
+		{name: "month and day with year", line: "Party 5th of january 2027", title: "Party", due: day(2027, time.January, 5), allDay: true},
+		{name: "no february 30th", line: "Party feb 30", title: "Party feb 30"},
+		{name: "iso date", line: "Trip 2025-03-01", title: "Trip", due: day(2025, time.March, 1), allDay: true},
+		{name: "noon", line: "Lunch at noon", title: "Lunch", due: at(2025, time.January, 31, 12, 0)},
+		{name: "passed time is tomorrow", line: "Breakfast at 9am", title: "Breakfast", due: at(2025, time.February, 1, 9, 0)},
+		{name: "date and time", line: "Dentist tomorrow 5:30pm", title: "Dentist", due: at(2025, time.February, 1, 17, 30)},
+		{name: "bare number is no time", line: "Read 9 books", title: "Read 9 books"},
+		{
+			name:       "daily",
+			line:       "Stretch daily",
+			title:      "Stretch",
+			due:        day(2025, time.January, 31),
+			allDay:     true,
+			recurrence: "FREQ=DAILY",
+		},
+		{
+			name:       "interval",
+			line:       "Water plants every 2 weeks",
+			title:      "Water plants",
+			due:        day(2025, time.January, 31),
+			allDay:     true,
+			recurrence: "FREQ=WEEKLY;INTERVAL=2",
+		},
+		{
+			name:       "every other",
+			line:       "Clean every other month",
+			title:      "Clean",
+			due:        day(2025, time.January, 31),
+			allDay:     true,
+			recurrence: "FREQ=MONTHLY;INTERVAL=2",
+		},
+		{
+			name:       "weekdays",
+			line:       "Gym every tuesday and thursday at 7am",
+			title:      "Gym",
+			due:        at(2025, time.February, 4, 7, 0),
+			recurrence: "FREQ=WEEKLY;BYDAY=TU,TH",
+		},
+		{
+			name:       "every weekday",
+			line:       "Work every weekday",
+			title:      "Work",
+			due:        day(2025, time.January, 31),
+			allDay:     true,
+			recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		},
+		{
+			name:       "day of the month",
+			line:       "Pay rent every month on the 1st",
+			title:      "Pay rent",
+			due:        day(2025, time.February, 1),
+			allDay:     true,
+			recurrence: "FREQ=MONTHLY;BYMONTHDAY=1",
+		},
+		{
+			name:       "day of the month skips short months",
+			line:       "Backup monthly on the 30th",
+			title:      "Backup",
+			due:        day(2025, time.March, 30),
+			allDay:     true,
+			recurrence: "FREQ=MONTHLY;BYMONTHDAY=30",
+		},
+		{name: "tags", line: "Taxes #home #Home, #money", title: "Taxes", tags: []string{"home", "money"}},
+		{name: "priority", line: "Taxes !high !low", title: "Taxes !low", priority: 3},
+		{name: "unknown priority", line: "Wow !!", title: "Wow !!"},
+		{name: "list", line: "Taxes @work_stuff", title: "Taxes", list: "work stuff"},
+		{name: "huge offset", line: "Far in 999999999999 days", title: "Far in 999999999999 days"},
+		{name: "offset past year 9999", line: "Far in 9000 years", title: "Far in 9000 years"},
+		{name: "huge interval", line: "Far every 999999 days", title: "Far every 999999 days"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := test.now
+			if now.IsZero() {
+				now = friday
+			}
+			result, err := Parse(test.line, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", test.line, err)
+			}
+			if result.Title != test.title {
+				t.Errorf("title = %q, want %q", result.Title, test.title)
+			}
+			if (result.Due == nil) != (test.due == nil) || result.Due != nil && !result.Due.Equal(*test.due) {
+				t.Errorf("due = %v, want %v", result.Due, test.due)
+			}
+			if result.AllDay != test.allDay {
+				t.Errorf("all day = %v, want %v", result.AllDay, test.allDay)
+			}
+			recurrence := ""
+			if result.Recurrence != nil {
+				recurrence = *result.Recurrence
+			}
+			if recurrence != test.recurrence {
+				t.Errorf("recurrence = %q, want %q", recurrence, test.recurrence)
+			}
+			if test.tags == nil {
+				test.tags = []string{}
+			}
+			if !slices.Equal(result.Tags, test.tags) {
+				t.Errorf("tags = %v, want %v", result.Tags, test.tags)
+			}
+			var priority int16
+			if result.Priority != nil {
+				priority = *result.Priority
+			}
+			if priority != test.priority {
+				t.Errorf("priority = %v, want %v", priority, test.priority)
+			}
+			list := ""
+			if result.List != nil {
+				list = *result.List
+			}
+			if list != test.list {
+				t.Errorf("list = %q, want %q", list, test.list)
+			}
+		})
+	}
+}
+
+func TestParseNoTitle(t *testing.T) {
+	for _, line := range []string{"", "  ", "#tag !high tomorrow", "@inbox daily"} {
+		if _, err := Parse(line, time.Now()); !errors.Is(err, ErrNoTitle) {
+			t.Errorf("Parse(%q) error = %v, want ErrNoTitle", line, err)
+		}
+	}
+}
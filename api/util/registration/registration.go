@@ -0,0 +1,119 @@
+// Package registration decides who can create an account with POST /user and
+// sends the links that verify the email addresses of users.
+package registration
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/config"
+	"go-todo/util/mail"
+	"go-todo/util/secret"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Anyone can create an account.
+	ModeOpen = "open"
+	// Creating an account needs an invite code made by an admin.
+	ModeInviteOnly = "invite-only"
+	// Only the first user can be created.
+	ModeClosed = "closed"
+
+	InviteCodePrefix = "gti_"
+
+	verificationPrefix   = "gtv_"
+	verificationLifeSpan = 24 * time.Hour
+)
+
+type Settings struct {
+	Mode                     string
+	RequireEmailVerification bool
+}
+
+// Returns the registration settings from the config.
+func Get() (*Settings, error) {
+	config, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+	settings := &Settings{
+		Mode:                     config.RegistrationMode,
+		RequireEmailVerification: config.RequireEmailVerification,
+	}
+	switch settings.Mode {
+	case "":
+		settings.Mode = ModeOpen
+	case ModeOpen, ModeInviteOnly, ModeClosed:
+	default:
+		return nil, fmt.Errorf("unknown registration mode %v", settings.Mode)
+	}
+	return settings, nil
+}
+
+// Replaces the verification links of the user with a new one. Returns the
+// token, which is only sent by email.
+func CreateVerificationToken(ctx context.Context, queries *db.Queries, userID string) (string, error) {
+	if err := queries.DeleteExpiredEmailVerificationTokens(ctx); err != nil {
+		return "", fmt.Errorf("failed to delete expired email verification tokens: %w", err)
+	}
+	// Only the newest link works.
+	if err := queries.DeleteEmailVerificationTokensByUserId(ctx, userID); err != nil {
+		return "", fmt.Errorf("failed to delete email verification tokens: %w", err)
+	}
+	token, tokenHash, err := secret.Generate(verificationPrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate email verification token: %w", err)
+	}
+	args := &db.CreateEmailVerificationTokenParams{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().UTC().Add(verificationLifeSpan), Valid: true},
+	}
+	if err := queries.CreateEmailVerificationToken(ctx, *args); err != nil {
+		return "", fmt.Errorf("failed to save email verification token: %w", err)
+	}
+	return token, nil
+}
+
+// Emails the verification link with the token to the user. Errors are
+// logged, since it is sent after responding.
+func SendVerificationEmail(to string, username string, token string) {
+	logError := func(err error) {
+		_, file, line, _ := runtime.Caller(1)
+		logging.LogError(err, fmt.Sprintf("%v: %d", file, line), "failed to send verification email")
+	}
+	config, err := config.Get()
+	if err != nil {
+		logError(err)
+		return
+	}
+
+	link := token
+	if config.EmailVerificationURL != "" {
+		verifyURL, err := url.Parse(config.EmailVerificationURL)
+		if err != nil {
+			logError(err)
+			return
+		}
+		query := verifyURL.Query()
+		query.Set("token", token)
+		verifyURL.RawQuery = query.Encode()
+		link = verifyURL.String()
+	}
+	body := fmt.Sprintf(
+		"Hi %v,\n\nplease confirm the email address of your account with this link within %v hours:\n\n%v\n\nIf you did not create an account or change its email address, you can ignore this email.\n",
+		username,
+		int(verificationLifeSpan.Hours()),
+		link,
+	)
+	if err := mail.Send(to, "Verify your email address", body); err != nil {
+		logError(err)
+	}
+}
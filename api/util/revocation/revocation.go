@@ -0,0 +1,165 @@
+// Package revocation revokes access jwts before they expire. Revocations are
+// stored in the database and cached in process, so checking a jwt does not
+// query the database. Revocations made by other processes are seen after at
+// most maxStaleness.
+//
+// Revoking every jwt of a user moves the user to the next token epoch. Access
+// jwts carry the epoch they were issued in, so the ones of earlier epochs are
+// revoked while the ones issued afterwards stay valid, however soon.
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/util/config"
+	"go-todo/util/jwt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxStaleness = 10 * time.Second
+
+var cache = struct {
+	sync.RWMutex
+	loadedAt time.Time
+	// Epoch every jwt of the user issued in an earlier one was revoked up to,
+	// by user.
+	users map[string]int32
+	// Revoked sessions, by refresh token family.
+	sessions map[string]bool
+}{
+	users:    map[string]int32{},
+	sessions: map[string]bool{},
+}
+
+// Held while loading, so that only one request loads at a time.
+var loading sync.Mutex
+
+// Reports whether the access jwt has been revoked, either by itself through its
+// session or with every jwt of its user.
+func IsRevoked(ctx context.Context, queries *db.Queries, claims *jwt.GtClaims) (bool, error) {
+	if err := load(ctx, queries); err != nil {
+		return false, err
+	}
+
+	cache.RLock()
+	defer cache.RUnlock()
+	if epoch, ok := cache.users[claims.Subject]; ok && claims.Epoch < epoch {
+		return true, nil
+	}
+	return claims.Session != "" && cache.sessions[claims.Session], nil
+}
+
+// Revokes every access jwt of the user issued until now by moving the user to
+// the next token epoch, which is returned. Jwts issued afterwards must carry
+// it, so a user loaded before has to be given it. Deleted users have every jwt
+// revoked.
+func RevokeUser(ctx context.Context, queries *db.Queries, userID string) (int32, error) {
+	epoch, err := queries.IncrementUserTokenEpoch(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		epoch = math.MaxInt32
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to increment token epoch: %w", err)
+	}
+	return epoch, revoke(ctx, queries, userID, "", epoch)
+}
+
+// Deletes the personal access tokens and app passwords of the user, which
+// RevokeUser keeps, as scripts and CalDAV clients use them. Done when the
+// password changes, deleting the user deletes them as well.
+func RevokeCredentials(ctx context.Context, queries *db.Queries, userID string) error {
+	if err := queries.DeletePersonalAccessTokensByUserId(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete personal access tokens: %w", err)
+	}
+	if err := queries.DeleteAppPasswordsByUserId(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete app passwords: %w", err)
+	}
+	return nil
+}
+
+// Revokes the access jwts of the session, which is a refresh token family.
+func RevokeSession(ctx context.Context, queries *db.Queries, userID string, family string) error {
+	return revoke(ctx, queries, userID, family, 0)
+}
+
+func revoke(ctx context.Context, queries *db.Queries, userID string, family string, epoch int32) error {
+	config, err := config.Get()
+	if err != nil {
+		return err
+	}
+
+	revokedAt := time.Now().UTC()
+	// Jwts issued before revokedAt have all expired by then.
+	expiresAt := revokedAt.Add(time.Minute * time.Duration(config.AccessTokenLifeSpan))
+	args := &db.CreateTokenRevocationParams{
+		UserID:    userID,
+		Family:    pgtype.Text{String: family, Valid: family != ""},
+		RevokedAt: pgtype.Timestamptz{Time: revokedAt, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		Epoch:     epoch,
+	}
+	if err := queries.CreateTokenRevocation(ctx, *args); err != nil {
+		return fmt.Errorf("failed to save token revocation: %w", err)
+	}
+
+	// Applies to this process at once, without waiting for the next load.
+	cache.Lock()
+	defer cache.Unlock()
+	add(family, userID, epoch)
+	return nil
+}
+
+// Adds a revocation to the cache. Expects the cache to be locked.
+func add(family string, userID string, epoch int32) {
+	if family != "" {
+		cache.sessions[family] = true
+	} else if epoch > cache.users[userID] {
+		cache.users[userID] = epoch
+	}
+}
+
+// Replaces the cache with the revocations in the database, if it is older than
+// maxStaleness. Expired revocations are removed on the way.
+func load(ctx context.Context, queries *db.Queries) error {
+	cache.RLock()
+	fresh := time.Since(cache.loadedAt) < maxStaleness
+	cache.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	loading.Lock()
+	defer loading.Unlock()
+	// Another request may have loaded while this one waited.
+	cache.RLock()
+	fresh = time.Since(cache.loadedAt) < maxStaleness
+	cache.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	if err := queries.DeleteExpiredTokenRevocations(ctx); err != nil {
+		return fmt.Errorf("failed to delete expired token revocations: %w", err)
+	}
+	revocations, err := queries.GetTokenRevocations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get token revocations: %w", err)
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+	cache.users = make(map[string]int32, len(revocations))
+	cache.sessions = make(map[string]bool, len(revocations))
+	for _, revocation := range revocations {
+		add(revocation.Family.String, revocation.UserID, revocation.Epoch)
+	}
+	cache.loadedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,111 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	db "go-todo/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Blocks made by other processes are seen after at most this long.
+const maxStaleness = 10 * time.Second
+
+var blocks = struct {
+	sync.RWMutex
+	loadedAt time.Time
+	// Until when each ip is blocked.
+	ips map[string]time.Time
+}{
+	ips: map[string]time.Time{},
+}
+
+// Held while loading, so that only one request loads at a time.
+var loading sync.Mutex
+
+// Reports whether requests from the ip are refused. Loopback addresses are
+// never blocked, so that the server can always be reached from itself.
+func IsBlocked(ctx context.Context, queries *db.Queries, ip string) (bool, error) {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.IsLoopback() {
+		return false, nil
+	}
+	if err := load(ctx, queries); err != nil {
+		return false, err
+	}
+
+	blocks.RLock()
+	defer blocks.RUnlock()
+	until, ok := blocks.ips[ip]
+	return ok && time.Now().Before(until), nil
+}
+
+// Lifts the block of the ip. Returns false if it was not blocked.
+func Unblock(ctx context.Context, queries *db.Queries, ip string) (bool, error) {
+	rows, err := queries.DeleteIpBlock(ctx, ip)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete ip block: %w", err)
+	}
+
+	blocks.Lock()
+	defer blocks.Unlock()
+	delete(blocks.ips, ip)
+	return rows > 0, nil
+}
+
+func block(ctx context.Context, queries *db.Queries, ip string, reason string, until time.Time) error {
+	args := &db.BlockIpParams{
+		Ip:           ip,
+		Reason:       reason,
+		BlockedUntil: pgtype.Timestamptz{Time: until, Valid: true},
+	}
+	if err := queries.BlockIp(ctx, *args); err != nil {
+		return fmt.Errorf("failed to block ip: %w", err)
+	}
+
+	// Applies to this process at once, without waiting for the next load.
+	blocks.Lock()
+	defer blocks.Unlock()
+	if until.After(blocks.ips[ip]) {
+		blocks.ips[ip] = until
+	}
+	return nil
+}
+
+// Replaces the cache with the blocks in the database, if it is older than
+// maxStaleness.
+func load(ctx context.Context, queries *db.Queries) error {
+	blocks.RLock()
+	fresh := time.Since(blocks.loadedAt) < maxStaleness
+	blocks.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	loading.Lock()
+	defer loading.Unlock()
+	// Another request may have loaded while this one waited.
+	blocks.RLock()
+	fresh = time.Since(blocks.loadedAt) < maxStaleness
+	blocks.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	ipBlocks, err := queries.GetIpBlocks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ip blocks: %w", err)
+	}
+
+	blocks.Lock()
+	defer blocks.Unlock()
+	blocks.ips = make(map[string]time.Time, len(ipBlocks))
+	for _, ipBlock := range ipBlocks {
+		blocks.ips[ipBlock.Ip] = ipBlock.BlockedUntil.Time
+	}
+	blocks.loadedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,343 @@
+// Package risk keeps a rolling score of security events per violator, by user
+// id or ip, and fires the actions of rules when a score reaches their
+// threshold. Scores are stored in the database, so that rules see the events
+// of every instance of the api.
+//
+// Events come from logging.LogSecurityEvent and are handled in the
+// background, so that requests do not wait for the database.
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/logging"
+	"go-todo/util/config"
+	"go-todo/util/lockout"
+	"go-todo/util/mail"
+	"go-todo/util/revocation"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	ViolatorUser = "user"
+	ViolatorIp   = "ip"
+
+	// Logs out every session of the user.
+	ActionRevokeSessions = "revoke_sessions"
+	// Locks logging in as the user for duration_minutes and revokes their
+	// sessions.
+	ActionLockAccount = "lock_account"
+	// Refuses every request from the ip for duration_minutes.
+	ActionBlockIp = "block_ip"
+	// Emails the admins that have an email address.
+	ActionNotifyAdmins = "notify_admins"
+
+	defaultWindowMinutes   = 60
+	defaultDurationMinutes = 60
+	// Events waiting to be handled. Further ones are dropped until there is
+	// room again.
+	eventBuffer   = 1000
+	pruneInterval = time.Minute
+)
+
+// Fires Action once when the score of a violator in the window reaches
+// Threshold.
+type Rule struct {
+	Name string `json:"name"`
+	// user or ip, or empty for both. revoke_sessions and lock_account only
+	// apply to users and block_ip only to ips.
+	Violator  string `json:"violator"`
+	Threshold int    `json:"threshold"`
+	Action    string `json:"action"`
+	// How long lock_account and block_ip last. Defaults to 60.
+	DurationMinutes int `json:"duration_minutes"`
+}
+
+var defaultRules = []Rule{
+	{Name: "notify-admins", Threshold: 30, Action: ActionNotifyAdmins},
+	{Name: "revoke-user-sessions", Violator: ViolatorUser, Threshold: 30, Action: ActionRevokeSessions},
+	{Name: "lock-user", Violator: ViolatorUser, Threshold: 50, Action: ActionLockAccount},
+	{Name: "block-ip", Violator: ViolatorIp, Threshold: 50, Action: ActionBlockIp},
+}
+
+func (rule *Rule) appliesTo(violatorType string) bool {
+	if rule.Violator != "" && rule.Violator != violatorType {
+		return false
+	}
+	switch rule.Action {
+	case ActionRevokeSessions, ActionLockAccount:
+		return violatorType == ViolatorUser
+	case ActionBlockIp:
+		return violatorType == ViolatorIp
+	}
+	return true
+}
+
+type engine struct {
+	queries *db.Queries
+	rules   []Rule
+	window  time.Duration
+	events  chan logging.SecurityEvent
+}
+
+// Returns the rules from the config, or the default ones if none are set.
+func Rules() ([]Rule, error) {
+	config, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+	if config.SecurityRules == "" {
+		return defaultRules, nil
+	}
+	var rules []Rule
+	if err := json.Unmarshal([]byte(config.SecurityRules), &rules); err != nil {
+		return nil, fmt.Errorf("invalid security rules: %w", err)
+	}
+	for i := range rules {
+		rule := &rules[i]
+		switch rule.Action {
+		case ActionRevokeSessions, ActionLockAccount, ActionBlockIp, ActionNotifyAdmins:
+		default:
+			return nil, fmt.Errorf("security rule %v has unknown action %v", rule.Name, rule.Action)
+		}
+		if rule.Violator != "" && rule.Violator != ViolatorUser && rule.Violator != ViolatorIp {
+			return nil, fmt.Errorf("security rule %v has unknown violator %v", rule.Name, rule.Violator)
+		}
+		if rule.Threshold <= 0 {
+			return nil, fmt.Errorf("security rule %v needs a threshold above 0", rule.Name)
+		}
+		if rule.DurationMinutes <= 0 {
+			rule.DurationMinutes = defaultDurationMinutes
+		}
+	}
+	return rules, nil
+}
+
+// Returns how far back scores are summed.
+func Window() (time.Duration, error) {
+	config, err := config.Get()
+	if err != nil {
+		return 0, err
+	}
+	minutes := config.SecurityScoreWindowMinutes
+	if minutes <= 0 {
+		minutes = defaultWindowMinutes
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// Starts scoring the security events logged from now on, until ctx is done.
+func Start(ctx context.Context, queries *db.Queries) error {
+	rules, err := Rules()
+	if err != nil {
+		return err
+	}
+	window, err := Window()
+	if err != nil {
+		return err
+	}
+	e := &engine{
+		queries: queries,
+		rules:   rules,
+		window:  window,
+		events:  make(chan logging.SecurityEvent, eventBuffer),
+	}
+
+	logging.SetSecurityEventHook(func(event logging.SecurityEvent) {
+		select {
+		case e.events <- event:
+		default:
+			logError(errors.New("security event buffer is full, event not scored"))
+		}
+	})
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-e.events:
+				if err := e.handle(ctx, event); err != nil {
+					logError(fmt.Errorf("failed to score security event: %w", err))
+				}
+			case <-ticker.C:
+				if err := e.prune(ctx); err != nil {
+					logError(fmt.Errorf("failed to prune security scores: %w", err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func logError(err error) {
+	_, file, line, _ := runtime.Caller(1)
+	logging.LogError(err, fmt.Sprintf("%v: %d", file, line), err.Error())
+}
+
+func (e *engine) prune(ctx context.Context) error {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-e.window), Valid: true}
+	if err := e.queries.DeleteSecurityScoresBefore(ctx, before); err != nil {
+		return err
+	}
+	return e.queries.DeleteExpiredIpBlocks(ctx)
+}
+
+// Returns whether the violator of an event is a user or an ip, with users
+// given by id. Call sites pass ips, user ids or usernames. Violators that are
+// none of them are not scored.
+func (e *engine) classify(ctx context.Context, violator string) (string, string, bool, error) {
+	if net.ParseIP(violator) != nil {
+		return ViolatorIp, violator, true, nil
+	}
+	user, err := e.queries.GetUserById(ctx, violator)
+	if err == nil {
+		return ViolatorUser, user.ID, true, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return "", "", false, err
+	}
+	user, err = e.queries.GetUserByUsername(ctx, violator)
+	if err == nil {
+		return ViolatorUser, user.ID, true, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return "", "", false, err
+	}
+	return "", "", false, nil
+}
+
+func (e *engine) handle(ctx context.Context, event logging.SecurityEvent) error {
+	violatorType, violator, ok, err := e.classify(ctx, event.Violator)
+	if err != nil || !ok {
+		return err
+	}
+
+	args := &db.CreateSecurityScoreParams{
+		ViolatorType: violatorType,
+		Violator:     violator,
+		Event:        event.Name.String(),
+		Score:        int32(event.Score),
+	}
+	if err := e.queries.CreateSecurityScore(ctx, *args); err != nil {
+		return err
+	}
+	sumArgs := &db.SumSecurityScoreParams{
+		ViolatorType: violatorType,
+		Violator:     violator,
+		CreatedAt:    pgtype.Timestamptz{Time: time.Now().Add(-e.window), Valid: true},
+	}
+	score, err := e.queries.SumSecurityScore(ctx, *sumArgs)
+	if err != nil {
+		return err
+	}
+
+	// Rules fire when this event takes the score from below their threshold
+	// to it, so that each fires once while the score stays high.
+	previous := int(score) - int(event.Score)
+	for _, rule := range e.rules {
+		if !rule.appliesTo(violatorType) || previous >= rule.Threshold || int(score) < rule.Threshold {
+			continue
+		}
+		if err := e.fire(ctx, &rule, violatorType, violator, int(score)); err != nil {
+			logError(fmt.Errorf("security rule %v failed: %w", rule.Name, err))
+		}
+	}
+	return nil
+}
+
+func (e *engine) fire(ctx context.Context, rule *Rule, violatorType string, violator string, score int) error {
+	duration := time.Duration(rule.DurationMinutes) * time.Minute
+	if duration <= 0 {
+		duration = defaultDurationMinutes * time.Minute
+	}
+	until := pgtype.Timestamptz{Time: time.Now().UTC().Add(duration), Valid: true}
+
+	switch rule.Action {
+	case ActionRevokeSessions:
+		if _, err := revocation.RevokeUser(ctx, e.queries, violator); err != nil {
+			return err
+		}
+		if err := e.queries.DeleteJwtTokensByUserId(ctx, violator); err != nil {
+			return err
+		}
+	case ActionLockAccount:
+		user, err := e.queries.GetUserById(ctx, violator)
+		if err != nil {
+			return err
+		}
+		args := &db.UpsertLoginLockParams{Key: lockout.UsernameKey(user.Username), LockedUntil: until}
+		if err := e.queries.UpsertLoginLock(ctx, *args); err != nil {
+			return err
+		}
+		// The lock keeps the user from logging in and from using app passwords
+		// and personal access tokens, the jwts they have are revoked.
+		if _, err := revocation.RevokeUser(ctx, e.queries, violator); err != nil {
+			return err
+		}
+		if err := e.queries.DeleteJwtTokensByUserId(ctx, violator); err != nil {
+			return err
+		}
+	case ActionBlockIp:
+		if err := block(ctx, e.queries, violator, rule.Name, until.Time); err != nil {
+			return err
+		}
+	case ActionNotifyAdmins:
+		if err := e.notifyAdmins(ctx, rule, violatorType, violator, score); err != nil {
+			return err
+		}
+	}
+
+	args := &db.CreateSecurityActionParams{
+		ViolatorType: violatorType,
+		Violator:     violator,
+		Rule:         rule.Name,
+		Action:       rule.Action,
+		Score:        int32(score),
+	}
+	if err := e.queries.CreateSecurityAction(ctx, *args); err != nil {
+		return err
+	}
+	logging.LogAuditEvent(
+		true,
+		"",
+		"",
+		"security:"+rule.Action,
+		slog.String("rule", rule.Name),
+		slog.String("violator_type", violatorType),
+		slog.String("violator", violator),
+		slog.Int("score", score),
+	)
+	return nil
+}
+
+func (e *engine) notifyAdmins(ctx context.Context, rule *Rule, violatorType string, violator string, score int) error {
+	emails, err := e.queries.GetAdminEmails(ctx)
+	if err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("Security alert: %v %v", violatorType, violator)
+	body := fmt.Sprintf(
+		"The %v %v has reached a security score of %d in the last %d minutes, firing rule %v.\n\nSee GET /api/v1/admin/risk for details.\n",
+		violatorType,
+		violator,
+		score,
+		int(e.window.Minutes()),
+		rule.Name,
+	)
+	for _, email := range emails {
+		if err := mail.Send(email, subject, body); err != nil && !errors.Is(err, mail.ErrDisabled) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package secret
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Generates a random token with 256 bits of entropy behind the prefix.
+// Returns the token, which is shown to the user once, and its hash, which is
+// what gets stored.
+func Generate(prefix string) (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	token := prefix + base64.RawURLEncoding.EncodeToString(bytes)
+	return token, Hash(token), nil
+}
+
+// Hashes a token made by Generate. Tokens have enough entropy that a fast
+// hash is sufficient.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,166 @@
+package timesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	db "go-todo/db/sqlc"
+	"go-todo/util/txtutil"
+)
+
+// A finished time entry with the todo, list and user it belongs to.
+type Entry struct {
+	ID                string
+	TodoID            string
+	TodoTitle         string
+	EstimatedDuration *int32
+	ListID            string
+	ListTitle         string
+	UserID            string
+	Username          string
+	StartedAt         time.Time
+	StoppedAt         time.Time
+	Note              string
+}
+
+func (e *Entry) Duration() time.Duration {
+	return e.StoppedAt.Sub(e.StartedAt)
+}
+
+type TodoTotal struct {
+	TodoID            string `json:"todo_id"`
+	TodoTitle         string `json:"todo_title"`
+	ListID            string `json:"list_id"`
+	ListTitle         string `json:"list_title"`
+	EstimatedDuration *int32 `json:"estimated_duration"`
+	TotalSeconds      int64  `json:"total_seconds"`
+}
+
+type UserTotal struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	TotalSeconds int64  `json:"total_seconds"`
+}
+
+type Summary struct {
+	From         time.Time   `json:"from"`
+	To           time.Time   `json:"to"`
+	TotalSeconds int64       `json:"total_seconds"`
+	Todos        []TodoTotal `json:"todos"`
+	Users        []UserTotal `json:"users"`
+}
+
+func FromRows(rows []db.GetTimeEntriesInRangeRow) []Entry {
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		var estimatedDuration *int32
+		if row.EstimatedDuration.Valid {
+			estimatedDuration = &row.EstimatedDuration.Int32
+		}
+		entries = append(entries, Entry{
+			ID:                row.ID,
+			TodoID:            row.TodoID,
+			TodoTitle:         row.TodoTitle,
+			EstimatedDuration: estimatedDuration,
+			ListID:            row.ListID,
+			ListTitle:         row.ListTitle,
+			UserID:            row.UserID,
+			Username:          row.Username,
+			StartedAt:         row.StartedAt.Time,
+			StoppedAt:         row.StoppedAt.Time,
+			Note:              row.Note.String,
+		})
+	}
+	return entries
+}
+
+// Sums the entries per todo and per user. Totals keep the order in which the
+// todos and users first appear in entries.
+func Summarize(from, to time.Time, entries []Entry) *Summary {
+	summary := &Summary{
+		From:  from,
+		To:    to,
+		Todos: []TodoTotal{},
+		Users: []UserTotal{},
+	}
+	todoIndex := make(map[string]int)
+	userIndex := make(map[string]int)
+	for _, entry := range entries {
+		seconds := int64(entry.Duration().Seconds())
+		summary.TotalSeconds += seconds
+
+		i, ok := todoIndex[entry.TodoID]
+		if !ok {
+			i = len(summary.Todos)
+			todoIndex[entry.TodoID] = i
+			summary.Todos = append(summary.Todos, TodoTotal{
+				TodoID:            entry.TodoID,
+				TodoTitle:         entry.TodoTitle,
+				ListID:            entry.ListID,
+				ListTitle:         entry.ListTitle,
+				EstimatedDuration: entry.EstimatedDuration,
+			})
+		}
+		summary.Todos[i].TotalSeconds += seconds
+
+		i, ok = userIndex[entry.UserID]
+		if !ok {
+			i = len(summary.Users)
+			userIndex[entry.UserID] = i
+			summary.Users = append(summary.Users, UserTotal{
+				UserID:   entry.UserID,
+				Username: entry.Username,
+			})
+		}
+		summary.Users[i].TotalSeconds += seconds
+	}
+	return summary
+}
+
+// Writes one CSV row per entry with a header row first. Text cells are quoted
+// with txtutil.CSVEscape, so that spreadsheets do not run them as formulas.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"entry_id",
+		"list_id",
+		"list_title",
+		"todo_id",
+		"todo_title",
+		"user_id",
+		"username",
+		"started_at",
+		"stopped_at",
+		"duration_seconds",
+		"duration_hours",
+		"note",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, entry := range entries {
+		duration := entry.Duration()
+		record := []string{
+			entry.ID,
+			entry.ListID,
+			txtutil.CSVEscape(entry.ListTitle),
+			entry.TodoID,
+			txtutil.CSVEscape(entry.TodoTitle),
+			entry.UserID,
+			txtutil.CSVEscape(entry.Username),
+			entry.StartedAt.Format(time.RFC3339),
+			entry.StoppedAt.Format(time.RFC3339),
+			strconv.FormatInt(int64(duration.Seconds()), 10),
+			strconv.FormatFloat(duration.Hours(), 'f', 2, 64),
+			txtutil.CSVEscape(entry.Note),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -1,7 +1,36 @@
 package txtutil
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func AddLineNumberToFileName(fileName string, lineNumber int) string {
 	return fmt.Sprintf("%v: %d", fileName, lineNumber)
 }
+
+// Cells starting with one of these are run as formulas by spreadsheets.
+const csvFormulaChars = "=+-@\t\r"
+
+// Prefixes a user written CSV cell a spreadsheet would run as a formula with a
+// quote, which spreadsheets hide. Cells already starting with a quote before
+// such a cell get another one, so that CSVUnescape gives back every cell.
+func CSVEscape(value string) string {
+	if csvNeedsEscape(value) {
+		return "'" + value
+	}
+	return value
+}
+
+// Removes the quote CSVEscape added.
+func CSVUnescape(value string) string {
+	if rest, ok := strings.CutPrefix(value, "'"); ok && csvNeedsEscape(rest) {
+		return rest
+	}
+	return value
+}
+
+func csvNeedsEscape(value string) bool {
+	rest := strings.TrimLeft(value, "'")
+	return rest != "" && strings.ContainsRune(csvFormulaChars, rune(rest[0]))
+}
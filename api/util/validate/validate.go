@@ -2,7 +2,12 @@ package validate
 
 import (
 	"fmt"
+	"net/mail"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Returns true if the str has lower or equal number of chars than length.
@@ -49,3 +54,101 @@ func Username(username string) (bool, error) {
 
 	return !hasDisallowedChars, nil
 }
+
+// Returns true if email is a plain address like name@example.com, without a
+// display name.
+func Email(email string) bool {
+	if !stringLength(email, 254) {
+		return false
+	}
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email && strings.Contains(email, ".")
+}
+
+func Tag(tag string) bool {
+	if tag == "" || !stringLength(tag, 30) {
+		return false
+	}
+	return !strings.ContainsAny(tag, " \t\r\n#,")
+}
+
+// Returns true if rule is an RFC 5545 recurrence rule, without the "RRULE:"
+// prefix, that only uses the FREQ, INTERVAL, COUNT, UNTIL, BYDAY and
+// BYMONTHDAY parts.
+func Recurrence(rule string) bool {
+	if !stringLength(rule, 150) {
+		return false
+	}
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return false
+		}
+		if _, ok := parts[key]; ok {
+			return false
+		}
+		parts[key] = value
+	}
+
+	for key, value := range parts {
+		switch key {
+		case "FREQ":
+			if !slices.Contains([]string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}, value) {
+				return false
+			}
+		case "INTERVAL", "COUNT":
+			if n, err := strconv.Atoi(value); err != nil || n < 1 {
+				return false
+			}
+		case "UNTIL":
+			_, errDate := time.Parse("20060102", value)
+			_, errDateTime := time.Parse("20060102T150405Z", value)
+			if errDate != nil && errDateTime != nil {
+				return false
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				if !slices.Contains([]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}, day) {
+					return false
+				}
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return false
+				}
+			}
+		default:
+			return false
+		}
+	}
+
+	_, hasCount := parts["COUNT"]
+	_, hasUntil := parts["UNTIL"]
+	_, hasFreq := parts["FREQ"]
+	return hasFreq && !(hasCount && hasUntil)
+}
+
+// Returns true if name is an IANA time zone such as Europe/Berlin or UTC.
+func TimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|\d{3}))?$`)
+
+// Returns true if locale is a BCP 47 language tag such as en-US or de.
+func Locale(locale string) bool {
+	return localeRegex.MatchString(locale)
+}
+
+var DateFormats = []string{"YYYY-MM-DD", "DD.MM.YYYY", "DD/MM/YYYY", "MM/DD/YYYY"}
+
+func DateFormat(format string) bool {
+	return slices.Contains(DateFormats, format)
+}